@@ -5,6 +5,7 @@ import (
 	"os"
 	"path"
 	"runtime"
+	"strings"
 
 	"github.com/jinzhu/configor"
 )
@@ -15,6 +16,17 @@ type Config struct {
 	RefreshTokenConfig RefreshTokenConfig
 	KafkaConfig        KafkaConfig
 	MinioConfig        MinioConfig
+	ModerationConfig   ModerationConfig
+	RateLimitConfig    RateLimitConfig
+	UsernameConfig     UsernameConfig
+	EmailConfig        EmailConfig
+	ShutdownConfig     ShutdownConfig
+	HealthConfig       HealthConfig
+	AuthConfig         AuthConfig
+	KeyStoreConfig     KeyStoreConfig
+	KeyPublishConfig   KeyPublishConfig
+	ServiceTokenConfig ServiceTokenConfig
+	GuestConfig        GuestConfig
 }
 
 type AppConfig struct {
@@ -26,6 +38,9 @@ type AppConfig struct {
 	KeyRollingDurationInHours int    `env:"CONFIG__APP_CONFIG__KEY_ROLLING_DURATION_IN_HOURS" default:"1"`
 	InternalGraphQLURL        string `env:"INTERNAL_GRAPHQL_URL" default:"http://localhost:5001/graphql"`
 	JWTValiditySeconds        int    `env:"CONFIG__APP_CONFIG__JWT_VALIDITY_SECONDS" default:"900"` // 15 minutes.
+	PublicKeyWindow           int    `env:"CONFIG__APP_CONFIG__PUBLIC_KEY_WINDOW" default:"2"`      // Keys served at /.well-known/jwks.json, current one included.
+	SigningAlgorithm          string `env:"CONFIG__APP_CONFIG__SIGNING_ALGORITHM" default:"RS256"`  // RS256, ES256 or EdDSA.
+	AdminUserIDs              string `env:"CONFIG__APP_CONFIG__ADMIN_USER_IDS" default:""`          // Comma separated. Admins whatever roles are stored.
 }
 
 type DBConfig struct {
@@ -47,7 +62,18 @@ type KafkaConfig struct {
 	BootstrapServers  string `default:"localhost:9092" env:"KAFKA_BOOTSTRAP_SERVERS"`
 	Offset            string `default:"earliest" env:"KAFKA_OFFSET"`
 	Topic             string `default:"user-created" env:"KAFKA_TOPIC"`
+	EmailChangedTopic string `default:"auth.email-changed" env:"KAFKA_EMAIL_CHANGED_TOPIC"`
+	UserDeletedTopic  string `default:"user-deleted" env:"KAFKA_USER_DELETED_TOPIC"`
+	ConsumeTopics     string `default:"user-created" env:"KAFKA_CONSUME_TOPICS"` // Comma separated topics `eventing start` consumes by default.
 	ProducerTopic     string `default:"nil" env:"KAFKA_PRODUCER_TOPIC"`
+	MaxRetries        int    `default:"3" env:"KAFKA_MAX_RETRIES"`
+	DeadLetterTopic   string `default:"" env:"KAFKA_DEAD_LETTER_TOPIC"` // Defaults to <topic>-dlq.
+	SecurityProtocol  string `default:"" env:"KAFKA_SECURITY_PROTOCOL"` // e.g. SASL_SSL; plaintext when empty.
+	SaslMechanism     string `default:"" env:"KAFKA_SASL_MECHANISM"`    // e.g. PLAIN or SCRAM-SHA-512.
+	Username          string `default:"" env:"KAFKA_USERNAME"`
+	Password          string `default:"" env:"KAFKA_PASSWORD"`
+	ClientID          string `default:"" env:"KAFKA_CLIENT_ID"`
+	SessionTimeoutMs  int    `default:"0" env:"KAFKA_SESSION_TIMEOUT_MS"` // Broker default when 0.
 }
 
 type MinioConfig struct {
@@ -58,6 +84,76 @@ type MinioConfig struct {
 	Bucket          string `default:"anime" env:"MINIO_BUCKET"`
 }
 
+type ModerationConfig struct {
+	Moderator     string  `default:"allow-all" env:"MODERATION_MODERATOR"` // allow-all or rules.
+	RequireReview bool    `default:"false" env:"MODERATION_REQUIRE_REVIEW"`
+	MaxBytes      int64   `default:"5242880" env:"MODERATION_MAX_BYTES"` // 5 MB.
+	MinDimension  int     `default:"32" env:"MODERATION_MIN_DIMENSION"`
+	MaxAspect     float64 `default:"3" env:"MODERATION_MAX_ASPECT"`
+	BlockedHashes string  `default:"" env:"MODERATION_BLOCKED_HASHES"` // Comma separated SHA-256 hashes.
+}
+
+type RateLimitConfig struct {
+	UsernameCheckPerMinute int `default:"30" env:"RATE_LIMIT_USERNAME_CHECK_PER_MINUTE"`
+	UsernameCheckBurst     int `default:"10" env:"RATE_LIMIT_USERNAME_CHECK_BURST"`
+}
+
+type UsernameConfig struct {
+	ReleaseCooldownHours int `default:"720" env:"USERNAME_RELEASE_COOLDOWN_HOURS"` // 30 days before a released name can be claimed by someone else.
+	RenameIntervalHours  int `default:"720" env:"USERNAME_RENAME_INTERVAL_HOURS"`  // 30 days between renames.
+}
+
+type HealthConfig struct {
+	CheckTimeoutMs   int `default:"2000" env:"HEALTH_CHECK_TIMEOUT_MS"`
+	CacheMs          int `default:"5000" env:"HEALTH_CHECK_CACHE_MS"`   // How long a check result is reused by later probes.
+	MaxKeyAgeMinutes int `default:"0" env:"HEALTH_MAX_KEY_AGE_MINUTES"` // Liveness fails once the signing key is older, 3 rotation intervals when 0.
+}
+
+type ShutdownConfig struct {
+	TimeoutSeconds    int `default:"30" env:"SHUTDOWN_TIMEOUT_SECONDS"`    // Deadline for draining requests and closing connections.
+	DrainDelaySeconds int `default:"5" env:"SHUTDOWN_DRAIN_DELAY_SECONDS"` // How long /readyz fails before the server stops accepting connections.
+}
+
+type AuthConfig struct {
+	VerifyTokens     bool   `default:"false" env:"AUTH_VERIFY_TOKENS"` // Verify Authorization: Bearer tokens instead of only trusting gateway headers.
+	Strict           bool   `default:"false" env:"AUTH_STRICT"`        // Reject x-user-id headers that don't come with a verified token.
+	JWKSURL          string `default:"http://localhost:5001/.well-known/jwks.json" env:"AUTH_JWKS_URL"`
+	JWKSCacheSeconds int    `default:"300" env:"AUTH_JWKS_CACHE_SECONDS"`
+	AllowedPurposes  string `default:"login,internal" env:"AUTH_ALLOWED_PURPOSES"`                                                      // Comma separated token purposes accepted as a login.
+	TrustedProxies   string `default:"127.0.0.0/8,::1/128,10.0.0.0/8,172.16.0.0/12,192.168.0.0/16,fc00::/7" env:"AUTH_TRUSTED_PROXIES"` // Comma separated CIDRs whose x-remote-ip header is believed.
+}
+
+type KeyStoreConfig struct {
+	Type           string `default:"memory" env:"KEY_STORE_TYPE"` // memory, db or file.
+	FilePath       string `default:"signing-keys.enc" env:"KEY_STORE_FILE_PATH"`
+	EncryptionKey  string `default:"" env:"KEY_STORE_ENCRYPTION_KEY"`       // Encrypts private keys at rest, required for file and db.
+	AllowPlaintext bool   `default:"false" env:"KEY_STORE_ALLOW_PLAINTEXT"` // Lets db store private keys unencrypted when no encryption key is set.
+	LockTTLSeconds int    `default:"60" env:"KEY_STORE_LOCK_TTL_SECONDS"`   // How long a crashed rotation leader blocks the others.
+}
+
+type KeyPublishConfig struct {
+	AttemptTimeoutMs   int `default:"5000" env:"KEY_PUBLISH_ATTEMPT_TIMEOUT_MS"`
+	MaxAttempts        int `default:"5" env:"KEY_PUBLISH_MAX_ATTEMPTS"`
+	InitialBackoffMs   int `default:"200" env:"KEY_PUBLISH_INITIAL_BACKOFF_MS"` // Doubles after every failed attempt.
+	MaxBackoffMs       int `default:"5000" env:"KEY_PUBLISH_MAX_BACKOFF_MS"`
+	BreakerFailures    int `default:"5" env:"KEY_PUBLISH_BREAKER_FAILURES"`      // Consecutive failed attempts before publishing fails fast.
+	BreakerOpenSeconds int `default:"60" env:"KEY_PUBLISH_BREAKER_OPEN_SECONDS"` // How long it fails fast before trying again.
+}
+
+type ServiceTokenConfig struct {
+	Audiences         string `default:"" env:"SERVICE_TOKEN_AUDIENCES"` // Comma separated services tokens may be issued for, none when empty.
+	DefaultTTLSeconds int    `default:"60" env:"SERVICE_TOKEN_DEFAULT_TTL_SECONDS"`
+	MaxTTLSeconds     int    `default:"300" env:"SERVICE_TOKEN_MAX_TTL_SECONDS"`
+}
+
+type GuestConfig struct {
+	ProfileTTLHours int `default:"720" env:"GUEST_PROFILE_TTL_HOURS"` // Guest profiles expire this long after their last change.
+}
+
+type EmailConfig struct {
+	VerificationTTLHours int `default:"24" env:"EMAIL_VERIFICATION_TTL_HOURS"`
+}
+
 func LoadConfig() (*Config, error) {
 	var config Config
 	err := configor.
@@ -79,6 +175,19 @@ func LoadConfigOrPanic() *Config {
 	return config
 }
 
+// SplitList returns the items of a comma separated setting, trimmed and without blanks.
+func SplitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item != "" {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
+
 func getConfigLocation() string {
 	_, filename, _, _ := runtime.Caller(0) // nolint
 
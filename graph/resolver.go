@@ -3,7 +3,14 @@ package graph
 import (
 	"github.com/weeb-vip/user-service/config"
 	"github.com/weeb-vip/user-service/internal/jwt"
+	"github.com/weeb-vip/user-service/internal/ratelimit"
+	"github.com/weeb-vip/user-service/internal/services/audit"
+	"github.com/weeb-vip/user-service/internal/services/guests"
 	"github.com/weeb-vip/user-service/internal/services/image"
+	"github.com/weeb-vip/user-service/internal/services/moderation"
+	"github.com/weeb-vip/user-service/internal/services/roles"
+	"github.com/weeb-vip/user-service/internal/services/servicetokens"
+	"github.com/weeb-vip/user-service/internal/services/settings"
 	"github.com/weeb-vip/user-service/internal/services/users"
 )
 
@@ -12,8 +19,16 @@ import (
 // It serves as dependency injection for your app, add any dependencies you require here.
 
 type Resolver struct {
-	UserService  users.User
-	JwtTokenizer jwt.Tokenizer
-	Config       config.Config
-	ImageService *image.ImageService
+	UserService       users.User
+	JwtTokenizer      jwt.Tokenizer
+	Config            config.Config
+	ImageService      *image.ImageService
+	ModerationService moderation.Moderation
+	SettingsService   settings.Settings
+	RolesService      roles.Roles
+	GuestService      guests.Guests
+	AuditLogService   audit.AuditLog
+	ServiceTokens     servicetokens.ServiceTokens
+	// UsernameCheckLimiter throttles usernameAvailable per caller
+	UsernameCheckLimiter *ratelimit.Limiter
 }
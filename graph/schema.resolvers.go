@@ -25,7 +25,57 @@ func (r *mutationResolver) UpdateUserDetails(ctx context.Context, input model.Up
 
 // UploadProfileImage is the resolver for the UploadProfileImage field.
 func (r *mutationResolver) UploadProfileImage(ctx context.Context, image graphql.Upload) (*model.User, error) {
-	return resolvers.UploadProfileImage(ctx, r.UserService, r.ImageService, image)
+	return resolvers.UploadProfileImage(ctx, r.UserService, r.ImageService, r.ModerationService, image)
+}
+
+// UpdateSettings is the resolver for the UpdateSettings field.
+func (r *mutationResolver) UpdateSettings(ctx context.Context, input model.UpdateSettingsInput) (*model.UserSettings, error) {
+	return resolvers.UpdateSettings(ctx, r.SettingsService, r.GuestService, &input)
+}
+
+// UpdateGuestProfile is the resolver for the UpdateGuestProfile field.
+func (r *mutationResolver) UpdateGuestProfile(ctx context.Context, input model.UpdateGuestProfileInput) (*model.GuestProfile, error) {
+	return resolvers.UpdateGuestProfile(ctx, r.GuestService, &input)
+}
+
+// RequestEmailVerification is the resolver for the RequestEmailVerification field.
+func (r *mutationResolver) RequestEmailVerification(ctx context.Context) (bool, error) {
+	return resolvers.RequestEmailVerification(ctx, r.UserService)
+}
+
+// VerifyEmail is the resolver for the VerifyEmail field.
+func (r *mutationResolver) VerifyEmail(ctx context.Context, token string) (*model.User, error) {
+	return resolvers.VerifyEmail(ctx, r.UserService, token)
+}
+
+// ApproveProfileImage is the resolver for the ApproveProfileImage field.
+func (r *mutationResolver) ApproveProfileImage(ctx context.Context, id string) (*model.ProfileImage, error) {
+	return resolvers.ApproveProfileImage(ctx, r.ModerationService, id)
+}
+
+// RejectProfileImage is the resolver for the RejectProfileImage field.
+func (r *mutationResolver) RejectProfileImage(ctx context.Context, id string, reason *string) (*model.ProfileImage, error) {
+	return resolvers.RejectProfileImage(ctx, r.ModerationService, id, reason)
+}
+
+// IssueServiceToken is the resolver for the IssueServiceToken field.
+func (r *mutationResolver) IssueServiceToken(ctx context.Context, audience string, scopes []string, ttl *int) (*model.ServiceToken, error) {
+	return resolvers.IssueServiceToken(ctx, r.ServiceTokens, audience, scopes, ttl)
+}
+
+// UpgradeGuest is the resolver for the UpgradeGuest field.
+func (r *mutationResolver) UpgradeGuest(ctx context.Context, guestID string) (*model.UserSettings, error) {
+	return resolvers.UpgradeGuest(ctx, r.GuestService, guestID)
+}
+
+// AssignRole is the resolver for the AssignRole field.
+func (r *mutationResolver) AssignRole(ctx context.Context, userID string, role model.Role, reason *string) (*model.UserRoles, error) {
+	return resolvers.AssignRole(ctx, r.RolesService, userID, role, reason)
+}
+
+// RevokeRole is the resolver for the RevokeRole field.
+func (r *mutationResolver) RevokeRole(ctx context.Context, userID string, role model.Role, reason *string) (*model.UserRoles, error) {
+	return resolvers.RevokeRole(ctx, r.RolesService, userID, role, reason)
 }
 
 // UserDetails is the resolver for the UserDetails field.
@@ -33,6 +83,46 @@ func (r *queryResolver) UserDetails(ctx context.Context) (*model.User, error) {
 	return resolvers.GetUser(ctx, r.UserService)
 }
 
+// PendingProfileImages is the resolver for the pendingProfileImages field.
+func (r *queryResolver) PendingProfileImages(ctx context.Context, limit *int, offset *int) ([]*model.ProfileImage, error) {
+	return resolvers.ListPendingProfileImages(ctx, r.ModerationService, limit, offset)
+}
+
+// UsernameAvailable is the resolver for the usernameAvailable field.
+func (r *queryResolver) UsernameAvailable(ctx context.Context, username string, firstname *string, lastname *string) (*model.UsernameAvailability, error) {
+	return resolvers.UsernameAvailable(ctx, r.UserService, r.UsernameCheckLimiter, username, firstname, lastname)
+}
+
+// UserByUsername is the resolver for the userByUsername field.
+func (r *queryResolver) UserByUsername(ctx context.Context, username string) (*model.UsernameLookup, error) {
+	return resolvers.UserByUsername(ctx, r.UserService, username)
+}
+
+// UserByEmail is the resolver for the userByEmail field.
+func (r *queryResolver) UserByEmail(ctx context.Context, email string) (*model.User, error) {
+	return resolvers.UserByEmail(ctx, r.UserService, email)
+}
+
+// MyRoles is the resolver for the myRoles field.
+func (r *queryResolver) MyRoles(ctx context.Context) (*model.UserRoles, error) {
+	return resolvers.MyRoles(ctx, r.RolesService)
+}
+
+// MyAuditLog is the resolver for the myAuditLog field.
+func (r *queryResolver) MyAuditLog(ctx context.Context, limit *int, offset *int) ([]*model.AuditEntry, error) {
+	return resolvers.MyAuditLog(ctx, r.AuditLogService, limit, offset)
+}
+
+// AuditLog is the resolver for the auditLog field.
+func (r *queryResolver) AuditLog(ctx context.Context, userID *string, limit *int, offset *int) ([]*model.AuditEntry, error) {
+	return resolvers.AuditLog(ctx, r.AuditLogService, userID, limit, offset)
+}
+
+// GuestProfile is the resolver for the guestProfile field.
+func (r *queryResolver) GuestProfile(ctx context.Context) (*model.GuestProfile, error) {
+	return resolvers.GetGuestProfile(ctx, r.GuestService)
+}
+
 // Mutation returns generated.MutationResolver implementation.
 func (r *Resolver) Mutation() generated.MutationResolver { return &mutationResolver{r} }
 
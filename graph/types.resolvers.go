@@ -0,0 +1,28 @@
+package graph
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.78
+
+import (
+	"context"
+
+	"github.com/weeb-vip/user-service/graph/generated"
+	"github.com/weeb-vip/user-service/graph/model"
+	"github.com/weeb-vip/user-service/internal/resolvers"
+)
+
+// PendingProfileImage is the resolver for the pendingProfileImage field.
+func (r *userResolver) PendingProfileImage(ctx context.Context, obj *model.User) (*model.ProfileImage, error) {
+	return resolvers.GetPendingProfileImage(ctx, r.ModerationService, obj)
+}
+
+// Settings is the resolver for the settings field.
+func (r *userResolver) Settings(ctx context.Context, obj *model.User) (*model.UserSettings, error) {
+	return resolvers.GetUserSettings(ctx, r.SettingsService, obj)
+}
+
+// User returns generated.UserResolver implementation.
+func (r *Resolver) User() generated.UserResolver { return &userResolver{r} }
+
+type userResolver struct{ *Resolver }
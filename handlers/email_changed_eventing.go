@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+
+	"github.com/weeb-vip/user-service/internal/eventing"
+	"github.com/weeb-vip/user-service/internal/logger"
+	"github.com/weeb-vip/user-service/internal/services/users"
+	"github.com/weeb-vip/user-service/internal/services/users/email"
+)
+
+const EmailChangedEventType = "auth.email_changed"
+
+// EmailChangedPayload is version 1 of the auth.email_changed event.
+type EmailChangedPayload struct {
+	UserID string `json:"user_id"`
+	Email  string `json:"email"`
+}
+
+// newEmailChangedHandler copies a new address from the auth service. The auth service keys these
+// events by user, so a user's changes arrive in order. A change for a user we haven't seen
+// creates them, the same as user-created would.
+func newEmailChangedHandler(userService users.User) eventing.Handler {
+	return func(ctx context.Context, envelope eventing.Envelope) error {
+		log := logger.FromCtx(ctx)
+
+		var payload EmailChangedPayload
+		if err := envelope.Decode(&payload); err != nil {
+			return eventing.Permanent(eventing.ReasonMalformed, err)
+		}
+
+		address, err := email.Validate(payload.Email)
+		if err != nil {
+			return eventing.Permanent(eventing.ReasonRejected, err)
+		}
+
+		user, created, err := userService.EnsureUser(ctx, payload.UserID, &address, "")
+		if err != nil {
+			return err
+		}
+		if created || (user.Email != nil && *user.Email == address) {
+			return nil
+		}
+
+		_, err = userService.UpdateUser(ctx, payload.UserID, nil, nil, nil, nil, &address)
+		var userErr *users.Error
+		if errors.As(err, &userErr) && (userErr.Code == users.UserErrorEmailTaken || userErr.Code == users.UserErrorEmailInvalid) {
+			return eventing.Permanent(eventing.ReasonRejected, err)
+		}
+		if err != nil {
+			log.Error().Err(err).Str("event_id", envelope.EventID).Msg("Failed to change email")
+			return err
+		}
+
+		return nil
+	}
+}
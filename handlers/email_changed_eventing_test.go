@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/weeb-vip/user-service/internal/db"
+	"github.com/weeb-vip/user-service/internal/eventing"
+	"github.com/weeb-vip/user-service/internal/services/users"
+	"github.com/weeb-vip/user-service/internal/services/users/models"
+	"github.com/weeb-vip/user-service/mocks"
+)
+
+func TestEmailChangedHandler(t *testing.T) {
+	oldAddress, newAddress := "old@example.com", "new@example.com"
+
+	tests := []struct {
+		name    string
+		payload EmailChangedPayload
+		expect  func(userService *mocks.MockUser)
+		reason  string
+		wantErr bool
+	}{
+		{
+			name:    "email is updated",
+			payload: EmailChangedPayload{UserID: "1", Email: "new@EXAMPLE.com"},
+			expect: func(userService *mocks.MockUser) {
+				userService.EXPECT().EnsureUser(gomock.Any(), "1", &newAddress, "").
+					Return(&models.User{BaseModel: db.BaseModel{ID: "1"}, Email: &oldAddress}, false, nil)
+				userService.EXPECT().UpdateUser(gomock.Any(), "1", nil, nil, nil, nil, &newAddress).
+					Return(&models.User{BaseModel: db.BaseModel{ID: "1"}, Email: &newAddress}, nil)
+			},
+		},
+		{
+			name:    "redelivered event is a no-op",
+			payload: EmailChangedPayload{UserID: "1", Email: newAddress},
+			expect: func(userService *mocks.MockUser) {
+				userService.EXPECT().EnsureUser(gomock.Any(), "1", &newAddress, "").
+					Return(&models.User{BaseModel: db.BaseModel{ID: "1"}, Email: &newAddress}, false, nil)
+			},
+		},
+		{
+			name:    "unknown user is created with the email",
+			payload: EmailChangedPayload{UserID: "1", Email: newAddress},
+			expect: func(userService *mocks.MockUser) {
+				userService.EXPECT().EnsureUser(gomock.Any(), "1", &newAddress, "").
+					Return(&models.User{BaseModel: db.BaseModel{ID: "1"}, Email: &newAddress}, true, nil)
+			},
+		},
+		{
+			name:    "invalid email is rejected",
+			payload: EmailChangedPayload{UserID: "1", Email: "not-an-email"},
+			expect:  func(userService *mocks.MockUser) {},
+			reason:  eventing.ReasonRejected,
+		},
+		{
+			name:    "email held by someone else is rejected",
+			payload: EmailChangedPayload{UserID: "1", Email: newAddress},
+			expect: func(userService *mocks.MockUser) {
+				userService.EXPECT().EnsureUser(gomock.Any(), "1", &newAddress, "").
+					Return(&models.User{BaseModel: db.BaseModel{ID: "1"}, Email: &oldAddress}, false, nil)
+				userService.EXPECT().UpdateUser(gomock.Any(), "1", nil, nil, nil, nil, &newAddress).
+					Return(nil, &users.Error{Code: users.UserErrorEmailTaken, Message: "email is already in use"})
+			},
+			reason: eventing.ReasonRejected,
+		},
+		{
+			name:    "service errors are retried",
+			payload: EmailChangedPayload{UserID: "1", Email: newAddress},
+			expect: func(userService *mocks.MockUser) {
+				userService.EXPECT().EnsureUser(gomock.Any(), "1", &newAddress, "").Return(nil, false, errors.New("database error"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userService := mocks.NewMockUser(gomock.NewController(t))
+			tt.expect(userService)
+
+			data, err := json.Marshal(tt.payload)
+			require.NoError(t, err)
+
+			err = newEmailChangedHandler(userService)(context.Background(), eventing.Envelope{Data: data})
+
+			var permanent *eventing.PermanentError
+			switch {
+			case tt.reason != "":
+				require.True(t, errors.As(err, &permanent), "expected a permanent error, got %v", err)
+				assert.Equal(t, tt.reason, permanent.Reason)
+			case tt.wantErr:
+				require.Error(t, err)
+				assert.False(t, errors.As(err, &permanent))
+			default:
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
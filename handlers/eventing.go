@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ThatCatDev/ep/v2/drivers"
+	epKafka "github.com/ThatCatDev/ep/v2/drivers/kafka"
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+	"github.com/weeb-vip/user-service/config"
+	"github.com/weeb-vip/user-service/internal/db"
+	"github.com/weeb-vip/user-service/internal/eventing"
+	"github.com/weeb-vip/user-service/internal/lifecycle"
+	"github.com/weeb-vip/user-service/internal/logger"
+	"github.com/weeb-vip/user-service/internal/services/users"
+)
+
+// NewConsumerRegistry registers a consumer for every topic this service understands. Topics left
+// empty in cfg are skipped, so a deployment only needs the topics it consumes. Add new topics
+// here, with a schema for their event type in internal/eventing/schemas.
+func NewConsumerRegistry(cfg config.KafkaConfig, userService users.User) (*eventing.Registry, error) {
+	registry := eventing.NewRegistry()
+	for _, consumer := range []eventing.Consumer{
+		{Topic: cfg.Topic, EventType: UserCreatedEventType, Handle: newUserCreatedHandler(userService)},
+		{Topic: cfg.EmailChangedTopic, EventType: EmailChangedEventType, Handle: newEmailChangedHandler(userService)},
+		{Topic: cfg.UserDeletedTopic, EventType: UserDeletedEventType, Handle: newUserDeletedHandler(userService)},
+	} {
+		if consumer.Topic == "" {
+			continue
+		}
+		if err := registry.Register(consumer); err != nil {
+			return nil, fmt.Errorf("failed to register %s consumer: %w", consumer.EventType, err)
+		}
+	}
+
+	return registry, nil
+}
+
+// Eventing consumes topics until ctx is cancelled or a consumer fails, then shuts down through
+// shutdown. Events being handled when ctx is cancelled are finished and committed first.
+func Eventing(ctx context.Context, topics []string, shutdown *lifecycle.Lifecycle) error {
+	cfg, _ := config.LoadConfig()
+	log := logger.FromCtx(ctx)
+
+	driver := epKafka.NewKafkaDriver(eventing.DriverConfig(cfg.KafkaConfig))
+	defer func(driver drivers.Driver[*kafka.Message]) {
+		err := driver.Close()
+		if err != nil {
+			log.Error().Err(err).Msg("Error closing Kafka driver")
+		} else {
+			log.Info().Msg("Kafka driver closed successfully")
+		}
+	}(driver)
+	shutdown.OnShutdown("database", func(context.Context) error {
+		return db.Close()
+	})
+
+	registry, err := NewConsumerRegistry(cfg.KafkaConfig, users.NewUserService())
+	if err != nil {
+		log.Error().Err(err).Msg("Error registering consumers")
+		return errors.Join(err, shutdown.Shutdown(context.WithoutCancel(ctx)))
+	}
+	consumed := make(chan error, 1)
+	go func() {
+		consumed <- registry.Run(ctx, driver, topics, cfg.KafkaConfig)
+	}()
+
+	select {
+	case err := <-consumed:
+		if ctx.Err() == nil {
+			// A consumer failed on its own
+			log.Error().Err(err).Msg("Error consuming messages")
+			return errors.Join(err, shutdown.Shutdown(context.WithoutCancel(ctx)))
+		}
+		consumed <- err
+	case <-ctx.Done():
+	}
+
+	log.Info().Msg("Shutting down consumers...")
+	shutdown.OnShutdown("kafka consumers", func(ctx context.Context) error {
+		select {
+		case err := <-consumed:
+			return err
+		case <-ctx.Done():
+			return fmt.Errorf("consumers still handling events: %w", ctx.Err())
+		}
+	})
+
+	return shutdown.Shutdown(context.WithoutCancel(ctx))
+}
@@ -0,0 +1,26 @@
+package handlers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/weeb-vip/user-service/config"
+)
+
+func TestNewConsumerRegistry(t *testing.T) {
+	t.Run("skips topics that are not configured", func(t *testing.T) {
+		registry, err := NewConsumerRegistry(config.KafkaConfig{Topic: "user-created"}, nil)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"user-created"}, registry.Topics())
+
+		topics, err := registry.ParseTopics("user-created")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"user-created"}, topics)
+	})
+
+	t.Run("reports topics shared by two consumers", func(t *testing.T) {
+		_, err := NewConsumerRegistry(config.KafkaConfig{Topic: "users", EmailChangedTopic: "users"}, nil)
+		assert.ErrorContains(t, err, "already has a consumer")
+	})
+}
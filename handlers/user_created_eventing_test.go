@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/weeb-vip/user-service/internal/db"
+	"github.com/weeb-vip/user-service/internal/eventing"
+	"github.com/weeb-vip/user-service/internal/services/users/models"
+	"github.com/weeb-vip/user-service/mocks"
+)
+
+func TestUserCreatedHandler(t *testing.T) {
+	address := "a@example.com"
+	user := &models.User{BaseModel: db.BaseModel{ID: "1"}}
+
+	tests := []struct {
+		name    string
+		payload Payload
+		expect  func(userService *mocks.MockUser)
+		wantErr bool
+	}{
+		{
+			name:    "email and locale are passed on",
+			payload: Payload{UserID: "1", Email: " a@EXAMPLE.com ", Locale: "pt-BR"},
+			expect: func(userService *mocks.MockUser) {
+				userService.EXPECT().EnsureUser(gomock.Any(), "1", &address, "pt-BR").Return(user, true, nil)
+			},
+		},
+		{
+			name:    "invalid email is dropped",
+			payload: Payload{UserID: "1", Email: "not-an-email"},
+			expect: func(userService *mocks.MockUser) {
+				userService.EXPECT().EnsureUser(gomock.Any(), "1", nil, "").Return(user, true, nil)
+			},
+		},
+		{
+			name:    "redelivered event succeeds",
+			payload: Payload{UserID: "1"},
+			expect: func(userService *mocks.MockUser) {
+				userService.EXPECT().EnsureUser(gomock.Any(), "1", nil, "").Return(user, false, nil)
+			},
+		},
+		{
+			name:    "service errors are retried",
+			payload: Payload{UserID: "1"},
+			expect: func(userService *mocks.MockUser) {
+				userService.EXPECT().EnsureUser(gomock.Any(), "1", nil, "").Return(nil, false, errors.New("database error"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userService := mocks.NewMockUser(gomock.NewController(t))
+			tt.expect(userService)
+
+			data, err := json.Marshal(tt.payload)
+			require.NoError(t, err)
+
+			err = newUserCreatedHandler(userService)(context.Background(), eventing.Envelope{Data: data})
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
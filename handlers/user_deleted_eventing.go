@@ -0,0 +1,35 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/weeb-vip/user-service/internal/eventing"
+	"github.com/weeb-vip/user-service/internal/logger"
+	"github.com/weeb-vip/user-service/internal/services/users"
+)
+
+const UserDeletedEventType = "user.deleted"
+
+// UserDeletedPayload is version 1 of the user.deleted event.
+type UserDeletedPayload struct {
+	UserID string `json:"user_id"`
+}
+
+// newUserDeletedHandler deletes the user. Deleting is idempotent, so redelivery is harmless.
+func newUserDeletedHandler(userService users.User) eventing.Handler {
+	return func(ctx context.Context, envelope eventing.Envelope) error {
+		log := logger.FromCtx(ctx)
+
+		var payload UserDeletedPayload
+		if err := envelope.Decode(&payload); err != nil {
+			return eventing.Permanent(eventing.ReasonMalformed, err)
+		}
+
+		if err := userService.DeleteUser(ctx, payload.UserID); err != nil {
+			log.Error().Err(err).Str("event_id", envelope.EventID).Msg("Failed to delete user")
+			return err
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,80 @@
+package requestinfo
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/weeb-vip/user-service/internal/jwt"
+	"github.com/weeb-vip/user-service/internal/logger"
+)
+
+// AuthHandler fills RequestInfo from a verified Authorization: Bearer token instead of trusting
+// identity headers. Requests without a token fall back to the x-user-id headers set by the gateway,
+// unless strict is set, in which case those headers are refused and only anonymous requests
+// get through without a token. A token that fails verification, or whose purpose is not one of
+// allowedPurposes, is always refused: tokens such as email verification links are signed by the
+// same keys but must never act as a login.
+func AuthHandler(verifier jwt.Verifier, strict bool, allowedPurposes []string) func(http.Handler) http.Handler {
+	return func(handler http.Handler) http.Handler {
+		return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			info := getRequestInfoFromRequest(request)
+
+			token, hasToken := getBearerToken(request)
+			switch {
+			case hasToken:
+				claims, err := verifier.Verify(request.Context(), token)
+				if err != nil {
+					log := logger.FromCtx(request.Context())
+					log.Info().Err(err).Msg("rejected bearer token")
+					unauthorized(writer, `error="invalid_token"`)
+					return
+				}
+				if !slices.Contains(allowedPurposes, claims.Purpose) {
+					log := logger.FromCtx(request.Context())
+					log.Info().Str("purpose", claims.Purpose).Msg("rejected bearer token for purpose")
+					unauthorized(writer, `error="invalid_token"`)
+					return
+				}
+				info = withClaims(info, claims, token)
+			case strict && info.UserID != nil:
+				unauthorized(writer, `error="invalid_request"`)
+				return
+			}
+
+			ctx := addRequestInfoToContext(request.Context(), info)
+			handler.ServeHTTP(writer, request.WithContext(ctx))
+		})
+	}
+}
+
+func withClaims(info RequestInfo, claims *jwt.VerifiedClaims, token string) RequestInfo {
+	info.UserID = nil
+	info.Purpose = nil
+	if claims.Subject != "" {
+		info.UserID = &claims.Subject
+	}
+	if claims.Purpose != "" {
+		info.Purpose = &claims.Purpose
+	}
+	info.Scopes = claims.Scopes
+	info.RawToken = &token
+	info.UserType = getUserType(info.UserID)
+	info.Verified = true
+
+	return info
+}
+
+func getBearerToken(request *http.Request) (string, bool) {
+	scheme, token, found := strings.Cut(request.Header.Get("Authorization"), " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") || strings.TrimSpace(token) == "" {
+		return "", false
+	}
+
+	return strings.TrimSpace(token), true
+}
+
+func unauthorized(writer http.ResponseWriter, reason string) {
+	writer.Header().Set("WWW-Authenticate", "Bearer "+reason)
+	http.Error(writer, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+}
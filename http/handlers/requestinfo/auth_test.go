@@ -0,0 +1,118 @@
+package requestinfo_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/weeb-vip/user-service/http/handlers/requestinfo"
+	"github.com/weeb-vip/user-service/internal/jwt"
+)
+
+type fakeVerifier map[string]jwt.VerifiedClaims
+
+func (f fakeVerifier) Verify(_ context.Context, token string) (*jwt.VerifiedClaims, error) {
+	claims, found := f[token]
+	if !found {
+		return nil, errors.New("invalid token")
+	}
+
+	return &claims, nil
+}
+
+func TestAuthHandler(t *testing.T) {
+	verifier := fakeVerifier{
+		"good-token":         {Subject: "user_verified", Purpose: "login"},
+		"scoped-token":       {Subject: "user_verified", Purpose: "internal", Scopes: []string{"profile:read"}},
+		"verification-token": {Subject: "user_verified", Purpose: "email_verification"},
+		"service-token":      {Subject: "user_verified", Purpose: "service", Scopes: []string{"profile:read"}},
+		"purposeless-token":  {Subject: "user_verified"},
+	}
+
+	serve := func(strict bool, headers map[string]string) (*httptest.ResponseRecorder, *requestinfo.RequestInfo) {
+		req := httptest.NewRequest(http.MethodPost, "/graphql", nil)
+		for key, value := range headers {
+			req.Header.Set(key, value)
+		}
+
+		var info *requestinfo.RequestInfo
+		recorder := httptest.NewRecorder()
+		requestinfo.AuthHandler(verifier, strict, []string{"login", "internal"})(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			details := requestinfo.FromContext(request.Context())
+			info = &details
+		})).ServeHTTP(recorder, req)
+
+		return recorder, info
+	}
+
+	t.Run("verified token overrides identity headers", func(t *testing.T) {
+		_, info := serve(false, map[string]string{
+			"Authorization": "Bearer good-token",
+			"x-user-id":     "user_spoofed",
+			"x-remote-ip":   "10.0.0.1",
+		})
+		assert.Equal(t, "user_verified", *info.UserID)
+		assert.Equal(t, "login", *info.Purpose)
+		assert.Equal(t, "good-token", *info.RawToken)
+		assert.Equal(t, requestinfo.UserTypeUser, *info.UserType)
+		assert.Equal(t, "10.0.0.1", *info.RemoteIP)
+		assert.Nil(t, info.Scopes)
+	})
+	t.Run("verified internal token is internal", func(t *testing.T) {
+		_, info := serve(false, map[string]string{"Authorization": "Bearer scoped-token"})
+		assert.True(t, info.Verified)
+		assert.True(t, info.IsInternal())
+	})
+	t.Run("purpose header alone is not internal", func(t *testing.T) {
+		_, info := serve(false, map[string]string{
+			"x-user-id":       "user_victim",
+			"x-token-purpose": "internal",
+		})
+		assert.Equal(t, "internal", *info.Purpose)
+		assert.False(t, info.Verified)
+		assert.False(t, info.IsInternal())
+	})
+	t.Run("token scopes are passed on", func(t *testing.T) {
+		_, info := serve(false, map[string]string{"Authorization": "Bearer scoped-token"})
+		assert.Equal(t, []string{"profile:read"}, info.Scopes)
+	})
+	t.Run("invalid token is rejected", func(t *testing.T) {
+		recorder, info := serve(false, map[string]string{"Authorization": "Bearer forged"})
+		assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+		assert.Contains(t, recorder.Header().Get("WWW-Authenticate"), "invalid_token")
+		assert.Nil(t, info)
+	})
+	t.Run("email verification token is not a login", func(t *testing.T) {
+		recorder, info := serve(false, map[string]string{"Authorization": "Bearer verification-token"})
+		assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+		assert.Contains(t, recorder.Header().Get("WWW-Authenticate"), "invalid_token")
+		assert.Nil(t, info)
+	})
+	t.Run("service token is not a login", func(t *testing.T) {
+		recorder, info := serve(false, map[string]string{"Authorization": "Bearer service-token"})
+		assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+		assert.Contains(t, recorder.Header().Get("WWW-Authenticate"), "invalid_token")
+		assert.Nil(t, info)
+	})
+	t.Run("token without a purpose is rejected", func(t *testing.T) {
+		recorder, info := serve(false, map[string]string{"Authorization": "Bearer purposeless-token"})
+		assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+		assert.Nil(t, info)
+	})
+	t.Run("headers are trusted without a token outside strict mode", func(t *testing.T) {
+		_, info := serve(false, map[string]string{"x-user-id": "user_gateway"})
+		assert.Equal(t, "user_gateway", *info.UserID)
+	})
+	t.Run("strict mode refuses identity headers without a token", func(t *testing.T) {
+		recorder, info := serve(true, map[string]string{"x-user-id": "user_gateway"})
+		assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+		assert.Nil(t, info)
+	})
+	t.Run("strict mode lets anonymous requests through", func(t *testing.T) {
+		_, info := serve(true, nil)
+		assert.Nil(t, info.UserID)
+	})
+}
@@ -0,0 +1,53 @@
+package requestinfo
+
+import (
+	"net"
+	"net/http"
+	"net/netip"
+)
+
+// ClientIPHandler makes RemoteIP the address of the connection's peer, unless that peer is one of
+// trustedProxies, in which case the x-remote-ip it forwarded is kept. Without it any caller could
+// choose their own RemoteIP, and with it their own rate limit bucket, by setting the header.
+// It must run inside Handler or AuthHandler.
+func ClientIPHandler(trustedProxies []netip.Prefix) func(http.Handler) http.Handler {
+	return func(handler http.Handler) http.Handler {
+		return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			info := FromContext(request.Context())
+
+			peer, ok := peerAddr(request)
+			if !ok {
+				info.RemoteIP = nil
+			} else if !isTrusted(peer, trustedProxies) || info.RemoteIP == nil {
+				address := peer.String()
+				info.RemoteIP = &address
+			}
+
+			handler.ServeHTTP(writer, request.WithContext(addRequestInfoToContext(request.Context(), info)))
+		})
+	}
+}
+
+func peerAddr(request *http.Request) (netip.Addr, bool) {
+	host, _, err := net.SplitHostPort(request.RemoteAddr)
+	if err != nil {
+		host = request.RemoteAddr
+	}
+
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return netip.Addr{}, false
+	}
+
+	return addr.Unmap(), true
+}
+
+func isTrusted(addr netip.Addr, trustedProxies []netip.Prefix) bool {
+	for _, prefix := range trustedProxies {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,44 @@
+package requestinfo_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/weeb-vip/user-service/http/handlers/requestinfo"
+)
+
+func TestClientIPHandler(t *testing.T) {
+	trusted := []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")}
+
+	serve := func(remoteAddr string, forwarded string) *string {
+		req := httptest.NewRequest(http.MethodPost, "/graphql", nil)
+		req.RemoteAddr = remoteAddr
+		if forwarded != "" {
+			req.Header.Set("x-remote-ip", forwarded)
+		}
+
+		var remoteIP *string
+		handler := requestinfo.ClientIPHandler(trusted)(http.HandlerFunc(func(_ http.ResponseWriter, request *http.Request) {
+			remoteIP = requestinfo.FromContext(request.Context()).RemoteIP
+		}))
+		requestinfo.Handler()(handler).ServeHTTP(httptest.NewRecorder(), req)
+
+		return remoteIP
+	}
+
+	t.Run("trusted proxy forwards the client address", func(t *testing.T) {
+		assert.Equal(t, "203.0.113.7", *serve("10.1.2.3:4000", "203.0.113.7"))
+	})
+	t.Run("untrusted peer cannot pick its address", func(t *testing.T) {
+		assert.Equal(t, "198.51.100.9", *serve("198.51.100.9:4000", "203.0.113.7"))
+	})
+	t.Run("trusted proxy without the header is the client", func(t *testing.T) {
+		assert.Equal(t, "10.1.2.3", *serve("10.1.2.3:4000", ""))
+	})
+	t.Run("unparseable peer has no address", func(t *testing.T) {
+		assert.Nil(t, serve("pipe", "203.0.113.7"))
+	})
+}
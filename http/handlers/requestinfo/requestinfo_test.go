@@ -42,6 +42,19 @@ func TestHandler(t *testing.T) {
 		requestinfo.Handler()(f).ServeHTTP(httptest.NewRecorder(), req)
 	})
 
+	t.Run("purpose header does not make the caller internal", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/graphql", nil)
+		req.Header.Add("x-user-id", "user_victim")
+		req.Header.Add("x-token-purpose", "internal")
+
+		f := http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			info := requestinfo.FromContext(request.Context())
+			assert.False(t, info.Verified)
+			assert.False(t, info.IsInternal())
+		})
+		requestinfo.Handler()(f).ServeHTTP(httptest.NewRecorder(), req)
+	})
+
 	t.Run("sets user type correctly", func(t *testing.T) {
 		req, _ := http.NewRequest("GET", "/graphql", nil)
 		req.Header.Add("x-user-id", "guest_something")
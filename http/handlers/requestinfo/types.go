@@ -1,5 +1,7 @@
 package requestinfo
 
+import "github.com/weeb-vip/user-service/internal/jwt"
+
 const (
 	UserTypeGuest UserType = "GUEST"
 	UserTypeUser  UserType = "USER"
@@ -12,6 +14,23 @@ type RequestInfo struct {
 	UserType  *UserType
 	RemoteIP  *string
 	UserAgent *string
+	// Scopes are the scopes of a verified bearer token. nil means the token did not narrow what
+	// the caller may do, or the caller was identified by headers.
+	Scopes []string
+	// Verified is set when UserID and Purpose come from a bearer token that AuthHandler verified,
+	// rather than from headers any caller can send.
+	Verified bool
+}
+
+// IsGuest reports whether the caller is a guest rather than a registered user.
+func (r RequestInfo) IsGuest() bool {
+	return r.UserType != nil && *r.UserType == UserTypeGuest
+}
+
+// IsInternal reports whether the caller holds a verified token issued to one of our own backends.
+// The x-token-purpose header alone is never enough.
+func (r RequestInfo) IsInternal() bool {
+	return r.Verified && r.Purpose != nil && *r.Purpose == jwt.PurposeInternal
 }
 
 type UserType string
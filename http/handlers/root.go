@@ -5,6 +5,10 @@ import (
 	"fmt"
 	"github.com/99designs/gqlgen/graphql"
 	"net/http"
+	"net/netip"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/99designs/gqlgen/graphql/handler"
 	"github.com/99designs/gqlgen/graphql/handler/apollotracing"
@@ -14,13 +18,31 @@ import (
 	"github.com/weeb-vip/user-service/config"
 	"github.com/weeb-vip/user-service/graph"
 	"github.com/weeb-vip/user-service/graph/generated"
+	"github.com/weeb-vip/user-service/graph/model"
 	"github.com/weeb-vip/user-service/http/handlers/logger"
 	"github.com/weeb-vip/user-service/http/handlers/metrics"
 	"github.com/weeb-vip/user-service/http/handlers/requestinfo"
 	"github.com/weeb-vip/user-service/http/middleware"
+	"github.com/weeb-vip/user-service/internal/events"
 	"github.com/weeb-vip/user-service/internal/jwt"
+	internalLogger "github.com/weeb-vip/user-service/internal/logger"
 	"github.com/weeb-vip/user-service/internal/measurements"
+	"github.com/weeb-vip/user-service/internal/ratelimit"
+	gqlResolvers "github.com/weeb-vip/user-service/internal/resolvers"
+	"github.com/weeb-vip/user-service/internal/services/audit"
+	auditModels "github.com/weeb-vip/user-service/internal/services/audit/models"
+	auditRepositories "github.com/weeb-vip/user-service/internal/services/audit/repositories"
+	"github.com/weeb-vip/user-service/internal/services/guests"
+	guestRepositories "github.com/weeb-vip/user-service/internal/services/guests/repositories"
 	"github.com/weeb-vip/user-service/internal/services/image"
+	imageRepositories "github.com/weeb-vip/user-service/internal/services/image/repositories"
+	"github.com/weeb-vip/user-service/internal/services/moderation"
+	moderationRepositories "github.com/weeb-vip/user-service/internal/services/moderation/repositories"
+	"github.com/weeb-vip/user-service/internal/services/roles"
+	rolesRepositories "github.com/weeb-vip/user-service/internal/services/roles/repositories"
+	"github.com/weeb-vip/user-service/internal/services/servicetokens"
+	"github.com/weeb-vip/user-service/internal/services/settings"
+	settingsRepositories "github.com/weeb-vip/user-service/internal/services/settings/repositories"
 	"github.com/weeb-vip/user-service/internal/services/users"
 	"github.com/weeb-vip/user-service/internal/storage/minio"
 )
@@ -33,17 +55,44 @@ func BuildRootHandler(tokenizer jwt.Tokenizer) http.Handler { // nolint
 		panic(err)
 	}
 
-	userService := users.NewUserService()
-	
+	publisher := events.NewPublisher(conf.APPConfig.APPName, conf.KafkaConfig)
+	userService := users.NewUserService(
+		users.WithUsernameConfig(conf.UsernameConfig),
+		users.WithEmailVerification(tokenizer, publisher, conf.EmailConfig),
+	)
+
 	// Initialize MinIO storage
 	minioStorage := minio.NewMinioStorage(conf.MinioConfig)
-	imageService := image.NewImageService(minioStorage)
-	
+	imageService := image.NewImageService(minioStorage, imageRepositories.GetImagesRepository())
+	moderationService := moderation.NewModerationService(
+		moderationRepositories.GetProfileImagesRepository(),
+		userService,
+		imageService,
+		moderation.NewModerator(conf.ModerationConfig),
+	)
+	settingsService := settings.NewSettingsService(settingsRepositories.GetUserSettingsRepository())
+	guestService := guests.NewGuestService(
+		guestRepositories.GetGuestProfilesRepository(),
+		settingsService,
+		userService,
+		publisher,
+		conf.GuestConfig,
+	)
+	rolesService := roles.NewRolesService(rolesRepositories.GetRolesRepository(), parseAdminUserIDs(conf.APPConfig.AdminUserIDs))
+	usernameCheckLimiter := ratelimit.NewPerMinute(conf.RateLimitConfig.UsernameCheckPerMinute, conf.RateLimitConfig.UsernameCheckBurst)
+
 	resolvers := &graph.Resolver{
-		UserService:  userService,
-		JwtTokenizer: tokenizer,
-		Config:       *conf,
-		ImageService: imageService,
+		UserService:          userService,
+		JwtTokenizer:         tokenizer,
+		Config:               *conf,
+		ImageService:         imageService,
+		ModerationService:    moderationService,
+		SettingsService:      settingsService,
+		RolesService:         rolesService,
+		GuestService:         guestService,
+		AuditLogService:      audit.NewAuditLogService(auditRepositories.GetAuditLogRepository()),
+		ServiceTokens:        servicetokens.NewServiceTokens(tokenizer, conf.ServiceTokenConfig),
+		UsernameCheckLimiter: usernameCheckLimiter,
 	}
 	cfg := generated.Config{Resolvers: resolvers}
 	cfg.Directives.Authenticated = func(ctx context.Context, obj interface{}, next graphql.Resolver) (res interface{}, err error) {
@@ -56,7 +105,49 @@ func BuildRootHandler(tokenizer jwt.Tokenizer) http.Handler { // nolint
 
 		return next(ctx)
 	}
+	cfg.Directives.Registered = func(ctx context.Context, obj interface{}, next graphql.Resolver) (res interface{}, err error) {
+		if requestinfo.FromContext(ctx).IsGuest() {
+			return nil, guests.NotAllowed()
+		}
+
+		return next(ctx)
+	}
+	cfg.Directives.HasRole = func(ctx context.Context, obj interface{}, next graphql.Resolver, role model.Role) (res interface{}, err error) {
+		return requireRole(ctx, rolesService, roles.Role(strings.ToLower(role.String())), next)
+	}
+	cfg.Directives.HasScope = func(ctx context.Context, obj interface{}, next graphql.Resolver, scope string) (res interface{}, err error) {
+		req := requestinfo.FromContext(ctx)
+
+		if req.UserID == nil || req.IsGuest() {
+			return nil, fmt.Errorf("Access denied")
+		}
+
+		// A token issued for a narrower purpose cannot use scopes it was not given, whatever the
+		// user's roles allow
+		if req.Scopes != nil && !slices.Contains(req.Scopes, scope) {
+			return nil, fmt.Errorf("Access denied")
+		}
+
+		allowed, err := rolesService.HasScope(ctx, *req.UserID, scope)
+		if err != nil {
+			return nil, err
+		}
+		if !allowed {
+			return nil, fmt.Errorf("Access denied")
+		}
+
+		return next(ctx)
+	}
+	cfg.Directives.Internal = func(ctx context.Context, obj interface{}, next graphql.Resolver) (res interface{}, err error) {
+		// Only a verified token counts, since the purpose header is set by whoever sends the request
+		if !requestinfo.FromContext(ctx).IsInternal() {
+			return nil, fmt.Errorf("Access denied")
+		}
+
+		return next(ctx)
+	}
 	srv := handler.NewDefaultServer(generated.NewExecutableSchema(cfg))
+	srv.SetErrorPresenter(gqlResolvers.ErrorPresenter)
 	srv.Use(apollotracing.Tracer{})
 	srv.Use(&middleware.GraphQLTracingExtension{})
 	srv.Use(&middleware.GraphQLMetricsExtension{})
@@ -69,5 +160,83 @@ func BuildRootHandler(tokenizer jwt.Tokenizer) http.Handler { // nolint
 
 	client := measurements.New()
 
-	return requestinfo.Handler()(logger.Handler()(metrics.Handler(client)(srv)))
+	clientIPHandler := requestinfo.ClientIPHandler(parseTrustedProxies(conf.AuthConfig.TrustedProxies))
+
+	return requestInfoHandler(conf.AuthConfig)(clientIPHandler(auditActorHandler(logger.Handler()(metrics.Handler(client)(srv)))))
+}
+
+// auditActorHandler attributes profile changes made while serving the request to its caller.
+func auditActorHandler(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		req := requestinfo.FromContext(request.Context())
+		if req.UserID == nil {
+			handler.ServeHTTP(writer, request)
+			return
+		}
+
+		ctx := auditModels.WithActor(request.Context(), auditModels.Actor{
+			ID:        *req.UserID,
+			IP:        req.RemoteIP,
+			UserAgent: req.UserAgent,
+		})
+		handler.ServeHTTP(writer, request.WithContext(ctx))
+	})
+}
+
+func requestInfoHandler(authConfig config.AuthConfig) func(http.Handler) http.Handler {
+	if !authConfig.VerifyTokens && !authConfig.Strict {
+		return requestinfo.Handler()
+	}
+
+	keys := jwt.NewRemoteKeySet(authConfig.JWKSURL, time.Duration(authConfig.JWKSCacheSeconds)*time.Second, nil)
+
+	return requestinfo.AuthHandler(jwt.NewVerifier(keys), authConfig.Strict, config.SplitList(authConfig.AllowedPurposes))
+}
+
+func requireRole(ctx context.Context, rolesService roles.Roles, role roles.Role, next graphql.Resolver) (interface{}, error) {
+	req := requestinfo.FromContext(ctx)
+
+	// Guests have no roles, not even the implicit user role
+	if req.UserID == nil || req.IsGuest() {
+		return nil, fmt.Errorf("Access denied")
+	}
+
+	allowed, err := rolesService.HasRole(ctx, *req.UserID, role)
+	if err != nil {
+		return nil, err
+	}
+	if !allowed {
+		return nil, fmt.Errorf("Access denied")
+	}
+
+	return next(ctx)
+}
+
+// parseTrustedProxies reads a list of CIDRs or single addresses, skipping any it can't parse.
+func parseTrustedProxies(value string) []netip.Prefix {
+	log := internalLogger.Get()
+	var prefixes []netip.Prefix
+	for _, item := range config.SplitList(value) {
+		if prefix, err := netip.ParsePrefix(item); err == nil {
+			prefixes = append(prefixes, prefix.Masked())
+			continue
+		}
+		addr, err := netip.ParseAddr(item)
+		if err != nil {
+			log.Warn().Str("proxy", item).Msg("ignoring invalid trusted proxy")
+			continue
+		}
+		prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+	}
+
+	return prefixes
+}
+
+func parseAdminUserIDs(value string) map[string]bool {
+	adminUserIDs := map[string]bool{}
+	for _, id := range config.SplitList(value) {
+		adminUserIDs[id] = true
+	}
+
+	return adminUserIDs
 }
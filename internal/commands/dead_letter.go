@@ -0,0 +1,140 @@
+package commands
+
+import (
+	"errors"
+
+	epKafka "github.com/ThatCatDev/ep/v2/drivers/kafka"
+	"github.com/spf13/cobra"
+	"github.com/weeb-vip/user-service/config"
+	"github.com/weeb-vip/user-service/internal/eventing"
+)
+
+func configureDeadLetterCommand(eventingCmd *cobra.Command) {
+	var dlqCmd = &cobra.Command{
+		Use:   "dlq",
+		Short: "inspect and replay dead-lettered events",
+	}
+
+	var listCmd = &cobra.Command{
+		Use:   "list",
+		Short: "list dead-lettered events",
+		RunE:  listDeadLetters,
+	}
+
+	var replayCmd = &cobra.Command{
+		Use:   "replay",
+		Short: "re-inject dead-lettered events into the topic they failed on",
+		Long: "Re-injects the selected events into the topic they failed on. Kafka can't delete them " +
+			"from the dead-letter topic, so replayed events stay listed there.",
+		RunE: replayDeadLetters,
+	}
+
+	for _, cmd := range []*cobra.Command{listCmd, replayCmd} {
+		cmd.Flags().String("topic", "", "consumed topic whose dead letters to read (default from config)")
+		cmd.Flags().String("filter", "", "comma separated field=value or field~value terms; fields are "+
+			"key, partition, offset, error, topic, header.<name> and payload.<name>")
+		cmd.Flags().Int("limit", 0, "stop after this many matching events (0 for no limit)")
+	}
+	replayCmd.Flags().Bool("all", false, "replay every event when no filter is given")
+	replayCmd.Flags().Bool("dry-run", false, "only list the events that would be replayed")
+
+	eventingCmd.AddCommand(dlqCmd)
+	dlqCmd.AddCommand(listCmd, replayCmd)
+}
+
+// deadLetterArgs resolves the flags shared by the dlq commands.
+func deadLetterArgs(cmd *cobra.Command, cfg *config.Config) (topic string, deadLetterTopic string, filter *eventing.Filter, limit int, err error) {
+	topic, _ = cmd.Flags().GetString("topic")
+	if topic == "" {
+		topic = cfg.KafkaConfig.Topic
+	}
+	deadLetterTopic = eventing.DeadLetterTopic(topic, cfg.KafkaConfig.DeadLetterTopic)
+
+	expression, _ := cmd.Flags().GetString("filter")
+	filter, err = eventing.ParseFilter(expression)
+	limit, _ = cmd.Flags().GetInt("limit")
+
+	return topic, deadLetterTopic, filter, limit, err
+}
+
+func listDeadLetters(cmd *cobra.Command, args []string) error {
+	cfg := config.LoadConfigOrPanic()
+
+	_, deadLetterTopic, filter, limit, err := deadLetterArgs(cmd, cfg)
+	if err != nil {
+		return err
+	}
+
+	letters, err := eventing.ReadDeadLetters(cmd.Context(), eventing.ConfigMap(cfg.KafkaConfig), deadLetterTopic, filter, limit)
+	if err != nil {
+		return err
+	}
+
+	for _, letter := range letters {
+		printDeadLetter(cmd, letter)
+	}
+	cmd.Printf("%d dead-lettered events in %s\n", len(letters), deadLetterTopic)
+
+	return nil
+}
+
+func replayDeadLetters(cmd *cobra.Command, args []string) error {
+	cfg := config.LoadConfigOrPanic()
+
+	topic, deadLetterTopic, filter, limit, err := deadLetterArgs(cmd, cfg)
+	if err != nil {
+		return err
+	}
+
+	expression, _ := cmd.Flags().GetString("filter")
+	all, _ := cmd.Flags().GetBool("all")
+	if expression == "" && !all {
+		return errors.New("pass --filter to select events, or --all to replay every one")
+	}
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+	letters, err := eventing.ReadDeadLetters(cmd.Context(), eventing.ConfigMap(cfg.KafkaConfig), deadLetterTopic, filter, limit)
+	if err != nil {
+		return err
+	}
+
+	driver := epKafka.NewKafkaDriver(eventing.DriverConfig(cfg.KafkaConfig))
+	defer driver.Close()
+
+	replayed := 0
+	for _, letter := range letters {
+		target := letter.OriginalTopic()
+		if target == "" {
+			target = topic
+		}
+
+		printDeadLetter(cmd, letter)
+		if dryRun {
+			continue
+		}
+		if err := driver.Produce(cmd.Context(), target, eventing.ReplayMessage(letter)); err != nil {
+			return err
+		}
+		replayed++
+	}
+
+	if dryRun {
+		cmd.Printf("%d events would be replayed\n", len(letters))
+	} else {
+		cmd.Printf("Replayed %d events\n", replayed)
+	}
+
+	return nil
+}
+
+func printDeadLetter(cmd *cobra.Command, letter eventing.DeadLetter) {
+	cmd.Printf("%d/%d\tkey=%s\tattempts=%d\tfailed_at=%s\terror=%s\n",
+		letter.Partition,
+		letter.Offset,
+		letter.Key,
+		letter.Attempts(),
+		letter.Headers[eventing.HeaderFailedAt],
+		letter.Error(),
+	)
+	cmd.Printf("\t%s\n", letter.Value)
+}
@@ -2,6 +2,7 @@ package commands
 
 import (
 	"errors"
+	"time"
 
 	"github.com/weeb-vip/user-service/config"
 
@@ -9,6 +10,8 @@ import (
 
 	"github.com/weeb-vip/user-service/internal/db"
 	"github.com/weeb-vip/user-service/internal/migrations"
+	guestRepositories "github.com/weeb-vip/user-service/internal/services/guests/repositories"
+	"github.com/weeb-vip/user-service/internal/services/users/repositories"
 
 	"github.com/spf13/cobra"
 )
@@ -23,7 +26,12 @@ func configureMigrateCommand(rootCmd *cobra.Command) {
 		Short: "migrate database",
 		RunE:  migrateDB,
 	}
-	dbCommand.AddCommand(migrateCmd)
+	purgeGuestsCmd := &cobra.Command{
+		Use:   "purge-guests",
+		Short: "delete expired guest profiles",
+		RunE:  purgeGuests,
+	}
+	dbCommand.AddCommand(migrateCmd, purgeGuestsCmd)
 
 	rootCmd.AddCommand(dbCommand)
 }
@@ -50,5 +58,37 @@ func migrateDB(cmd *cobra.Command, _ []string) error {
 		return err
 	}
 
+	// username_normalized and email_normalized need Go-side normalization, so they are backfilled
+	// here rather than in SQL
+	updated, conflicts, err := repositories.GetUsersRepository().BackfillNormalizedUsernames(cmd.Context())
+	if err != nil {
+		return err
+	}
+	cmd.Printf("Normalized %d usernames\n", updated)
+	for _, userID := range conflicts {
+		cmd.PrintErrf("Username of user %s collides with an older account and was left unnormalized\n", userID)
+	}
+
+	updated, conflicts, err = repositories.GetUsersRepository().BackfillNormalizedEmails(cmd.Context())
+	if err != nil {
+		return err
+	}
+	cmd.Printf("Normalized %d emails\n", updated)
+	for _, userID := range conflicts {
+		cmd.PrintErrf("Email of user %s collides with an older account and was left unnormalized\n", userID)
+	}
+
+	return nil
+}
+
+// purgeGuests deletes guest profiles past their expiry. Expired profiles are already ignored on
+// read, so this only reclaims space and can run as rarely as suits.
+func purgeGuests(cmd *cobra.Command, _ []string) error {
+	deleted, err := guestRepositories.GetGuestProfilesRepository().DeleteExpiredGuestProfiles(cmd.Context(), time.Now())
+	if err != nil {
+		return err
+	}
+	cmd.Printf("Deleted %d expired guest profiles\n", deleted)
+
 	return nil
 }
@@ -0,0 +1,41 @@
+package commands
+
+import (
+	"context"
+
+	"github.com/weeb-vip/user-service/config"
+	"github.com/weeb-vip/user-service/internal/lifecycle"
+	"github.com/weeb-vip/user-service/internal/logger"
+	"github.com/weeb-vip/user-service/tracing"
+)
+
+// startRun sets up logging and tracing for a long-running command. The returned context is
+// cancelled on SIGINT or SIGTERM, after which the command should call Shutdown on the lifecycle.
+// Traces are flushed by the lifecycle's last step.
+func startRun(cfg *config.Config) (context.Context, *lifecycle.Lifecycle, context.CancelFunc) {
+	// Initialize logger with environment
+	logger.Logger(
+		logger.WithServerName("user-service"),
+		logger.WithVersion("1.0.0"),
+		logger.WithEnvironment(cfg.APPConfig.Env),
+	)
+
+	ctx, stop := lifecycle.SignalContext(context.Background())
+	shutdown := lifecycle.New(cfg.ShutdownConfig)
+
+	// Initialize tracing
+	tracedCtx, err := tracing.InitTracing(ctx)
+	if err != nil {
+		log := logger.FromCtx(ctx)
+		log.Error().Err(err).Msg("Failed to initialize tracing")
+		// Continue without tracing if initialization fails
+		return ctx, shutdown, stop
+	}
+
+	// Registered first so it runs last, after everything else had a chance to finish its spans
+	shutdown.OnShutdown("tracing", tracing.Shutdown)
+	log := logger.FromCtx(tracedCtx)
+	log.Info().Msg("Tracing initialized successfully")
+
+	return tracedCtx, shutdown, stop
+}
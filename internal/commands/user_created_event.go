@@ -1,12 +1,8 @@
 package commands
 
 import (
-	"context"
-
 	"github.com/weeb-vip/user-service/config"
 	"github.com/weeb-vip/user-service/handlers"
-	"github.com/weeb-vip/user-service/internal/logger"
-	"github.com/weeb-vip/user-service/tracing"
 
 	"github.com/spf13/cobra"
 )
@@ -19,44 +15,52 @@ func configureUserCreatedEventCommand(rootCmd *cobra.Command) {
 
 	var userCreatedStartCmd = &cobra.Command{
 		Use:   "user-created",
-		Short: "start listening to events",
+		Short: "start listening to user-created events",
 		RunE:  startUserCreatedEventing,
 	}
 
+	var startCmd = &cobra.Command{
+		Use:   "start",
+		Short: "start listening to events on one or more topics",
+		RunE:  startEventing,
+	}
+	startCmd.Flags().String("topics", "", "comma separated topics to consume (default from config)")
+
 	rootCmd.AddCommand(eventingCmd)
-	eventingCmd.AddCommand(userCreatedStartCmd)
+	eventingCmd.AddCommand(userCreatedStartCmd, startCmd)
+	configureDeadLetterCommand(eventingCmd)
 }
 
 func startUserCreatedEventing(cmd *cobra.Command, args []string) error {
-	// Load config to get environment
 	cfg := config.LoadConfigOrPanic()
 
-	// Initialize logger with environment
-	logger.Logger(
-		logger.WithServerName("user-service"),
-		logger.WithVersion("1.0.0"),
-		logger.WithEnvironment(cfg.APPConfig.Env),
-	)
+	return runEventing(cfg, []string{cfg.KafkaConfig.Topic})
+}
+
+func startEventing(cmd *cobra.Command, args []string) error {
+	cfg := config.LoadConfigOrPanic()
 
-	// Initialize tracing
-	ctx := context.Background()
-	tracedCtx, err := tracing.InitTracing(ctx)
+	list, _ := cmd.Flags().GetString("topics")
+	if list == "" {
+		list = cfg.KafkaConfig.ConsumeTopics
+	}
+	// The handlers aren't needed to check the topics, so no user service is built here
+	registry, err := handlers.NewConsumerRegistry(cfg.KafkaConfig, nil)
+	if err != nil {
+		return err
+	}
+	topics, err := registry.ParseTopics(list)
 	if err != nil {
-		log := logger.FromCtx(ctx)
-		log.Error().Err(err).Msg("Failed to initialize tracing")
-		// Continue without tracing if initialization fails
-		tracedCtx = ctx
-	} else {
-		defer func() {
-			if err := tracing.Shutdown(context.Background()); err != nil {
-				log := logger.FromCtx(tracedCtx)
-				log.Error().Err(err).Msg("Error shutting down tracing")
-			}
-		}()
-		log := logger.FromCtx(tracedCtx)
-		log.Info().Msg("Tracing initialized successfully")
+		return err
 	}
 
+	return runEventing(cfg, topics)
+}
+
+func runEventing(cfg *config.Config, topics []string) error {
+	ctx, shutdown, stop := startRun(cfg)
+	defer stop()
+
 	// Start eventing with traced context
-	return handlers.UserCreatedEventingWithContext(tracedCtx)
+	return handlers.Eventing(ctx, topics, shutdown)
 }
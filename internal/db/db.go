@@ -1,6 +1,8 @@
 package db
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
 	"sync"
@@ -99,3 +101,34 @@ func GetDBService() DB {
 
 	return NewDBService()
 }
+
+// Close closes the connection pool, if one was opened. The next GetDBService reconnects.
+func Close() error {
+	service := dbservice.GetDB()
+	if service == nil {
+		return nil
+	}
+	dbservice.SetDB(nil)
+
+	sqlDB, err := service.GetDB().DB()
+	if err != nil {
+		return err
+	}
+
+	return sqlDB.Close()
+}
+
+// Ping checks the open connection pool without opening one.
+func Ping(ctx context.Context) error {
+	service := dbservice.GetDB()
+	if service == nil {
+		return errors.New("database not connected")
+	}
+
+	sqlDB, err := service.GetDB().DB()
+	if err != nil {
+		return err
+	}
+
+	return sqlDB.PingContext(ctx)
+}
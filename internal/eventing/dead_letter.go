@@ -0,0 +1,196 @@
+package eventing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/ThatCatDev/ep/v2/drivers"
+	"github.com/ThatCatDev/ep/v2/event"
+	"github.com/ThatCatDev/ep/v2/middleware"
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+	"github.com/weeb-vip/user-service/internal/logger"
+	"github.com/weeb-vip/user-service/metrics"
+)
+
+// Headers added to dead-lettered messages. Headers already on the message are kept.
+const (
+	headerPrefix = "dlq."
+
+	HeaderError             = "dlq.error"
+	HeaderReason            = "dlq.reason"
+	HeaderOriginalTopic     = "dlq.original_topic"
+	HeaderOriginalPartition = "dlq.original_partition"
+	HeaderOriginalOffset    = "dlq.original_offset"
+	HeaderAttempts          = "dlq.attempts"
+	HeaderFailedAt          = "dlq.failed_at"
+	// HeaderReplayedFrom is set on messages re-injected by Replay, as "<topic>/<partition>/<offset>".
+	HeaderReplayedFrom = "dlq.replayed_from"
+)
+
+// Reasons recorded in HeaderReason.
+const (
+	ReasonRetriesExhausted = "retries_exhausted"
+	ReasonMalformed        = "malformed"
+	ReasonInvalid          = "invalid"
+	// ReasonRejected is for valid events the service refuses, such as an email another user holds.
+	ReasonRejected = "rejected"
+)
+
+const (
+	defaultInitialInterval = 500 * time.Millisecond
+	defaultMaxInterval     = 10 * time.Second
+)
+
+type DeadLetterConfig struct {
+	// Topic is the topic being consumed, used for metrics and the dead-letter headers.
+	Topic string
+	// DeadLetterTopic receives messages that still fail after MaxRetries retries.
+	DeadLetterTopic string
+	MaxRetries      int
+	InitialInterval time.Duration
+	MaxInterval     time.Duration
+}
+
+// DeadLetterTopic returns the dead-letter topic for topic unless one is configured.
+func DeadLetterTopic(topic string, configured string) string {
+	if configured != "" {
+		return configured
+	}
+
+	return topic + "-dlq"
+}
+
+// PermanentError is a failure that retrying can't fix, such as a payload that fails validation.
+// The dead-letter middleware sends it to the dead-letter topic straight away.
+type PermanentError struct {
+	Reason string
+	Err    error
+}
+
+func (e *PermanentError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *PermanentError) Unwrap() error {
+	return e.Err
+}
+
+func Permanent(reason string, err error) error {
+	return &PermanentError{Reason: reason, Err: err}
+}
+
+// NewDeadLetterMiddleware retries a failing message in place with exponential backoff and, once
+// retries are exhausted or on a PermanentError, moves it to the dead-letter topic so the
+// partition can move on. It only returns an error when the message could not be dead-lettered,
+// which stops the consumer before the offset is committed.
+func NewDeadLetterMiddleware[M any](
+	driver drivers.Driver[*kafka.Message],
+	config DeadLetterConfig,
+) middleware.Middleware[*kafka.Message, M] {
+	if config.InitialInterval == 0 {
+		config.InitialInterval = defaultInitialInterval
+	}
+	if config.MaxInterval == 0 {
+		config.MaxInterval = defaultMaxInterval
+	}
+
+	return func(
+		ctx context.Context,
+		data event.Event[*kafka.Message, M],
+		next middleware.Handler[*kafka.Message, M],
+	) (*event.Event[*kafka.Message, M], error) {
+		log := logger.FromCtx(ctx)
+
+		result, err := next(ctx, data)
+		var permanent *PermanentError
+		interval := config.InitialInterval
+		attempts := 1
+		for ; err != nil && !errors.As(err, &permanent) && attempts <= config.MaxRetries; attempts++ {
+			metrics.GetAppMetrics().EventRetryMetric(config.Topic)
+			log.Warn().Err(err).Str("topic", config.Topic).Int("attempt", attempts).Msg("Event failed, retrying")
+
+			select {
+			case <-ctx.Done():
+				return &data, ctx.Err()
+			case <-time.After(interval):
+			}
+			interval = min(interval*2, config.MaxInterval)
+
+			result, err = next(ctx, data)
+		}
+		if err == nil {
+			return result, nil
+		}
+
+		reason := ReasonRetriesExhausted
+		if permanent != nil {
+			reason = permanent.Reason
+		}
+
+		if produceErr := driver.Produce(ctx, config.DeadLetterTopic, deadLetter(data.DriverMessage, config.Topic, err, reason, attempts)); produceErr != nil {
+			log.Error().Err(produceErr).Str("topic", config.DeadLetterTopic).Msg("Failed to dead-letter event")
+			return &data, produceErr
+		}
+
+		metrics.GetAppMetrics().EventDeadLetterMetric(config.Topic)
+		log.Error().Err(err).Str("topic", config.Topic).Str("dead_letter_topic", config.DeadLetterTopic).Str("reason", reason).Msg("Event dead-lettered")
+
+		return &data, nil
+	}
+}
+
+type malformedDeadLetteringDriver struct {
+	drivers.Driver[*kafka.Message]
+	config DeadLetterConfig
+}
+
+// DeadLetterMalformed wraps driver so that messages which aren't JSON at all go straight to the
+// dead-letter topic. The processor can't decode them into an event and would stop consuming.
+func DeadLetterMalformed(driver drivers.Driver[*kafka.Message], config DeadLetterConfig) drivers.Driver[*kafka.Message] {
+	return &malformedDeadLetteringDriver{Driver: driver, config: config}
+}
+
+func (d *malformedDeadLetteringDriver) Consume(ctx context.Context, topic string, handler func(context.Context, *kafka.Message, []byte) error) error {
+	return d.Driver.Consume(ctx, topic, func(ctx context.Context, message *kafka.Message, body []byte) error {
+		if json.Valid(body) {
+			return handler(ctx, message, body)
+		}
+
+		cause := errors.New("payload is not valid JSON")
+		if err := d.Produce(ctx, d.config.DeadLetterTopic, deadLetter(message, d.config.Topic, cause, ReasonMalformed, 0)); err != nil {
+			return err
+		}
+		metrics.GetAppMetrics().EventDeadLetterMetric(d.config.Topic)
+		log := logger.FromCtx(ctx)
+		log.Error().Str("topic", d.config.Topic).Str("reason", ReasonMalformed).Msg("Event dead-lettered")
+
+		return nil
+	})
+}
+
+// deadLetter copies message with headers describing why and where it failed.
+func deadLetter(message *kafka.Message, topic string, cause error, reason string, attempts int) *kafka.Message {
+	dead := &kafka.Message{}
+	partition, offset := int32(kafka.PartitionAny), kafka.OffsetInvalid
+	if message != nil {
+		dead.Key = message.Key
+		dead.Value = message.Value
+		dead.Headers = append(dead.Headers, message.Headers...)
+		partition, offset = message.TopicPartition.Partition, message.TopicPartition.Offset
+	}
+
+	dead.Headers = append(dead.Headers,
+		kafka.Header{Key: HeaderError, Value: []byte(cause.Error())},
+		kafka.Header{Key: HeaderReason, Value: []byte(reason)},
+		kafka.Header{Key: HeaderOriginalTopic, Value: []byte(topic)},
+		kafka.Header{Key: HeaderOriginalPartition, Value: []byte(strconv.Itoa(int(partition)))},
+		kafka.Header{Key: HeaderOriginalOffset, Value: []byte(strconv.FormatInt(int64(offset), 10))},
+		kafka.Header{Key: HeaderAttempts, Value: []byte(strconv.Itoa(attempts))},
+		kafka.Header{Key: HeaderFailedAt, Value: []byte(time.Now().UTC().Format(time.RFC3339))},
+	)
+
+	return dead
+}
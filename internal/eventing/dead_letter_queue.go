@@ -0,0 +1,214 @@
+package eventing
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+	"github.com/weeb-vip/user-service/internal/ulid"
+)
+
+const metadataTimeoutMs = 10000
+
+// deadLetterIdleTimeout stops a read that gets no messages for this long even though a partition
+// seems unfinished, rather than waiting forever for an offset that will never be delivered.
+const deadLetterIdleTimeout = 10 * time.Second
+
+// partitionReader is the part of *kafka.Consumer that ReadDeadLetters reads assigned partitions with.
+type partitionReader interface {
+	ReadMessage(timeout time.Duration) (*kafka.Message, error)
+	Position(partitions []kafka.TopicPartition) ([]kafka.TopicPartition, error)
+}
+
+// DeadLetter is a message read from a dead-letter topic.
+type DeadLetter struct {
+	Topic     string
+	Partition int32
+	Offset    int64
+	Key       string
+	Value     []byte
+	Headers   map[string]string
+	Timestamp time.Time
+}
+
+// Error returns why the message was dead-lettered.
+func (d DeadLetter) Error() string {
+	return d.Headers[HeaderError]
+}
+
+// OriginalTopic returns the topic the message failed on.
+func (d DeadLetter) OriginalTopic() string {
+	return d.Headers[HeaderOriginalTopic]
+}
+
+// Attempts returns how many times the message was processed before it was dead-lettered.
+func (d DeadLetter) Attempts() int {
+	attempts, _ := strconv.Atoi(d.Headers[HeaderAttempts])
+
+	return attempts
+}
+
+// ReadDeadLetters returns the messages currently in topic that match filter, up to limit when it
+// is positive. Partitions are read directly without a consumer group, so nothing is committed and
+// the topic can be inspected any number of times.
+func ReadDeadLetters(ctx context.Context, config *kafka.ConfigMap, topic string, filter *Filter, limit int) ([]DeadLetter, error) {
+	consumerConfig := kafka.ConfigMap{}
+	for key, value := range *config {
+		consumerConfig[key] = value
+	}
+	consumerConfig["group.id"] = ulid.New("dlq_reader")
+	consumerConfig["enable.auto.commit"] = false
+
+	consumer, err := kafka.NewConsumer(&consumerConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create consumer: %w", err)
+	}
+	defer consumer.Close()
+
+	metadata, err := consumer.GetMetadata(&topic, false, metadataTimeoutMs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read metadata for %s: %w", topic, err)
+	}
+	topicMetadata, ok := metadata.Topics[topic]
+	if !ok || topicMetadata.Error.Code() == kafka.ErrUnknownTopicOrPart {
+		return nil, nil
+	}
+
+	// Read each partition up to its end as of now, so messages dead-lettered meanwhile are left alone
+	remaining := map[int32]int64{}
+	var assignments []kafka.TopicPartition
+	for _, partition := range topicMetadata.Partitions {
+		low, high, err := consumer.QueryWatermarkOffsets(topic, partition.ID, metadataTimeoutMs)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read offsets for %s/%d: %w", topic, partition.ID, err)
+		}
+		if high <= low {
+			continue
+		}
+		remaining[partition.ID] = high
+		assignments = append(assignments, kafka.TopicPartition{Topic: &topic, Partition: partition.ID, Offset: kafka.Offset(low)})
+	}
+	if len(assignments) == 0 {
+		return nil, nil
+	}
+	if err := consumer.Assign(assignments); err != nil {
+		return nil, fmt.Errorf("failed to assign partitions: %w", err)
+	}
+
+	return readDeadLetters(ctx, consumer, topic, remaining, filter, limit, deadLetterIdleTimeout)
+}
+
+// readDeadLetters reads the assigned partitions until each reaches its end offset in remaining.
+// The last offset before the end may never arrive, as a transaction marker or a compacted away
+// message, so a partition also counts as done once the consumer's position has passed its end.
+func readDeadLetters(
+	ctx context.Context,
+	consumer partitionReader,
+	topic string,
+	remaining map[int32]int64,
+	filter *Filter,
+	limit int,
+	idleTimeout time.Duration,
+) ([]DeadLetter, error) {
+	var letters []DeadLetter
+	lastMessage := time.Now()
+	for len(remaining) > 0 && (limit <= 0 || len(letters) < limit) {
+		if err := ctx.Err(); err != nil {
+			return letters, err
+		}
+
+		message, err := consumer.ReadMessage(time.Second)
+		if err != nil {
+			if kafkaErr, ok := err.(kafka.Error); ok && kafkaErr.Code() == kafka.ErrTimedOut {
+				if err := dropFinishedPartitions(consumer, topic, remaining); err != nil {
+					return letters, err
+				}
+				if time.Since(lastMessage) > idleTimeout {
+					break
+				}
+				continue
+			}
+			if kafkaErr, ok := err.(kafka.Error); ok && kafkaErr.IsRetriable() {
+				continue
+			}
+			return letters, fmt.Errorf("failed to read %s: %w", topic, err)
+		}
+		lastMessage = time.Now()
+
+		partition, offset := message.TopicPartition.Partition, int64(message.TopicPartition.Offset)
+		if offset+1 >= remaining[partition] {
+			delete(remaining, partition)
+		}
+
+		letter := toDeadLetter(message)
+		if filter == nil || filter.Match(letter) {
+			letters = append(letters, letter)
+		}
+	}
+
+	return letters, nil
+}
+
+// dropFinishedPartitions removes the partitions whose position has reached their end offset.
+func dropFinishedPartitions(consumer partitionReader, topic string, remaining map[int32]int64) error {
+	partitions := make([]kafka.TopicPartition, 0, len(remaining))
+	for partition := range remaining {
+		partitions = append(partitions, kafka.TopicPartition{Topic: &topic, Partition: partition})
+	}
+
+	positions, err := consumer.Position(partitions)
+	if err != nil {
+		return fmt.Errorf("failed to read positions for %s: %w", topic, err)
+	}
+	for _, position := range positions {
+		// The position is invalid until the consumer has fetched from the partition
+		if position.Offset >= 0 && int64(position.Offset) >= remaining[position.Partition] {
+			delete(remaining, position.Partition)
+		}
+	}
+
+	return nil
+}
+
+func toDeadLetter(message *kafka.Message) DeadLetter {
+	letter := DeadLetter{
+		Partition: message.TopicPartition.Partition,
+		Offset:    int64(message.TopicPartition.Offset),
+		Key:       string(message.Key),
+		Value:     message.Value,
+		Headers:   map[string]string{},
+		Timestamp: message.Timestamp,
+	}
+	if message.TopicPartition.Topic != nil {
+		letter.Topic = *message.TopicPartition.Topic
+	}
+	for _, header := range message.Headers {
+		letter.Headers[header.Key] = string(header.Value)
+	}
+
+	return letter
+}
+
+// ReplayMessage rebuilds the original message for letter, without the dead-letter headers and
+// with HeaderReplayedFrom pointing back at it. Retry counters start again from zero.
+func ReplayMessage(letter DeadLetter) *kafka.Message {
+	message := &kafka.Message{
+		Key:   []byte(letter.Key),
+		Value: letter.Value,
+	}
+	for key, value := range letter.Headers {
+		if strings.HasPrefix(key, headerPrefix) {
+			continue
+		}
+		message.Headers = append(message.Headers, kafka.Header{Key: key, Value: []byte(value)})
+	}
+	message.Headers = append(message.Headers, kafka.Header{
+		Key:   HeaderReplayedFrom,
+		Value: []byte(fmt.Sprintf("%s/%d/%d", letter.Topic, letter.Partition, letter.Offset)),
+	})
+
+	return message
+}
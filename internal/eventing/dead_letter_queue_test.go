@@ -0,0 +1,82 @@
+package eventing
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubReader hands out messages, then times out like a consumer that has nothing left to deliver.
+type stubReader struct {
+	messages  []*kafka.Message
+	positions map[int32]kafka.Offset
+}
+
+func (r *stubReader) ReadMessage(_ time.Duration) (*kafka.Message, error) {
+	if len(r.messages) == 0 {
+		return nil, kafka.NewError(kafka.ErrTimedOut, "timed out", false)
+	}
+	message := r.messages[0]
+	r.messages = r.messages[1:]
+
+	return message, nil
+}
+
+func (r *stubReader) Position(partitions []kafka.TopicPartition) ([]kafka.TopicPartition, error) {
+	positions := make([]kafka.TopicPartition, 0, len(partitions))
+	for _, partition := range partitions {
+		offset, ok := r.positions[partition.Partition]
+		if !ok {
+			offset = kafka.OffsetInvalid
+		}
+		partition.Offset = offset
+		positions = append(positions, partition)
+	}
+
+	return positions, nil
+}
+
+func deadLetterMessage(topic string, partition int32, offset int64) *kafka.Message {
+	return &kafka.Message{
+		TopicPartition: kafka.TopicPartition{Topic: &topic, Partition: partition, Offset: kafka.Offset(offset)},
+		Value:          []byte(`{}`),
+	}
+}
+
+func TestReadDeadLetters_StopsPastTheEnd(t *testing.T) {
+	topic := "user-created.dlq"
+	// Offset 1 is a transaction marker, so the last message delivered is offset 0
+	reader := &stubReader{
+		messages:  []*kafka.Message{deadLetterMessage(topic, 0, 0)},
+		positions: map[int32]kafka.Offset{0: 2},
+	}
+
+	done := make(chan struct{})
+	var letters []DeadLetter
+	var err error
+	go func() {
+		defer close(done)
+		letters, err = readDeadLetters(context.Background(), reader, topic, map[int32]int64{0: 2}, nil, 0, time.Hour)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("reading did not stop at the end of the partition")
+	}
+	require.NoError(t, err)
+	assert.Len(t, letters, 1)
+}
+
+func TestReadDeadLetters_StopsWhenIdle(t *testing.T) {
+	topic := "user-created.dlq"
+	reader := &stubReader{messages: []*kafka.Message{deadLetterMessage(topic, 0, 0)}}
+
+	letters, err := readDeadLetters(context.Background(), reader, topic, map[int32]int64{0: 5}, nil, 0, 20*time.Millisecond)
+	require.NoError(t, err)
+	assert.Len(t, letters, 1)
+}
@@ -0,0 +1,129 @@
+package eventing
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ThatCatDev/ep/v2/drivers"
+	"github.com/ThatCatDev/ep/v2/event"
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type producedMessage struct {
+	topic   string
+	message *kafka.Message
+}
+
+type recordingDriver struct {
+	drivers.Driver[*kafka.Message]
+	consumed   []*kafka.Message
+	produced   []producedMessage
+	produceErr error
+}
+
+func (d *recordingDriver) Consume(ctx context.Context, _ string, handler func(context.Context, *kafka.Message, []byte) error) error {
+	for _, message := range d.consumed {
+		if err := handler(ctx, message, message.Value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (d *recordingDriver) Produce(_ context.Context, topic string, message *kafka.Message) error {
+	if d.produceErr != nil {
+		return d.produceErr
+	}
+	d.produced = append(d.produced, producedMessage{topic: topic, message: message})
+
+	return nil
+}
+
+type testPayload struct{}
+
+func failingHandler(failures int, calls *int) func(context.Context, event.Event[*kafka.Message, testPayload]) (*event.Event[*kafka.Message, testPayload], error) {
+	return func(_ context.Context, data event.Event[*kafka.Message, testPayload]) (*event.Event[*kafka.Message, testPayload], error) {
+		*calls++
+		if *calls <= failures {
+			return &data, errors.New("boom")
+		}
+
+		return &data, nil
+	}
+}
+
+func testEvent() event.Event[*kafka.Message, testPayload] {
+	topic := "user-created"
+
+	return event.Event[*kafka.Message, testPayload]{
+		DriverMessage: &kafka.Message{
+			TopicPartition: kafka.TopicPartition{Topic: &topic, Partition: 1, Offset: 7},
+			Key:            []byte("user-1"),
+			Value:          []byte(`{"user_id":"user-1"}`),
+			Headers:        []kafka.Header{{Key: "trace", Value: []byte("abc")}},
+		},
+	}
+}
+
+func testDeadLetterConfig() DeadLetterConfig {
+	return DeadLetterConfig{
+		Topic:           "user-created",
+		DeadLetterTopic: DeadLetterTopic("user-created", ""),
+		MaxRetries:      2,
+		InitialInterval: time.Millisecond,
+		MaxInterval:     time.Millisecond,
+	}
+}
+
+func TestDeadLetterMiddleware_SucceedsAfterRetry(t *testing.T) {
+	driver := &recordingDriver{}
+	calls := 0
+
+	_, err := NewDeadLetterMiddleware[testPayload](driver, testDeadLetterConfig())(context.Background(), testEvent(), failingHandler(1, &calls))
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, calls)
+	assert.Empty(t, driver.produced)
+}
+
+func TestDeadLetterMiddleware_DeadLettersAfterRetries(t *testing.T) {
+	driver := &recordingDriver{}
+	calls := 0
+
+	_, err := NewDeadLetterMiddleware[testPayload](driver, testDeadLetterConfig())(context.Background(), testEvent(), failingHandler(10, &calls))
+
+	require.NoError(t, err)
+	assert.Equal(t, 3, calls)
+	require.Len(t, driver.produced, 1)
+	assert.Equal(t, "user-created-dlq", driver.produced[0].topic)
+
+	letter := toDeadLetter(driver.produced[0].message)
+	assert.Equal(t, "user-1", letter.Key)
+	assert.Equal(t, "boom", letter.Error())
+	assert.Equal(t, ReasonRetriesExhausted, letter.Headers[HeaderReason])
+	assert.Equal(t, "user-created", letter.OriginalTopic())
+	assert.Equal(t, 3, letter.Attempts())
+	assert.Equal(t, "1", letter.Headers[HeaderOriginalPartition])
+	assert.Equal(t, "7", letter.Headers[HeaderOriginalOffset])
+	assert.Equal(t, "abc", letter.Headers["trace"])
+
+	// Replaying drops the dead-letter metadata but keeps the original headers
+	letter.Topic, letter.Partition, letter.Offset = "user-created-dlq", 0, 12
+	replayed := toDeadLetter(ReplayMessage(letter))
+	assert.Equal(t, `{"user_id":"user-1"}`, string(replayed.Value))
+	assert.Equal(t, map[string]string{"trace": "abc", HeaderReplayedFrom: "user-created-dlq/0/12"}, replayed.Headers)
+}
+
+func TestDeadLetterMiddleware_ProduceFailureStopsConsumer(t *testing.T) {
+	driver := &recordingDriver{produceErr: errors.New("broker down")}
+	calls := 0
+
+	_, err := NewDeadLetterMiddleware[testPayload](driver, testDeadLetterConfig())(context.Background(), testEvent(), failingHandler(10, &calls))
+
+	assert.EqualError(t, err, "broker down")
+}
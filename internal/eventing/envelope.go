@@ -0,0 +1,122 @@
+package eventing
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/ThatCatDev/ep/v2/event"
+	"github.com/ThatCatDev/ep/v2/middleware"
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+	"github.com/weeb-vip/user-service/internal/ulid"
+)
+
+// Envelope wraps every event with the metadata consumers need to route, deduplicate and validate
+// it. Data holds the event itself and is described by the schema for Type at Version.
+type Envelope struct {
+	EventID    string          `json:"event_id"`
+	Type       string          `json:"type"`
+	Version    int             `json:"version"`
+	OccurredAt time.Time       `json:"occurred_at"`
+	Producer   string          `json:"producer"`
+	Data       json.RawMessage `json:"data"`
+
+	raw       []byte
+	legacy    bool
+	decodeErr error
+}
+
+// NewEnvelope wraps data as version of eventType, validating it against the schema first so
+// producers can't publish events their consumers would reject.
+func NewEnvelope(eventType string, version int, producer string, data any) (*Envelope, error) {
+	body, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	if err := DefaultSchemas().Validate(eventType, version, body); err != nil {
+		return nil, err
+	}
+
+	return &Envelope{
+		EventID:    ulid.New("evt"),
+		Type:       eventType,
+		Version:    version,
+		OccurredAt: time.Now().UTC(),
+		Producer:   producer,
+		Data:       body,
+	}, nil
+}
+
+// UnmarshalJSON never fails, because the processor stops consuming on a decode error. Anything
+// that isn't an envelope is kept and rejected by the envelope middleware instead, apart from
+// objects without a type, which are payloads from producers that predate envelopes.
+func (e *Envelope) UnmarshalJSON(body []byte) error {
+	type envelope Envelope
+
+	e.raw = append([]byte(nil), body...)
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &fields); err != nil {
+		e.decodeErr = err
+		return nil
+	}
+	if _, ok := fields["type"]; !ok {
+		e.legacy = true
+		e.Data = e.raw
+		return nil
+	}
+
+	var decoded envelope
+	if err := json.Unmarshal(body, &decoded); err != nil {
+		e.decodeErr = err
+		return nil
+	}
+	*e = Envelope(decoded)
+	e.raw = append([]byte(nil), body...)
+
+	return nil
+}
+
+// Legacy reports whether the message was a bare payload rather than an envelope.
+func (e Envelope) Legacy() bool {
+	return e.legacy
+}
+
+// Decode unmarshals Data into payload.
+func (e Envelope) Decode(payload any) error {
+	return json.Unmarshal(e.Data, payload)
+}
+
+// NewEnvelopeMiddleware accepts only eventType events whose envelope and data match the schemas,
+// failing everything else with a PermanentError so it goes to the dead-letter topic with the
+// reason. Legacy payloads are treated as version 1 of eventType.
+func NewEnvelopeMiddleware(schemas *Schemas, eventType string) middleware.Middleware[*kafka.Message, Envelope] {
+	return func(
+		ctx context.Context,
+		data event.Event[*kafka.Message, Envelope],
+		next middleware.Handler[*kafka.Message, Envelope],
+	) (*event.Event[*kafka.Message, Envelope], error) {
+		envelope := &data.Payload
+		if envelope.decodeErr != nil {
+			return &data, Permanent(ReasonMalformed, envelope.decodeErr)
+		}
+
+		if envelope.legacy {
+			envelope.Type, envelope.Version = eventType, 1
+		} else {
+			if err := schemas.ValidateEnvelope(envelope.raw); err != nil {
+				return &data, Permanent(ReasonInvalid, err)
+			}
+			if envelope.Type != eventType {
+				return &data, Permanent(ReasonInvalid, fmt.Errorf("expected a %s event, got %s", eventType, envelope.Type))
+			}
+		}
+
+		if err := schemas.Validate(envelope.Type, envelope.Version, bytes.TrimSpace(envelope.Data)); err != nil {
+			return &data, Permanent(ReasonInvalid, err)
+		}
+
+		return next(ctx, data)
+	}
+}
@@ -0,0 +1,113 @@
+package eventing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/ThatCatDev/ep/v2/event"
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewEnvelope(t *testing.T) {
+	envelope, err := NewEnvelope("user.created", 1, "user-service", map[string]string{"user_id": "1"})
+	require.NoError(t, err)
+
+	body, err := json.Marshal(envelope)
+	require.NoError(t, err)
+	assert.NoError(t, DefaultSchemas().ValidateEnvelope(body))
+
+	var decoded Envelope
+	require.NoError(t, json.Unmarshal(body, &decoded))
+	assert.False(t, decoded.Legacy())
+	assert.Equal(t, envelope.EventID, decoded.EventID)
+	assert.JSONEq(t, `{"user_id":"1"}`, string(decoded.Data))
+
+	_, err = NewEnvelope("user.created", 1, "user-service", map[string]string{"email": "a@example.com"})
+	assert.Error(t, err)
+}
+
+func TestEnvelopeMiddleware(t *testing.T) {
+	valid := `{"event_id":"evt_1","type":"user.created","version":1,"occurred_at":"2025-09-25T10:00:00Z","producer":"auth-service","data":{"user_id":"1"}}`
+
+	tests := []struct {
+		name   string
+		body   string
+		reason string
+	}{
+		{name: "envelope", body: valid},
+		{name: "legacy payload", body: `{"user_id":"1","email":"a@example.com"}`},
+		{name: "not an object", body: `["user_id"]`, reason: ReasonMalformed},
+		{name: "legacy payload without user id", body: `{"email":"a@example.com"}`, reason: ReasonInvalid},
+		{name: "envelope without event id", body: `{"type":"user.created","version":1,"occurred_at":"2025-09-25T10:00:00Z","producer":"auth-service","data":{"user_id":"1"}}`, reason: ReasonInvalid},
+		{name: "other event type", body: `{"event_id":"evt_1","type":"user.deleted","version":1,"occurred_at":"2025-09-25T10:00:00Z","producer":"auth-service","data":{"user_id":"1"}}`, reason: ReasonInvalid},
+		{name: "unknown version", body: `{"event_id":"evt_1","type":"user.created","version":7,"occurred_at":"2025-09-25T10:00:00Z","producer":"auth-service","data":{"user_id":"1"}}`, reason: ReasonInvalid},
+		{name: "invalid data", body: `{"event_id":"evt_1","type":"user.created","version":1,"occurred_at":"2025-09-25T10:00:00Z","producer":"auth-service","data":{"user_id":1}}`, reason: ReasonInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := event.Event[*kafka.Message, Envelope]{}
+			require.NoError(t, data.Transform([]byte(tt.body)))
+
+			called := false
+			next := func(_ context.Context, data event.Event[*kafka.Message, Envelope]) (*event.Event[*kafka.Message, Envelope], error) {
+				called = true
+				assert.Equal(t, "user.created", data.Payload.Type)
+				assert.Equal(t, 1, data.Payload.Version)
+
+				return &data, nil
+			}
+
+			_, err := NewEnvelopeMiddleware(DefaultSchemas(), "user.created")(context.Background(), data, next)
+			if tt.reason == "" {
+				require.NoError(t, err)
+				assert.True(t, called)
+				return
+			}
+
+			var permanent *PermanentError
+			require.True(t, errors.As(err, &permanent), "expected a permanent error, got %v", err)
+			assert.Equal(t, tt.reason, permanent.Reason)
+			assert.False(t, called)
+		})
+	}
+}
+
+func TestDeadLetterMiddleware_PermanentErrorsSkipRetries(t *testing.T) {
+	driver := &recordingDriver{}
+	calls := 0
+	next := func(_ context.Context, data event.Event[*kafka.Message, testPayload]) (*event.Event[*kafka.Message, testPayload], error) {
+		calls++
+		return &data, Permanent(ReasonInvalid, errors.New("user_id is required"))
+	}
+
+	_, err := NewDeadLetterMiddleware[testPayload](driver, testDeadLetterConfig())(context.Background(), testEvent(), next)
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, calls)
+	require.Len(t, driver.produced, 1)
+	letter := toDeadLetter(driver.produced[0].message)
+	assert.Equal(t, ReasonInvalid, letter.Headers[HeaderReason])
+	assert.Equal(t, "user_id is required", letter.Error())
+}
+
+func TestDeadLetterMalformed(t *testing.T) {
+	message := testEvent().DriverMessage
+	message.Value = []byte("user_id=1")
+	driver := &recordingDriver{consumed: []*kafka.Message{message, testEvent().DriverMessage}}
+	handled := 0
+
+	err := DeadLetterMalformed(driver, testDeadLetterConfig()).Consume(context.Background(), "user-created", func(context.Context, *kafka.Message, []byte) error {
+		handled++
+		return nil
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, handled)
+	require.Len(t, driver.produced, 1)
+	assert.Equal(t, ReasonMalformed, toDeadLetter(driver.produced[0].message).Headers[HeaderReason])
+}
@@ -0,0 +1,129 @@
+package eventing
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Filter selects dead letters. It is a comma separated list of terms that must all match, each
+// either "field=value" for an exact match or "field~value" for a substring match. Fields are key,
+// partition, offset, error, topic (the original topic), header.<name> and payload.<name> for a
+// top-level field of the event, read from the envelope's data or from a bare JSON payload. An
+// empty filter matches everything.
+type Filter struct {
+	terms []filterTerm
+}
+
+type filterTerm struct {
+	field     string
+	value     string
+	substring bool
+}
+
+func ParseFilter(expression string) (*Filter, error) {
+	filter := &Filter{}
+	for _, raw := range strings.Split(expression, ",") {
+		raw = strings.TrimSpace(raw)
+		if raw == "" {
+			continue
+		}
+
+		index := strings.IndexAny(raw, "=~")
+		if index <= 0 {
+			return nil, fmt.Errorf("filter term %q must look like field=value or field~value", raw)
+		}
+
+		term := filterTerm{
+			field:     strings.TrimSpace(raw[:index]),
+			value:     strings.TrimSpace(raw[index+1:]),
+			substring: raw[index] == '~',
+		}
+		if !knownField(term.field) {
+			return nil, fmt.Errorf("unknown filter field %q", term.field)
+		}
+		filter.terms = append(filter.terms, term)
+	}
+
+	return filter, nil
+}
+
+func knownField(field string) bool {
+	switch field {
+	case "key", "partition", "offset", "error", "topic":
+		return true
+	}
+
+	return strings.HasPrefix(field, "header.") || strings.HasPrefix(field, "payload.")
+}
+
+// Match reports whether letter satisfies every term of the filter.
+func (f *Filter) Match(letter DeadLetter) bool {
+	for _, term := range f.terms {
+		actual, ok := fieldValue(letter, term.field)
+		if !ok {
+			return false
+		}
+		if term.substring && !strings.Contains(actual, term.value) {
+			return false
+		}
+		if !term.substring && actual != term.value {
+			return false
+		}
+	}
+
+	return true
+}
+
+func fieldValue(letter DeadLetter, field string) (string, bool) {
+	switch field {
+	case "key":
+		return letter.Key, true
+	case "partition":
+		return strconv.Itoa(int(letter.Partition)), true
+	case "offset":
+		return strconv.FormatInt(letter.Offset, 10), true
+	case "error":
+		return letter.Error(), true
+	case "topic":
+		return letter.OriginalTopic(), true
+	}
+
+	if name, ok := strings.CutPrefix(field, "header."); ok {
+		value, found := letter.Headers[name]
+		return value, found
+	}
+
+	name, _ := strings.CutPrefix(field, "payload.")
+	// Events sit under data since they are enveloped. Bare payloads from older producers decode
+	// as their own data, and the top level stays reachable for envelope fields such as type
+	var envelope Envelope
+	if err := json.Unmarshal(letter.Value, &envelope); err != nil || envelope.decodeErr != nil {
+		return "", false
+	}
+	if value, found := jsonField(envelope.Data, name); found {
+		return value, true
+	}
+
+	return jsonField(letter.Value, name)
+}
+
+// jsonField returns the top-level field name of the JSON object in body.
+func jsonField(body []byte, name string) (string, bool) {
+	var payload map[string]json.RawMessage
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return "", false
+	}
+	raw, found := payload[name]
+	if !found {
+		return "", false
+	}
+	// Strings compare by their contents, anything else by its JSON text
+	var text string
+	if err := json.Unmarshal(raw, &text); err == nil {
+		return text, true
+	}
+
+	return string(raw), true
+}
@@ -0,0 +1,82 @@
+package eventing
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFilter_Invalid(t *testing.T) {
+	for _, expression := range []string{"key", "=value", "colour=red", "key=a,offset"} {
+		_, err := ParseFilter(expression)
+		assert.Error(t, err, expression)
+	}
+}
+
+func TestFilter_Match(t *testing.T) {
+	letter := DeadLetter{
+		Partition: 2,
+		Offset:    41,
+		Key:       "user-1",
+		Value:     []byte(`{"user_id":"user-1","attempt":3}`),
+		Headers: map[string]string{
+			HeaderError:         "USER_EXISTS: user already exists",
+			HeaderOriginalTopic: "user-created",
+			"trace":             "abc",
+		},
+	}
+
+	tests := []struct {
+		expression string
+		match      bool
+	}{
+		{"", true},
+		{"key=user-1", true},
+		{"key=user-2", false},
+		{"partition=2, offset=41", true},
+		{"partition=2,offset=40", false},
+		{"error~USER_EXISTS", true},
+		{"error=USER_EXISTS", false},
+		{"topic=user-created", true},
+		{"header.trace=abc", true},
+		{"header.missing=abc", false},
+		{"payload.user_id=user-1", true},
+		{"payload.attempt=3", true},
+		{"payload.email~@", false},
+	}
+	for _, test := range tests {
+		t.Run(test.expression, func(t *testing.T) {
+			filter, err := ParseFilter(test.expression)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.match, filter.Match(letter))
+		})
+	}
+}
+
+func TestFilter_MatchEnvelope(t *testing.T) {
+	letter := DeadLetter{
+		Value: []byte(`{"event_id":"evt_1","type":"user.created","version":1,"producer":"user-service",` +
+			`"occurred_at":"2025-01-01T00:00:00Z","data":{"user_id":"user-1","attempt":3}}`),
+	}
+
+	tests := []struct {
+		expression string
+		match      bool
+	}{
+		{"payload.user_id=user-1", true},
+		{"payload.attempt=3", true},
+		{"payload.user_id=user-2", false},
+		{"payload.type=user.created", true},
+		{"payload.email~@", false},
+	}
+	for _, test := range tests {
+		t.Run(test.expression, func(t *testing.T) {
+			filter, err := ParseFilter(test.expression)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.match, filter.Match(letter))
+		})
+	}
+}
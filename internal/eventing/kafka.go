@@ -0,0 +1,74 @@
+package eventing
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	epKafka "github.com/ThatCatDev/ep/v2/drivers/kafka"
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+	"github.com/weeb-vip/user-service/config"
+)
+
+// DriverConfig converts cfg for the Kafka driver. Settings left empty in cfg fall back to the
+// librdkafka defaults.
+func DriverConfig(cfg config.KafkaConfig) *epKafka.KafkaConfig {
+	driverConfig := &epKafka.KafkaConfig{
+		ConsumerGroupName:       cfg.ConsumerGroupName,
+		BootstrapServers:        cfg.BootstrapServers,
+		SecurityProtocol:        optional(cfg.SecurityProtocol),
+		SaslMechanism:           optional(cfg.SaslMechanism),
+		Username:                optional(cfg.Username),
+		Password:                optional(cfg.Password),
+		ClientID:                optional(cfg.ClientID),
+		ConsumerAutoOffsetReset: optional(cfg.Offset),
+	}
+	if cfg.SessionTimeoutMs > 0 {
+		driverConfig.ConsumerSessionTimeoutMs = &cfg.SessionTimeoutMs
+	}
+
+	return driverConfig
+}
+
+// ConfigMap returns the client settings of cfg, without the consumer group settings.
+func ConfigMap(cfg config.KafkaConfig) *kafka.ConfigMap {
+	return epKafka.GetKafkaConfig(*DriverConfig(cfg))
+}
+
+func optional(value string) *string {
+	if value == "" {
+		return nil
+	}
+
+	return &value
+}
+
+// NewBrokerCheck returns a health check that fails unless the brokers answer a metadata request.
+// The admin client is created on first use and kept.
+func NewBrokerCheck(cfg config.KafkaConfig) func(ctx context.Context) error {
+	var (
+		mu    sync.Mutex
+		admin *kafka.AdminClient
+	)
+
+	return func(ctx context.Context) error {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if admin == nil {
+			client, err := kafka.NewAdminClient(ConfigMap(cfg))
+			if err != nil {
+				return err
+			}
+			admin = client
+		}
+
+		timeout := time.Duration(metadataTimeoutMs) * time.Millisecond
+		if deadline, ok := ctx.Deadline(); ok {
+			timeout = time.Until(deadline)
+		}
+		_, err := admin.GetMetadata(nil, false, int(timeout.Milliseconds()))
+
+		return err
+	}
+}
@@ -0,0 +1,77 @@
+package eventing
+
+import (
+	"context"
+	"time"
+
+	"github.com/ThatCatDev/ep/v2/event"
+	"github.com/ThatCatDev/ep/v2/middleware"
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+	"github.com/weeb-vip/user-service/internal/logger"
+	"github.com/weeb-vip/user-service/metrics"
+	"github.com/weeb-vip/user-service/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// NewTracingMiddleware starts a consumer span for each event, continuing the producer's trace
+// when the message carries one.
+func NewTracingMiddleware(topic string) middleware.Middleware[*kafka.Message, Envelope] {
+	return func(
+		ctx context.Context,
+		data event.Event[*kafka.Message, Envelope],
+		next middleware.Handler[*kafka.Message, Envelope],
+	) (*event.Event[*kafka.Message, Envelope], error) {
+		if data.Headers != nil {
+			ctx = propagation.TraceContext{}.Extract(ctx, propagation.MapCarrier(data.Headers))
+		}
+
+		tracer := tracing.GetTracer(ctx)
+		ctx, span := tracer.Start(ctx, "eventing.Consume "+topic,
+			trace.WithAttributes(
+				attribute.String("messaging.system", "kafka"),
+				attribute.String("messaging.source", topic),
+				attribute.String("event.id", data.Payload.EventID),
+				attribute.String("event.type", data.Payload.Type),
+			),
+			trace.WithSpanKind(trace.SpanKindConsumer),
+			tracing.GetEnvironmentAttribute(),
+		)
+		defer span.End()
+
+		result, err := next(ctx, data)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+
+		return result, err
+	}
+}
+
+// NewMetricsMiddleware records how long each event took to handle, including retries.
+func NewMetricsMiddleware(topic string) middleware.Middleware[*kafka.Message, Envelope] {
+	return func(
+		ctx context.Context,
+		data event.Event[*kafka.Message, Envelope],
+		next middleware.Handler[*kafka.Message, Envelope],
+	) (*event.Event[*kafka.Message, Envelope], error) {
+		start := time.Now()
+
+		result, err := next(ctx, data)
+
+		duration := float64(time.Since(start).Nanoseconds()) / float64(time.Millisecond)
+		outcome := metrics.Success
+		if err != nil {
+			outcome = metrics.Error
+		}
+		metrics.GetAppMetrics().EventMetric(duration, topic, outcome)
+
+		log := logger.FromCtx(ctx)
+		log.Debug().Str("topic", topic).Str("event_id", data.Payload.EventID).Float64("duration_ms", duration).Msg("Event handled")
+
+		return result, err
+	}
+}
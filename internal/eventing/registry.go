@@ -0,0 +1,150 @@
+package eventing
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/ThatCatDev/ep/v2/drivers"
+	"github.com/ThatCatDev/ep/v2/event"
+	"github.com/ThatCatDev/ep/v2/processor"
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+	"github.com/weeb-vip/user-service/config"
+	"github.com/weeb-vip/user-service/internal/logger"
+)
+
+// Handler processes one validated event. Returning a PermanentError dead-letters the event
+// straight away; any other error is retried first.
+type Handler func(ctx context.Context, envelope Envelope) error
+
+// Consumer handles the events of EventType published to Topic.
+type Consumer struct {
+	Topic     string
+	EventType string
+	Handle    Handler
+}
+
+// Registry holds the consumers this service can run, keyed by topic.
+type Registry struct {
+	consumers map[string]Consumer
+}
+
+func NewRegistry() *Registry {
+	return &Registry{consumers: map[string]Consumer{}}
+}
+
+// Register adds consumer, failing if its topic already has one.
+func (r *Registry) Register(consumer Consumer) error {
+	if consumer.Topic == "" || consumer.EventType == "" || consumer.Handle == nil {
+		return fmt.Errorf("consumer needs a topic, event type and handler")
+	}
+	if _, ok := r.consumers[consumer.Topic]; ok {
+		return fmt.Errorf("topic %s already has a consumer", consumer.Topic)
+	}
+	r.consumers[consumer.Topic] = consumer
+
+	return nil
+}
+
+// Topics returns the registered topics in alphabetical order.
+func (r *Registry) Topics() []string {
+	topics := make([]string, 0, len(r.consumers))
+	for topic := range r.consumers {
+		topics = append(topics, topic)
+	}
+	sort.Strings(topics)
+
+	return topics
+}
+
+// ParseTopics splits a comma separated list of topics, failing on any that isn't registered.
+func (r *Registry) ParseTopics(list string) ([]string, error) {
+	var topics []string
+	for _, topic := range strings.Split(list, ",") {
+		topic = strings.TrimSpace(topic)
+		if topic == "" {
+			continue
+		}
+		if _, ok := r.consumers[topic]; !ok {
+			return nil, fmt.Errorf("no consumer for topic %s, expected one of %s", topic, strings.Join(r.Topics(), ", "))
+		}
+		topics = append(topics, topic)
+	}
+	if len(topics) == 0 {
+		return nil, fmt.Errorf("no topics given, expected some of %s", strings.Join(r.Topics(), ", "))
+	}
+
+	return topics, nil
+}
+
+// Run consumes topics until ctx is cancelled or one of them fails, which stops the others. Every
+// consumer gets the same middleware: tracing, metrics, retries with dead-lettering, and envelope
+// validation.
+func (r *Registry) Run(ctx context.Context, driver drivers.Driver[*kafka.Message], topics []string, cfg config.KafkaConfig) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+	)
+	for _, topic := range topics {
+		consumer, ok := r.consumers[topic]
+		if !ok {
+			return fmt.Errorf("no consumer for topic %s", topic)
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			if err := r.run(ctx, driver, consumer, cfg); err != nil && ctx.Err() == nil {
+				once.Do(func() {
+					firstErr = fmt.Errorf("consumer for %s stopped: %w", consumer.Topic, err)
+					cancel()
+				})
+			}
+		}()
+	}
+	wg.Wait()
+
+	return firstErr
+}
+
+func (r *Registry) run(ctx context.Context, driver drivers.Driver[*kafka.Message], consumer Consumer, cfg config.KafkaConfig) error {
+	log := logger.FromCtx(ctx)
+
+	deadLetterConfig := DeadLetterConfig{
+		Topic:           consumer.Topic,
+		DeadLetterTopic: DeadLetterTopic(consumer.Topic, cfg.DeadLetterTopic),
+		MaxRetries:      cfg.MaxRetries,
+	}
+	log.Info().Str("topic", consumer.Topic).Str("dead_letter_topic", deadLetterConfig.DeadLetterTopic).Msg("Starting Kafka processor")
+
+	process := func(ctx context.Context, data event.Event[*kafka.Message, Envelope]) (event.Event[*kafka.Message, Envelope], error) {
+		return data, consumer.Handle(ctx, data.Payload)
+	}
+
+	return processor.NewProcessor[*kafka.Message, Envelope](DeadLetterMalformed(finishInFlight{driver}, deadLetterConfig), consumer.Topic, process).
+		AddMiddleware(NewTracingMiddleware(consumer.Topic)).
+		AddMiddleware(NewMetricsMiddleware(consumer.Topic)).
+		AddMiddleware(NewDeadLetterMiddleware[Envelope](driver, deadLetterConfig)).
+		AddMiddleware(NewEnvelopeMiddleware(DefaultSchemas(), consumer.EventType)).
+		Run(ctx)
+}
+
+// finishInFlight lets an event that is being handled when the consumer is stopped finish, so its
+// offset is committed rather than the event being handled again after a restart. The driver still
+// stops reading new events as soon as the context is cancelled.
+type finishInFlight struct {
+	drivers.Driver[*kafka.Message]
+}
+
+func (d finishInFlight) Consume(ctx context.Context, topic string, handler func(context.Context, *kafka.Message, []byte) error) error {
+	return d.Driver.Consume(ctx, topic, func(ctx context.Context, message *kafka.Message, body []byte) error {
+		return handler(context.WithoutCancel(ctx), message, body)
+	})
+}
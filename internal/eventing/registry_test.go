@@ -0,0 +1,31 @@
+package eventing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry(t *testing.T) {
+	handle := func(context.Context, Envelope) error { return nil }
+
+	registry := NewRegistry()
+	require.NoError(t, registry.Register(Consumer{Topic: "user-deleted", EventType: "user.deleted", Handle: handle}))
+	require.NoError(t, registry.Register(Consumer{Topic: "user-created", EventType: "user.created", Handle: handle}))
+
+	assert.Error(t, registry.Register(Consumer{Topic: "user-created", EventType: "user.created", Handle: handle}), "duplicate topic")
+	assert.Error(t, registry.Register(Consumer{Topic: "auth.email-changed", Handle: handle}), "missing event type")
+	assert.Equal(t, []string{"user-created", "user-deleted"}, registry.Topics())
+
+	topics, err := registry.ParseTopics(" user-created, user-deleted ,")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"user-created", "user-deleted"}, topics)
+
+	_, err = registry.ParseTopics("user-created,user-updated")
+	assert.ErrorContains(t, err, "user-updated")
+
+	_, err = registry.ParseTopics("")
+	assert.Error(t, err)
+}
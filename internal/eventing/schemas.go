@@ -0,0 +1,143 @@
+package eventing
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/xeipuuv/gojsonschema"
+)
+
+//go:embed schemas/*.json
+var schemaFiles embed.FS
+
+const envelopeSchemaFile = "envelope.json"
+
+// schemaFileName matches "<event type>.v<version>.json", e.g. "user.created.v1.json".
+var schemaFileName = regexp.MustCompile(`^(.+)\.v([0-9]+)\.json$`)
+
+// Schemas holds the JSON Schema of every version of every event type. Published versions must
+// never change incompatibly; breaking changes go in a new version, see schemas_test.go.
+type Schemas struct {
+	envelope *gojsonschema.Schema
+	events   map[string]map[int]*gojsonschema.Schema
+}
+
+type SchemaValidationError struct {
+	Type    string
+	Version int
+	Errors  []string
+}
+
+func (e *SchemaValidationError) Error() string {
+	return fmt.Sprintf("%s v%d failed schema validation: %s", e.Type, e.Version, strings.Join(e.Errors, "; "))
+}
+
+var (
+	defaultSchemas     *Schemas
+	defaultSchemasOnce sync.Once
+)
+
+// DefaultSchemas returns the schemas in the schemas directory.
+func DefaultSchemas() *Schemas {
+	defaultSchemasOnce.Do(func() {
+		files, err := fs.Sub(schemaFiles, "schemas")
+		if err != nil {
+			panic(err)
+		}
+		defaultSchemas, err = LoadSchemas(files)
+		if err != nil {
+			panic(err)
+		}
+	})
+
+	return defaultSchemas
+}
+
+// LoadSchemas compiles envelope.json and every "<event type>.v<version>.json" file in files.
+func LoadSchemas(files fs.FS) (*Schemas, error) {
+	schemas := &Schemas{events: map[string]map[int]*gojsonschema.Schema{}}
+
+	names, err := fs.Glob(files, "*.json")
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range names {
+		body, err := fs.ReadFile(files, name)
+		if err != nil {
+			return nil, err
+		}
+		schema, err := gojsonschema.NewSchema(gojsonschema.NewBytesLoader(body))
+		if err != nil {
+			return nil, fmt.Errorf("failed to compile schema %s: %w", name, err)
+		}
+
+		if name == envelopeSchemaFile {
+			schemas.envelope = schema
+			continue
+		}
+		match := schemaFileName.FindStringSubmatch(path.Base(name))
+		if match == nil {
+			return nil, fmt.Errorf("schema %s should be named <event type>.v<version>.json", name)
+		}
+		version, _ := strconv.Atoi(match[2])
+		if schemas.events[match[1]] == nil {
+			schemas.events[match[1]] = map[int]*gojsonschema.Schema{}
+		}
+		schemas.events[match[1]][version] = schema
+	}
+	if schemas.envelope == nil {
+		return nil, fmt.Errorf("missing %s", envelopeSchemaFile)
+	}
+
+	return schemas, nil
+}
+
+// Versions returns the known versions of eventType in ascending order.
+func (s *Schemas) Versions(eventType string) []int {
+	var versions []int
+	for version := range s.events[eventType] {
+		versions = append(versions, version)
+	}
+	sort.Ints(versions)
+
+	return versions
+}
+
+// ValidateEnvelope checks the envelope fields of body, but not its data.
+func (s *Schemas) ValidateEnvelope(body []byte) error {
+	return validate(s.envelope, "envelope", 1, body)
+}
+
+// Validate checks that data is a valid version of eventType.
+func (s *Schemas) Validate(eventType string, version int, data []byte) error {
+	schema, ok := s.events[eventType][version]
+	if !ok {
+		return &SchemaValidationError{Type: eventType, Version: version, Errors: []string{"unknown event type or version"}}
+	}
+
+	return validate(schema, eventType, version, data)
+}
+
+func validate(schema *gojsonschema.Schema, eventType string, version int, data []byte) error {
+	result, err := schema.Validate(gojsonschema.NewBytesLoader(data))
+	if err != nil {
+		return &SchemaValidationError{Type: eventType, Version: version, Errors: []string{err.Error()}}
+	}
+	if result.Valid() {
+		return nil
+	}
+
+	validationErr := &SchemaValidationError{Type: eventType, Version: version}
+	for _, resultErr := range result.Errors() {
+		validationErr.Errors = append(validationErr.Errors, resultErr.String())
+	}
+
+	return validationErr
+}
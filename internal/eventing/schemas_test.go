@@ -0,0 +1,147 @@
+package eventing
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Consumers must keep accepting events published under older schema versions, so a schema
+// change that breaks them fails the build. Add a new version for breaking changes instead, along
+// with example events in testdata/events/<type>/v<version>.
+
+type jsonSchema struct {
+	Type       any                    `json:"type"`
+	Required   []string               `json:"required"`
+	Properties map[string]*jsonSchema `json:"properties"`
+	Enum       []any                  `json:"enum"`
+	Items      *jsonSchema            `json:"items"`
+}
+
+func readSchema(t *testing.T, eventType string, version int) *jsonSchema {
+	body, err := fs.ReadFile(schemaFiles, fmt.Sprintf("schemas/%s.v%d.json", eventType, version))
+	require.NoError(t, err)
+
+	var schema jsonSchema
+	require.NoError(t, json.Unmarshal(body, &schema))
+
+	return &schema
+}
+
+// breakingChanges lists what next rejects that previous accepted, for the rules we enforce: no
+// new required fields, no removed fields, no type changes and no removed enum values.
+func breakingChanges(field string, previous *jsonSchema, next *jsonSchema) []string {
+	var changes []string
+	if next == nil {
+		return []string{field + " was removed"}
+	}
+	if previous.Type != nil && !reflect.DeepEqual(previous.Type, next.Type) {
+		changes = append(changes, fmt.Sprintf("%s changed type from %v to %v", field, previous.Type, next.Type))
+	}
+	for _, required := range next.Required {
+		if !contains(previous.Required, required) {
+			changes = append(changes, fmt.Sprintf("%s.%s became required", field, required))
+		}
+	}
+	if previous.Enum != nil {
+		for _, value := range previous.Enum {
+			if !contains(next.Enum, value) {
+				changes = append(changes, fmt.Sprintf("%s no longer allows %v", field, value))
+			}
+		}
+	}
+	for name, property := range previous.Properties {
+		changes = append(changes, breakingChanges(field+"."+name, property, next.Properties[name])...)
+	}
+	if previous.Items != nil {
+		changes = append(changes, breakingChanges(field+"[]", previous.Items, next.Items)...)
+	}
+
+	return changes
+}
+
+func contains[T comparable](values []T, value T) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+func TestSchemas_VersionsAreBackwardCompatible(t *testing.T) {
+	schemas := DefaultSchemas()
+
+	for eventType := range schemas.events {
+		versions := schemas.Versions(eventType)
+		assert.Equal(t, 1, versions[0], "%s versions should start at 1", eventType)
+
+		for i := 1; i < len(versions); i++ {
+			assert.Equal(t, versions[i-1]+1, versions[i], "%s skips a version", eventType)
+
+			previous, next := readSchema(t, eventType, versions[i-1]), readSchema(t, eventType, versions[i])
+			assert.Empty(t, breakingChanges(eventType, previous, next), "%s v%d breaks v%d consumers", eventType, versions[i], versions[i-1])
+		}
+	}
+}
+
+func TestSchemas_ExamplesStayValid(t *testing.T) {
+	schemas := DefaultSchemas()
+
+	for eventType := range schemas.events {
+		for _, version := range schemas.Versions(eventType) {
+			directory := path.Join("testdata/events", eventType, "v"+strconv.Itoa(version))
+			entries, err := os.ReadDir(directory)
+			require.NoError(t, err, "add example %s v%d events to %s", eventType, version, directory)
+			require.NotEmpty(t, entries, "add example %s v%d events to %s", eventType, version, directory)
+
+			for _, entry := range entries {
+				example, err := os.ReadFile(path.Join(directory, entry.Name()))
+				require.NoError(t, err)
+
+				// Events published under this version must still be accepted as this version
+				assert.NoError(t, schemas.Validate(eventType, version, example), "%s/%s", directory, entry.Name())
+			}
+		}
+	}
+}
+
+func TestSchemas_ExamplesMatchSchemaFiles(t *testing.T) {
+	directories, err := os.ReadDir("testdata/events")
+	require.NoError(t, err)
+
+	var withExamples, withSchemas []string
+	for _, directory := range directories {
+		withExamples = append(withExamples, directory.Name())
+	}
+	for eventType := range DefaultSchemas().events {
+		withSchemas = append(withSchemas, eventType)
+	}
+	sort.Strings(withSchemas)
+
+	// A deleted schema would strand events already published under it
+	assert.Equal(t, withExamples, withSchemas, "every event type with examples needs schemas")
+}
+
+func TestSchemas_Validate(t *testing.T) {
+	schemas := DefaultSchemas()
+
+	err := schemas.Validate("user.created", 1, []byte(`{"user_id": ""}`))
+	var validationErr *SchemaValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.True(t, strings.Contains(validationErr.Error(), "user_id"), validationErr.Error())
+
+	assert.Error(t, schemas.Validate("user.created", 99, []byte(`{"user_id": "1"}`)))
+	assert.Error(t, schemas.Validate("user.unknown", 1, []byte(`{}`)))
+}
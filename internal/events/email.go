@@ -0,0 +1,24 @@
+package events
+
+import (
+	"time"
+)
+
+const EmailVerificationRequestedName = "email.verification_requested"
+
+// EmailVerificationRequested asks the mailer to send Token to Email. The link it sends should
+// pass the token to the VerifyEmail mutation.
+type EmailVerificationRequested struct {
+	UserID    string    `json:"user_id"`
+	Email     string    `json:"email"`
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+func (EmailVerificationRequested) Name() string {
+	return EmailVerificationRequestedName
+}
+
+func (EmailVerificationRequested) Version() int {
+	return 1
+}
@@ -0,0 +1,20 @@
+package events
+
+const GuestUpgradedName = "guest.upgraded"
+
+// GuestUpgraded tells other services that GuestID signed up as UserID, so anything they keep for
+// the guest can move to the new user. Settings lists the setting keys carried over.
+type GuestUpgraded struct {
+	GuestID  string   `json:"guest_id"`
+	UserID   string   `json:"user_id"`
+	Language string   `json:"language,omitempty"`
+	Settings []string `json:"settings"`
+}
+
+func (GuestUpgraded) Name() string {
+	return GuestUpgradedName
+}
+
+func (GuestUpgraded) Version() int {
+	return 1
+}
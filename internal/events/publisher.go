@@ -0,0 +1,109 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+
+	"github.com/ThatCatDev/ep/v2/drivers"
+	epKafka "github.com/ThatCatDev/ep/v2/drivers/kafka"
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+	"github.com/weeb-vip/user-service/config"
+	"github.com/weeb-vip/user-service/internal/eventing"
+	"github.com/weeb-vip/user-service/internal/logger"
+	"github.com/weeb-vip/user-service/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Publisher sends events about users to other services.
+type Publisher interface {
+	// Publish sends event in an eventing.Envelope, keyed so that events for the same key stay in
+	// order.
+	Publish(ctx context.Context, key string, event Event) error
+}
+
+// Event is a message this service publishes. Name and Version identify its schema in
+// internal/eventing/schemas.
+type Event interface {
+	Name() string
+	Version() int
+}
+
+type kafkaPublisher struct {
+	producer string
+	config   config.KafkaConfig
+	mu       sync.Mutex
+	driver   drivers.Driver[*kafka.Message]
+}
+
+// NewPublisher publishes to KafkaConfig.ProducerTopic as producer, or only logs events when no
+// producer topic is configured.
+func NewPublisher(producer string, cfg config.KafkaConfig) Publisher {
+	if cfg.ProducerTopic == "" || cfg.ProducerTopic == "nil" {
+		return noopPublisher{}
+	}
+
+	return &kafkaPublisher{producer: producer, config: cfg}
+}
+
+func (p *kafkaPublisher) Publish(ctx context.Context, key string, event Event) error {
+	tracer := tracing.GetTracer(ctx)
+	ctx, span := tracer.Start(ctx, "events.Publish",
+		trace.WithAttributes(
+			attribute.String("event.name", event.Name()),
+			attribute.String("messaging.destination", p.config.ProducerTopic),
+		),
+		tracing.GetEnvironmentAttribute(),
+	)
+	defer span.End()
+
+	envelope, err := eventing.NewEnvelope(event.Name(), event.Version(), p.producer, event)
+	if err != nil {
+		return err
+	}
+	span.SetAttributes(attribute.String("event.id", envelope.EventID))
+
+	body, err := json.Marshal(envelope)
+	if err != nil {
+		return err
+	}
+
+	// Let consumers continue this trace
+	carrier := propagation.MapCarrier{}
+	propagation.TraceContext{}.Inject(ctx, carrier)
+	headers := []kafka.Header{
+		{Key: "event", Value: []byte(event.Name())},
+	}
+	for key, value := range carrier {
+		headers = append(headers, kafka.Header{Key: key, Value: []byte(value)})
+	}
+
+	return p.getDriver().Produce(ctx, p.config.ProducerTopic, &kafka.Message{
+		Key:     []byte(key),
+		Value:   body,
+		Headers: headers,
+	})
+}
+
+// getDriver connects on first use so that handlers which never publish don't need Kafka.
+func (p *kafkaPublisher) getDriver() drivers.Driver[*kafka.Message] {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.driver == nil {
+		p.driver = epKafka.NewKafkaDriver(eventing.DriverConfig(p.config))
+	}
+
+	return p.driver
+}
+
+type noopPublisher struct{}
+
+func (noopPublisher) Publish(ctx context.Context, key string, event Event) error {
+	log := logger.FromCtx(ctx)
+	log.Warn().Str("event", event.Name()).Str("key", key).Msg("No producer topic configured, dropping event")
+
+	return nil
+}
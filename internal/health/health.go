@@ -0,0 +1,193 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+)
+
+type Status string
+
+const (
+	StatusUp   Status = "up"
+	StatusDown Status = "down"
+	// StatusDraining is reported by readiness while the service shuts down.
+	StatusDraining Status = "draining"
+)
+
+// Checker returns an error when the dependency it checks is unusable. It should give up once ctx
+// is done.
+type Checker func(ctx context.Context) error
+
+type Check struct {
+	Name    string
+	Checker Checker
+	// Liveness checks also decide /livez. Only use it for failures a restart fixes; a database
+	// outage would otherwise restart every replica at once.
+	Liveness bool
+}
+
+// Result is the outcome of one check. Error is only shown in the verbose view.
+type Result struct {
+	Status     Status    `json:"status"`
+	Error      string    `json:"error,omitempty"`
+	DurationMs int64     `json:"duration_ms"`
+	CheckedAt  time.Time `json:"checked_at"`
+}
+
+type Report struct {
+	Status Status            `json:"status"`
+	Checks map[string]Result `json:"checks,omitempty"`
+}
+
+// Health runs checks with a timeout each and caches their results, so frequent probes from
+// several sources don't load the dependencies.
+type Health struct {
+	timeout  time.Duration
+	cacheFor time.Duration
+	draining func() bool
+
+	checks []*cachedCheck
+}
+
+type cachedCheck struct {
+	Check
+
+	mu     sync.Mutex
+	result *Result
+}
+
+// New returns a Health whose readiness also fails while draining reports true.
+func New(timeout time.Duration, cacheFor time.Duration, draining func() bool) *Health {
+	return &Health{timeout: timeout, cacheFor: cacheFor, draining: draining}
+}
+
+// Register adds check. Register every check before serving.
+func (h *Health) Register(check Check) {
+	h.checks = append(h.checks, &cachedCheck{Check: check})
+}
+
+// Readiness runs every check.
+func (h *Health) Readiness(ctx context.Context) Report {
+	report := h.run(ctx, false)
+	if h.draining != nil && h.draining() {
+		report.Status = StatusDraining
+	}
+
+	return report
+}
+
+// Liveness runs only the liveness checks.
+func (h *Health) Liveness(ctx context.Context) Report {
+	return h.run(ctx, true)
+}
+
+func (h *Health) run(ctx context.Context, livenessOnly bool) Report {
+	report := Report{Status: StatusUp, Checks: map[string]Result{}}
+
+	var (
+		wg sync.WaitGroup
+		mu sync.Mutex
+	)
+	for _, check := range h.checks {
+		if livenessOnly && !check.Liveness {
+			continue
+		}
+
+		wg.Add(1)
+		go func(check *cachedCheck) {
+			defer wg.Done()
+			result := h.result(ctx, check)
+
+			mu.Lock()
+			defer mu.Unlock()
+			report.Checks[check.Name] = result
+			if result.Status != StatusUp {
+				report.Status = StatusDown
+			}
+		}(check)
+	}
+	wg.Wait()
+
+	return report
+}
+
+// result returns the cached result of check or, once that is stale, runs it again. Concurrent
+// callers wait for the same run.
+func (h *Health) result(ctx context.Context, check *cachedCheck) Result {
+	check.mu.Lock()
+	defer check.mu.Unlock()
+
+	if check.result != nil && time.Since(check.result.CheckedAt) < h.cacheFor {
+		return *check.result
+	}
+
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), h.timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := check.Checker(ctx)
+	if err == nil && ctx.Err() != nil {
+		err = ctx.Err()
+	}
+
+	result := Result{Status: StatusUp, DurationMs: time.Since(start).Milliseconds(), CheckedAt: start}
+	if err != nil {
+		result.Status = StatusDown
+		result.Error = err.Error()
+	}
+	check.result = &result
+
+	return result
+}
+
+// ReadyHandler serves /readyz, returning 503 unless every check passes and the service isn't
+// draining.
+func (h *Health) ReadyHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeReport(w, h.Readiness(r.Context()), false)
+	})
+}
+
+// LiveHandler serves /livez, returning 503 unless every liveness check passes.
+func (h *Health) LiveHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeReport(w, h.Liveness(r.Context()), false)
+	})
+}
+
+// HealthHandler serves /healthz for operators. With ?verbose it includes each check's error,
+// duration and when it last ran.
+func (h *Health) HealthHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, verbose := r.URL.Query()["verbose"]
+		writeReport(w, h.Readiness(r.Context()), verbose)
+	})
+}
+
+// summary is the non-verbose view of a Report.
+type summary struct {
+	Status Status            `json:"status"`
+	Checks map[string]Status `json:"checks,omitempty"`
+}
+
+func writeReport(w http.ResponseWriter, report Report, verbose bool) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	if report.Status != StatusUp {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+
+	if verbose {
+		_ = json.NewEncoder(w).Encode(report) // nolint
+		return
+	}
+
+	view := summary{Status: report.Status, Checks: map[string]Status{}}
+	for name, result := range report.Checks {
+		view.Checks[name] = result.Status
+	}
+	_ = json.NewEncoder(w).Encode(view) // nolint
+}
@@ -0,0 +1,81 @@
+package health_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/weeb-vip/user-service/internal/health"
+)
+
+func TestHealth_Readiness(t *testing.T) {
+	calls := 0
+	checks := health.New(time.Second, time.Minute, nil)
+	checks.Register(health.Check{Name: "database", Checker: func(context.Context) error {
+		calls++
+		return nil
+	}})
+	checks.Register(health.Check{Name: "storage", Checker: func(context.Context) error {
+		return errors.New("connection refused")
+	}})
+
+	report := checks.Readiness(context.Background())
+	assert.Equal(t, health.StatusDown, report.Status)
+	assert.Equal(t, health.StatusUp, report.Checks["database"].Status)
+	assert.Equal(t, "connection refused", report.Checks["storage"].Error)
+
+	// Results are cached
+	checks.Readiness(context.Background())
+	assert.Equal(t, 1, calls)
+}
+
+func TestHealth_Timeout(t *testing.T) {
+	checks := health.New(10*time.Millisecond, 0, nil)
+	checks.Register(health.Check{Name: "kafka", Checker: func(ctx context.Context) error {
+		<-ctx.Done()
+		return nil
+	}})
+
+	report := checks.Readiness(context.Background())
+
+	assert.Equal(t, health.StatusDown, report.Status)
+	assert.Contains(t, report.Checks["kafka"].Error, "deadline exceeded")
+}
+
+func TestHealth_Liveness(t *testing.T) {
+	draining := false
+	checks := health.New(time.Second, 0, func() bool { return draining })
+	checks.Register(health.Check{Name: "database", Checker: func(context.Context) error { return errors.New("down") }})
+	checks.Register(health.Check{Name: "signing_key", Checker: func(context.Context) error { return nil }, Liveness: true})
+
+	live := checks.Liveness(context.Background())
+	assert.Equal(t, health.StatusUp, live.Status)
+	assert.Len(t, live.Checks, 1, "only liveness checks decide /livez")
+
+	draining = true
+	assert.Equal(t, health.StatusDraining, checks.Readiness(context.Background()).Status)
+	assert.Equal(t, health.StatusUp, checks.Liveness(context.Background()).Status)
+}
+
+func TestHealth_Handlers(t *testing.T) {
+	checks := health.New(time.Second, 0, nil)
+	checks.Register(health.Check{Name: "database", Checker: func(context.Context) error { return errors.New("dial tcp: refused") }})
+
+	recorder := httptest.NewRecorder()
+	checks.ReadyHandler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+	assert.JSONEq(t, `{"status":"down","checks":{"database":"down"}}`, recorder.Body.String())
+
+	recorder = httptest.NewRecorder()
+	checks.HealthHandler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/healthz?verbose", nil))
+	var report health.Report
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &report))
+	assert.Equal(t, "dial tcp: refused", report.Checks["database"].Error)
+	assert.False(t, report.Checks["database"].CheckedAt.IsZero())
+}
@@ -0,0 +1,158 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+
+	"github.com/weeb-vip/user-service/internal/keypair"
+)
+
+// JWK is a single public key in RFC 7517 form. RSA keys use N and E, EC keys Curve, X and Y,
+// and Ed25519 keys (kty OKP, RFC 8037) Curve and X.
+type JWK struct {
+	KeyType   string `json:"kty"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	KeyID     string `json:"kid"`
+	N         string `json:"n,omitempty"`
+	E         string `json:"e,omitempty"`
+	Curve     string `json:"crv,omitempty"`
+	X         string `json:"x,omitempty"`
+	Y         string `json:"y,omitempty"`
+}
+
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+// NewJWKSet converts the keys from source, newest first, into a JSON Web Key Set.
+func NewJWKSet(source PublicKeySource) (JWKSet, error) {
+	set := JWKSet{Keys: []JWK{}}
+	for _, publicKey := range source.PublicKeys() {
+		jwk, err := newJWK(publicKey)
+		if err != nil {
+			return JWKSet{}, fmt.Errorf("key %s: %w", publicKey.ID, err)
+		}
+
+		set.Keys = append(set.Keys, jwk)
+	}
+
+	return set, nil
+}
+
+func newJWK(publicKey keypair.PublicKey) (JWK, error) {
+	parsed, err := parsePublicKey(publicKey)
+	if err != nil {
+		return JWK{}, err
+	}
+
+	method, err := signingMethod(publicKey.Algorithm)
+	if err != nil {
+		return JWK{}, err
+	}
+
+	jwk := JWK{Use: "sig", Algorithm: method.Alg(), KeyID: publicKey.ID}
+	switch key := parsed.(type) {
+	case *rsa.PublicKey:
+		jwk.KeyType = "RSA"
+		jwk.N = encode(key.N.Bytes())
+		jwk.E = encode(big.NewInt(int64(key.E)).Bytes())
+	case *ecdsa.PublicKey:
+		size := (key.Curve.Params().BitSize + 7) / 8
+		jwk.KeyType = "EC"
+		jwk.Curve = key.Curve.Params().Name
+		jwk.X = encode(key.X.FillBytes(make([]byte, size)))
+		jwk.Y = encode(key.Y.FillBytes(make([]byte, size)))
+	case ed25519.PublicKey:
+		jwk.KeyType = "OKP"
+		jwk.Curve = "Ed25519"
+		jwk.X = encode(key)
+	default:
+		return JWK{}, fmt.Errorf("unsupported key type %T", parsed)
+	}
+
+	return jwk, nil
+}
+
+// Key returns the crypto key described by jwk.
+func (jwk JWK) Key() (crypto.PublicKey, error) {
+	switch jwk.KeyType {
+	case "RSA":
+		n, err := decode(jwk.N)
+		if err != nil {
+			return nil, fmt.Errorf("invalid modulus: %w", err)
+		}
+		e, err := decode(jwk.E)
+		if err != nil {
+			return nil, fmt.Errorf("invalid exponent: %w", err)
+		}
+
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}, nil
+	case "EC":
+		if jwk.Curve != elliptic.P256().Params().Name {
+			return nil, fmt.Errorf("unsupported curve %s", jwk.Curve)
+		}
+		x, err := decode(jwk.X)
+		if err != nil {
+			return nil, fmt.Errorf("invalid x coordinate: %w", err)
+		}
+		y, err := decode(jwk.Y)
+		if err != nil {
+			return nil, fmt.Errorf("invalid y coordinate: %w", err)
+		}
+
+		key := &ecdsa.PublicKey{Curve: elliptic.P256(), X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		if !key.Curve.IsOnCurve(key.X, key.Y) {
+			return nil, fmt.Errorf("point is not on curve %s", jwk.Curve)
+		}
+
+		return key, nil
+	case "OKP":
+		if jwk.Curve != "Ed25519" {
+			return nil, fmt.Errorf("unsupported curve %s", jwk.Curve)
+		}
+		x, err := decode(jwk.X)
+		if err != nil {
+			return nil, fmt.Errorf("invalid public key: %w", err)
+		}
+		if len(x) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("invalid Ed25519 public key length %d", len(x))
+		}
+
+		return ed25519.PublicKey(x), nil
+	}
+
+	return nil, fmt.Errorf("unsupported key type %s", jwk.KeyType)
+}
+
+func encode(value []byte) string {
+	return base64.RawURLEncoding.EncodeToString(value)
+}
+
+func decode(value string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(value)
+}
+
+// JWKSHandler serves the keys from source at /.well-known/jwks.json.
+func JWKSHandler(source PublicKeySource) http.Handler {
+	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		set, err := NewJWKSet(source)
+		if err != nil {
+			http.Error(writer, "failed to encode keys", http.StatusInternalServerError)
+			return
+		}
+
+		writer.Header().Set("Content-Type", "application/json")
+		// Short enough that verifiers pick up a new key well before the previous one leaves the window
+		writer.Header().Set("Cache-Control", "public, max-age=60")
+		_ = json.NewEncoder(writer).Encode(set)
+	})
+}
@@ -1,6 +1,7 @@
 package jwt
 
 import (
+	"strings"
 	"time"
 
 	"github.com/weeb-vip/user-service/internal/keypair"
@@ -13,10 +14,15 @@ const minJWTTokenValidityMinutes = 15
 func (t tokenizer) Tokenize(claims Claims) (string, error) {
 	signingKey := t.signingKey.GetLatest()
 
-	token := jwt.NewWithClaims(jwt.SigningMethodRS256, buildClaims(claims))
+	method, err := signingMethod(signingKey.Algorithm)
+	if err != nil {
+		return "", err
+	}
+
+	token := jwt.NewWithClaims(method, buildClaims(claims))
 	token.Header["kid"] = signingKey.ID
 
-	signKey, err := jwt.ParseRSAPrivateKeyFromPEM([]byte(signingKey.Key))
+	signKey, err := parsePrivateKey(signingKey.Key)
 	if err != nil {
 		return "", err
 	}
@@ -29,6 +35,25 @@ func (t tokenizer) Tokenize(claims Claims) (string, error) {
 	return signedToken, nil
 }
 
+// ParseUnverified decodes the claims of token without checking its signature or expiry. Callers
+// must establish trust some other way, such as matching the token against one they stored when
+// it was issued.
+func ParseUnverified(token string) (*UnverifiedClaims, error) {
+	claims := jwt.MapClaims{}
+	if _, _, err := jwt.NewParser().ParseUnverified(token, claims); err != nil {
+		return nil, err
+	}
+
+	result := &UnverifiedClaims{}
+	result.Subject, _ = claims["sub"].(string)
+	result.Purpose, _ = claims["purpose"].(string)
+	if exp, ok := claims["exp"].(float64); ok {
+		result.ExpiresAt = time.Unix(int64(exp), 0)
+	}
+
+	return result, nil
+}
+
 func New(key keypair.RotatingSigningKey) Tokenizer {
 	return tokenizer{signingKey: key}
 }
@@ -44,14 +69,18 @@ func getDefault(duration *time.Duration, defaultDuration time.Duration) time.Dur
 func buildClaims(srcClaims Claims) jwt.MapClaims {
 	mapClaims := jwt.MapClaims{
 		"nbf": time.Now().Unix(),
-		"iss": "ircforeverservices",
-		"aud": "ircforeverusers",
+		"iss": Issuer,
+		"aud": Audience,
 		"iat": time.Now().Unix(),
 	}
 
 	mapClaims = addIfNotNil(mapClaims, srcClaims.Subject, "sub")
 	mapClaims = addIfNotNil(mapClaims, srcClaims.Purpose, "purpose")
 	mapClaims = addIfNotNil(mapClaims, srcClaims.RefreshToken, "refresh_token")
+	mapClaims = addIfNotNil(mapClaims, srcClaims.Audience, "aud")
+	if len(srcClaims.Scopes) > 0 {
+		mapClaims["scope"] = strings.Join(srcClaims.Scopes, " ")
+	}
 	mapClaims["exp"] = time.
 		Now().
 		Add(getDefault(srcClaims.TTL, time.Minute*time.Duration(minJWTTokenValidityMinutes))).
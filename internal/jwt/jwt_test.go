@@ -1,13 +1,16 @@
 package jwt_test
 
 import (
+	"context"
 	"encoding/base64"
 	"encoding/json"
+	"net/http/httptest"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/weeb-vip/user-service/internal/jwt"
 	"github.com/weeb-vip/user-service/internal/keypair"
 )
@@ -18,14 +21,18 @@ type mockSigningKeyStruct struct {
 
 func (m mockSigningKeyStruct) Rotate() {}
 
-func (m mockSigningKeyStruct) RotateInBackground(every time.Duration) { return }
+func (m mockSigningKeyStruct) RotateInBackground(ctx context.Context, every time.Duration) { return }
 
 func (m mockSigningKeyStruct) GetLatest() keypair.SigningKey {
 	return m.key
 }
 
+func (m mockSigningKeyStruct) PublicKeys() []keypair.PublicKey {
+	return nil
+}
+
 func TestNew(t *testing.T) {
-	keyPair, keyGenerateError := keypair.GenerateKeyPair()
+	keyPair, keyGenerateError := keypair.GenerateKeyPair(keypair.AlgorithmRS256)
 	assert.NoError(t, keyGenerateError)
 	t.Run("signed JWT", func(t *testing.T) {
 		tokenizer := jwt.New(mockSigningKeyStruct{key: keypair.SigningKey{Key: keyPair.PrivateKey, ID: "key_id"}})
@@ -75,6 +82,57 @@ func TestNew(t *testing.T) {
 	})
 }
 
+func TestParseUnverified(t *testing.T) {
+	keyPair, err := keypair.GenerateKeyPair(keypair.AlgorithmRS256)
+	assert.NoError(t, err)
+	tokenizer := jwt.New(mockSigningKeyStruct{key: keypair.SigningKey{Key: keyPair.PrivateKey, ID: "key_id"}})
+
+	t.Run("reads subject, purpose and expiry", func(t *testing.T) {
+		token, err := tokenizer.Tokenize(jwt.Claims{
+			Subject: getPointer("user_1"),
+			TTL:     getPointer(time.Hour),
+			Purpose: getPointer(jwt.PurposeEmailVerification),
+		})
+		assert.NoError(t, err)
+
+		claims, err := jwt.ParseUnverified(token)
+		assert.NoError(t, err)
+		assert.Equal(t, "user_1", claims.Subject)
+		assert.Equal(t, jwt.PurposeEmailVerification, claims.Purpose)
+		assert.Equal(t, time.Now().Add(time.Hour).Unix(), claims.ExpiresAt.Unix())
+	})
+	t.Run("rejects malformed tokens", func(t *testing.T) {
+		_, err := jwt.ParseUnverified("not-a-token")
+		assert.Error(t, err)
+	})
+}
+
+func TestTokenize_Algorithms(t *testing.T) {
+	for _, algorithm := range keypair.Algorithms {
+		t.Run(string(algorithm), func(t *testing.T) {
+			rotator := newRotator(t, 2, keypair.WithAlgorithm(algorithm))
+			token, err := jwt.New(rotator).Tokenize(jwt.Claims{Subject: getPointer("user_1")})
+			require.NoError(t, err)
+
+			header, err := base64.RawURLEncoding.DecodeString(strings.Split(token, ".")[0])
+			require.NoError(t, err)
+			headerMap := map[string]interface{}{}
+			require.NoError(t, json.Unmarshal(header, &headerMap))
+			assert.Equal(t, string(algorithm), headerMap["alg"])
+
+			claims, err := jwt.NewVerifier(jwt.NewKeySet(rotator)).Verify(context.Background(), token)
+			require.NoError(t, err)
+			assert.Equal(t, "user_1", claims.Subject)
+
+			server := httptest.NewServer(jwt.JWKSHandler(rotator))
+			defer server.Close()
+			claims, err = jwt.NewVerifier(jwt.NewRemoteKeySet(server.URL, time.Minute, server.Client())).Verify(context.Background(), token)
+			require.NoError(t, err)
+			assert.Equal(t, "user_1", claims.Subject)
+		})
+	}
+}
+
 func getPointer[T any](val T) *T {
 	return &val
 }
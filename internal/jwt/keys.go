@@ -0,0 +1,74 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/weeb-vip/user-service/internal/keypair"
+)
+
+// signingMethods maps every supported algorithm to the method that signs and verifies it.
+var signingMethods = map[keypair.Algorithm]jwt.SigningMethod{
+	keypair.AlgorithmRS256: jwt.SigningMethodRS256,
+	keypair.AlgorithmES256: jwt.SigningMethodES256,
+	keypair.AlgorithmEdDSA: jwt.SigningMethodEdDSA,
+}
+
+func signingMethod(algorithm keypair.Algorithm) (jwt.SigningMethod, error) {
+	if algorithm == "" {
+		// Keys from before algorithms were configurable are all RSA
+		algorithm = keypair.AlgorithmRS256
+	}
+
+	method, found := signingMethods[algorithm]
+	if !found {
+		return nil, fmt.Errorf("unsupported signing algorithm %q", algorithm)
+	}
+
+	return method, nil
+}
+
+func validMethods() []string {
+	methods := make([]string, 0, len(keypair.Algorithms))
+	for _, algorithm := range keypair.Algorithms {
+		methods = append(methods, signingMethods[algorithm].Alg())
+	}
+
+	return methods
+}
+
+func parsePrivateKey(encoded string) (crypto.Signer, error) {
+	block, _ := pem.Decode([]byte(encoded))
+	if block == nil {
+		return nil, errors.New("private key is not PEM encoded")
+	}
+
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		// Older RSA keys may still be PKCS #1
+		if rsaKey, rsaErr := x509.ParsePKCS1PrivateKey(block.Bytes); rsaErr == nil {
+			return rsaKey, nil
+		}
+		return nil, err
+	}
+
+	signer, ok := parsed.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("unsupported private key type %T", parsed)
+	}
+
+	return signer, nil
+}
+
+func parsePublicKey(publicKey keypair.PublicKey) (crypto.PublicKey, error) {
+	block, _ := pem.Decode([]byte(publicKey.Key))
+	if block == nil {
+		return nil, errors.New("public key is not PEM encoded")
+	}
+
+	return x509.ParsePKIXPublicKey(block.Bytes)
+}
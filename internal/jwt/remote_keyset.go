@@ -0,0 +1,159 @@
+package jwt
+
+import (
+	"context"
+	"crypto"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/weeb-vip/user-service/internal/logger"
+)
+
+// minRefetchInterval stops tokens with made up kids from turning into a request per token
+// against the key-management service.
+const minRefetchInterval = 10 * time.Second
+
+type remoteKeySet struct {
+	url      string
+	cacheFor time.Duration
+	client   *http.Client
+
+	mu          sync.Mutex
+	keys        map[string]crypto.PublicKey
+	fetchedAt   time.Time
+	attemptedAt time.Time
+	pending     *pendingFetch
+}
+
+// pendingFetch is a JWKS request in flight. Callers that need the set while it runs wait for it
+// instead of starting their own.
+type pendingFetch struct {
+	done chan struct{}
+	err  error
+}
+
+// NewRemoteKeySet resolves keys from the JWKS served at url. The set is cached for cacheFor and
+// refetched early when a token names a kid it hasn't seen, so keys published by a rotation are
+// picked up straight away.
+func NewRemoteKeySet(url string, cacheFor time.Duration, client *http.Client) KeySet {
+	if client == nil {
+		client = &http.Client{Timeout: 5 * time.Second}
+	}
+
+	return &remoteKeySet{
+		url:      url,
+		cacheFor: cacheFor,
+		client:   client,
+		keys:     map[string]crypto.PublicKey{},
+	}
+}
+
+func (r *remoteKeySet) Key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	r.mu.Lock()
+	key, found := r.keys[kid]
+	if found && time.Since(r.fetchedAt) < r.cacheFor {
+		r.mu.Unlock()
+		return key, nil
+	}
+
+	pending := r.pending
+	if pending == nil {
+		// Failed attempts count too, or an outage would turn every token into a request
+		if time.Since(r.attemptedAt) < minRefetchInterval {
+			r.mu.Unlock()
+			if found {
+				return key, nil
+			}
+			return nil, fmt.Errorf("%w: %s", ErrUnknownKeyID, kid)
+		}
+
+		pending = &pendingFetch{done: make(chan struct{})}
+		r.pending = pending
+		r.attemptedAt = time.Now()
+		r.mu.Unlock()
+
+		r.refresh(ctx, pending)
+	} else {
+		r.mu.Unlock()
+
+		select {
+		case <-pending.done:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	r.mu.Lock()
+	key, found = r.keys[kid]
+	r.mu.Unlock()
+
+	if pending.err != nil {
+		if found {
+			// A stale key beats failing every request while the key-management service is down
+			log := logger.FromCtx(ctx)
+			log.Warn().Err(pending.err).Str("url", r.url).Msg("failed to refresh JWKS, using cached keys")
+			return key, nil
+		}
+		return nil, pending.err
+	}
+
+	if !found {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownKeyID, kid)
+	}
+
+	return key, nil
+}
+
+// refresh fetches the set without holding the lock, so verifying tokens with cached keys never
+// waits on the key-management service.
+func (r *remoteKeySet) refresh(ctx context.Context, pending *pendingFetch) {
+	keys, err := r.fetch(ctx)
+
+	r.mu.Lock()
+	if err == nil {
+		r.keys = keys
+		r.fetchedAt = time.Now()
+	}
+	pending.err = err
+	r.pending = nil
+	r.mu.Unlock()
+
+	close(pending.done)
+}
+
+func (r *remoteKeySet) fetch(ctx context.Context) (map[string]crypto.PublicKey, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, r.url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := r.client.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch JWKS: %w", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch JWKS: unexpected status %d", response.StatusCode)
+	}
+
+	var set JWKSet
+	if err := json.NewDecoder(response.Body).Decode(&set); err != nil {
+		return nil, fmt.Errorf("failed to decode JWKS: %w", err)
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(set.Keys))
+	for _, jwk := range set.Keys {
+		key, err := jwk.Key()
+		if err != nil {
+			// Skip keys we can't use rather than rejecting the whole set
+			continue
+		}
+		keys[jwk.KeyID] = key
+	}
+
+	return keys, nil
+}
@@ -0,0 +1,112 @@
+package jwt_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/weeb-vip/user-service/internal/jwt"
+)
+
+func TestRemoteKeySet(t *testing.T) {
+	rotator := newRotator(t, 2)
+	var requests atomic.Int32
+	jwks := jwt.JWKSHandler(rotator)
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		requests.Add(1)
+		jwks.ServeHTTP(writer, request)
+	}))
+	defer server.Close()
+
+	tokenizer := jwt.New(rotator)
+	verifier := jwt.NewVerifier(jwt.NewRemoteKeySet(server.URL, time.Hour, server.Client()))
+
+	token, err := tokenizer.Tokenize(jwt.Claims{Subject: getPointer("user_1")})
+	require.NoError(t, err)
+
+	claims, err := verifier.Verify(context.Background(), token)
+	require.NoError(t, err)
+	assert.Equal(t, "user_1", claims.Subject)
+
+	_, err = verifier.Verify(context.Background(), token)
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), requests.Load(), "known keys are served from cache")
+
+	t.Run("unknown kids are not refetched straight away", func(t *testing.T) {
+		rotator.Rotate()
+		rotated, err := tokenizer.Tokenize(jwt.Claims{Subject: getPointer("user_2")})
+		require.NoError(t, err)
+
+		_, err = verifier.Verify(context.Background(), rotated)
+		assert.ErrorIs(t, err, jwt.ErrUnknownKeyID)
+		assert.Equal(t, int32(1), requests.Load())
+	})
+}
+
+func TestRemoteKeySet_Unavailable(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, _ *http.Request) {
+		writer.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	_, err := jwt.NewRemoteKeySet(server.URL, time.Hour, server.Client()).Key(context.Background(), "key_1")
+	assert.ErrorContains(t, err, "unexpected status 503")
+}
+
+func TestRemoteKeySet_FailedFetchIsThrottled(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, _ *http.Request) {
+		requests.Add(1)
+		writer.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	keys := jwt.NewRemoteKeySet(server.URL, time.Hour, server.Client())
+	_, err := keys.Key(context.Background(), "key_1")
+	assert.ErrorContains(t, err, "unexpected status 503")
+
+	_, err = keys.Key(context.Background(), "key_2")
+	assert.ErrorIs(t, err, jwt.ErrUnknownKeyID)
+	assert.Equal(t, int32(1), requests.Load())
+}
+
+func TestRemoteKeySet_ConcurrentCallersShareAFetch(t *testing.T) {
+	rotator := newRotator(t, 2)
+	var requests atomic.Int32
+	release := make(chan struct{})
+	jwks := jwt.JWKSHandler(rotator)
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		requests.Add(1)
+		<-release
+		jwks.ServeHTTP(writer, request)
+	}))
+	defer server.Close()
+
+	tokenizer := jwt.New(rotator)
+	verifier := jwt.NewVerifier(jwt.NewRemoteKeySet(server.URL, time.Hour, server.Client()))
+	token, err := tokenizer.Tokenize(jwt.Claims{Subject: getPointer("user_1")})
+	require.NoError(t, err)
+
+	const callers = 5
+	errs := make(chan error, callers)
+	for i := 0; i < callers; i++ {
+		go func() {
+			_, err := verifier.Verify(context.Background(), token)
+			errs <- err
+		}()
+	}
+
+	require.Eventually(t, func() bool { return requests.Load() == 1 }, time.Second, 10*time.Millisecond)
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+
+	for i := 0; i < callers; i++ {
+		assert.NoError(t, <-errs)
+	}
+	assert.Equal(t, int32(1), requests.Load())
+}
@@ -6,6 +6,27 @@ import (
 	"github.com/weeb-vip/user-service/internal/keypair"
 )
 
+const (
+	// Issuer is the iss claim on every token this service signs.
+	Issuer = "ircforeverservices"
+	// Audience is the default aud claim on tokens this service signs.
+	Audience = "ircforeverusers"
+	// EmailVerificationAudience is the aud claim on email verification tokens, so that verifiers
+	// expecting Audience never accept them.
+	EmailVerificationAudience = "ircforeverusers:email_verification"
+)
+
+const (
+	// PurposeLogin marks the tokens users sign in with.
+	PurposeLogin = "login"
+	// PurposeEmailVerification marks tokens that confirm the holder owns the email on their account.
+	PurposeEmailVerification = "email_verification"
+	// PurposeInternal marks tokens held by our own backends, such as the BFF, rather than by users.
+	PurposeInternal = "internal"
+	// PurposeService marks tokens minted for calling another service on behalf of a user.
+	PurposeService = "service"
+)
+
 type tokenizer struct {
 	signingKey keypair.RotatingSigningKey
 }
@@ -15,8 +36,29 @@ type Claims struct {
 	TTL          *time.Duration
 	Purpose      *string
 	RefreshToken *string
+	// Audience overrides the default aud claim, for tokens meant for another service.
+	Audience *string
+	// Scopes limit what the holder may do, sent as the space separated scope claim.
+	Scopes []string
 }
 
 type Tokenizer interface {
 	Tokenize(claims Claims) (string, error)
 }
+
+// UnverifiedClaims are read from a token without checking its signature.
+type UnverifiedClaims struct {
+	Subject   string
+	Purpose   string
+	ExpiresAt time.Time
+}
+
+// VerifiedClaims are read from a token whose signature, issuer, audience and validity window were checked.
+type VerifiedClaims struct {
+	Subject   string
+	Purpose   string
+	Scopes    []string
+	KeyID     string
+	IssuedAt  time.Time
+	ExpiresAt time.Time
+}
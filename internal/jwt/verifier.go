@@ -0,0 +1,147 @@
+package jwt
+
+import (
+	"context"
+	"crypto"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/weeb-vip/user-service/internal/keypair"
+)
+
+var (
+	ErrMissingKeyID     = errors.New("token has no kid header")
+	ErrUnknownKeyID     = errors.New("token was signed by an unknown key")
+	ErrInvalidIssuer    = errors.New("token has an unexpected issuer")
+	ErrInvalidAudience  = errors.New("token has an unexpected audience")
+	ErrTokenExpired     = errors.New("token is expired")
+	ErrTokenNotYetValid = errors.New("token is not valid yet")
+)
+
+// KeySet resolves the public key a token was signed with from its kid header.
+type KeySet interface {
+	Key(ctx context.Context, kid string) (crypto.PublicKey, error)
+}
+
+// PublicKeySource lists the public keys that may have signed a live token, such as a
+// keypair.RotatingSigningKey.
+type PublicKeySource interface {
+	PublicKeys() []keypair.PublicKey
+}
+
+type Verifier interface {
+	Verify(ctx context.Context, token string) (*VerifiedClaims, error)
+}
+
+type VerifierOption func(*verifier)
+
+// WithIssuer overrides the iss claim tokens must carry. It defaults to Issuer.
+func WithIssuer(issuer string) VerifierOption {
+	return func(v *verifier) {
+		v.issuer = issuer
+	}
+}
+
+// WithAudience overrides the aud claim tokens must carry. It defaults to Audience.
+func WithAudience(audience string) VerifierOption {
+	return func(v *verifier) {
+		v.audience = audience
+	}
+}
+
+// WithLeeway tolerates clock skew between the signer and the verifier when checking exp and nbf.
+func WithLeeway(leeway time.Duration) VerifierOption {
+	return func(v *verifier) {
+		v.leeway = leeway
+	}
+}
+
+type verifier struct {
+	keys     KeySet
+	issuer   string
+	audience string
+	leeway   time.Duration
+}
+
+func NewVerifier(keys KeySet, opts ...VerifierOption) Verifier {
+	v := &verifier{
+		keys:     keys,
+		issuer:   Issuer,
+		audience: Audience,
+	}
+	for _, opt := range opts {
+		opt(v)
+	}
+
+	return v
+}
+
+func (v *verifier) Verify(ctx context.Context, token string) (*VerifiedClaims, error) {
+	claims := jwt.MapClaims{}
+	parser := jwt.NewParser(jwt.WithValidMethods(validMethods()), jwt.WithoutClaimsValidation())
+
+	parsed, err := parser.ParseWithClaims(token, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		if kid == "" {
+			return nil, ErrMissingKeyID
+		}
+
+		return v.keys.Key(ctx, kid)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if !claims.VerifyIssuer(v.issuer, true) {
+		return nil, ErrInvalidIssuer
+	}
+	if !claims.VerifyAudience(v.audience, true) {
+		return nil, ErrInvalidAudience
+	}
+	if !claims.VerifyExpiresAt(now.Add(-v.leeway).Unix(), true) {
+		return nil, ErrTokenExpired
+	}
+	if !claims.VerifyNotBefore(now.Add(v.leeway).Unix(), false) {
+		return nil, ErrTokenNotYetValid
+	}
+
+	result := &VerifiedClaims{}
+	result.KeyID, _ = parsed.Header["kid"].(string)
+	result.Subject, _ = claims["sub"].(string)
+	result.Purpose, _ = claims["purpose"].(string)
+	if scope, ok := claims["scope"].(string); ok {
+		result.Scopes = strings.Fields(scope)
+	}
+	if iat, ok := claims["iat"].(float64); ok {
+		result.IssuedAt = time.Unix(int64(iat), 0)
+	}
+	if exp, ok := claims["exp"].(float64); ok {
+		result.ExpiresAt = time.Unix(int64(exp), 0)
+	}
+
+	return result, nil
+}
+
+type localKeySet struct {
+	source PublicKeySource
+}
+
+// NewKeySet resolves keys from source, so a verifier in the signing process accepts tokens from
+// every key still in the rotation window.
+func NewKeySet(source PublicKeySource) KeySet {
+	return localKeySet{source: source}
+}
+
+func (l localKeySet) Key(_ context.Context, kid string) (crypto.PublicKey, error) {
+	for _, publicKey := range l.source.PublicKeys() {
+		if publicKey.ID == kid {
+			return parsePublicKey(publicKey)
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrUnknownKeyID, kid)
+}
@@ -0,0 +1,161 @@
+package jwt_test
+
+import (
+	"context"
+	"crypto"
+	"fmt"
+	"testing"
+	"time"
+
+	jwtlib "github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/weeb-vip/user-service/internal/jwt"
+	"github.com/weeb-vip/user-service/internal/keypair"
+)
+
+func newRotator(t *testing.T, window int, opts ...keypair.RotatorOption) keypair.RotatingSigningKey {
+	count := 0
+	rotator, err := keypair.NewSigningKeyRotator(context.Background(), func(context.Context, string, keypair.Algorithm) (string, error) {
+		count++
+		return fmt.Sprintf("key_%d", count), nil
+	}, append([]keypair.RotatorOption{keypair.WithPublicKeyWindow(window)}, opts...)...)
+	require.NoError(t, err)
+
+	return rotator
+}
+
+func signClaims(t *testing.T, signingKey keypair.SigningKey, claims jwtlib.MapClaims) string {
+	token := jwtlib.NewWithClaims(jwtlib.SigningMethodRS256, claims)
+	token.Header["kid"] = signingKey.ID
+	key, err := jwtlib.ParseRSAPrivateKeyFromPEM([]byte(signingKey.Key))
+	require.NoError(t, err)
+	signed, err := token.SignedString(key)
+	require.NoError(t, err)
+
+	return signed
+}
+
+func TestVerifier_RotationOverlap(t *testing.T) {
+	rotator := newRotator(t, 2)
+	tokenizer := jwt.New(rotator)
+	verifier := jwt.NewVerifier(jwt.NewKeySet(rotator))
+
+	oldToken, err := tokenizer.Tokenize(jwt.Claims{Subject: getPointer("user_1")})
+	require.NoError(t, err)
+
+	rotator.Rotate()
+	newToken, err := tokenizer.Tokenize(jwt.Claims{Subject: getPointer("user_2")})
+	require.NoError(t, err)
+
+	t.Run("tokens from the current key verify", func(t *testing.T) {
+		claims, err := verifier.Verify(context.Background(), newToken)
+		require.NoError(t, err)
+		assert.Equal(t, "user_2", claims.Subject)
+		assert.Equal(t, "key_2", claims.KeyID)
+	})
+	t.Run("tokens from the previous key still verify", func(t *testing.T) {
+		claims, err := verifier.Verify(context.Background(), oldToken)
+		require.NoError(t, err)
+		assert.Equal(t, "user_1", claims.Subject)
+		assert.Equal(t, "key_1", claims.KeyID)
+	})
+	t.Run("tokens from a key outside the window are rejected", func(t *testing.T) {
+		rotator.Rotate()
+		_, err := verifier.Verify(context.Background(), oldToken)
+		assert.ErrorIs(t, err, jwt.ErrUnknownKeyID)
+
+		_, err = verifier.Verify(context.Background(), newToken)
+		assert.NoError(t, err)
+	})
+}
+
+func TestVerifier_Claims(t *testing.T) {
+	rotator := newRotator(t, 1)
+	signingKey := rotator.GetLatest()
+	verifier := jwt.NewVerifier(jwt.NewKeySet(rotator), jwt.WithLeeway(5*time.Second))
+	now := time.Now()
+
+	validClaims := func() jwtlib.MapClaims {
+		return jwtlib.MapClaims{
+			"sub": "user_1",
+			"iss": jwt.Issuer,
+			"aud": jwt.Audience,
+			"nbf": now.Unix(),
+			"exp": now.Add(time.Minute).Unix(),
+		}
+	}
+
+	tests := []struct {
+		name   string
+		modify func(claims jwtlib.MapClaims)
+		err    error
+	}{
+		{name: "valid", modify: func(jwtlib.MapClaims) {}},
+		{name: "wrong issuer", modify: func(c jwtlib.MapClaims) { c["iss"] = "someone-else" }, err: jwt.ErrInvalidIssuer},
+		{name: "wrong audience", modify: func(c jwtlib.MapClaims) { c["aud"] = "someone-else" }, err: jwt.ErrInvalidAudience},
+		{name: "missing expiry", modify: func(c jwtlib.MapClaims) { delete(c, "exp") }, err: jwt.ErrTokenExpired},
+		{name: "expired", modify: func(c jwtlib.MapClaims) { c["exp"] = now.Add(-time.Minute).Unix() }, err: jwt.ErrTokenExpired},
+		{name: "expired within leeway", modify: func(c jwtlib.MapClaims) { c["exp"] = now.Add(-2 * time.Second).Unix() }},
+		{name: "not yet valid", modify: func(c jwtlib.MapClaims) { c["nbf"] = now.Add(time.Minute).Unix() }, err: jwt.ErrTokenNotYetValid},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims := validClaims()
+			tt.modify(claims)
+
+			result, err := verifier.Verify(context.Background(), signClaims(t, signingKey, claims))
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "user_1", result.Subject)
+		})
+	}
+
+	t.Run("missing kid", func(t *testing.T) {
+		_, err := verifier.Verify(context.Background(), signClaims(t, keypair.SigningKey{Key: signingKey.Key}, validClaims()))
+		assert.ErrorIs(t, err, jwt.ErrMissingKeyID)
+	})
+	t.Run("tampered signature", func(t *testing.T) {
+		other := newRotator(t, 1).GetLatest()
+		other.ID = signingKey.ID
+		_, err := verifier.Verify(context.Background(), signClaims(t, other, validClaims()))
+		assert.Error(t, err)
+	})
+	t.Run("unexpected algorithm", func(t *testing.T) {
+		token := jwtlib.NewWithClaims(jwtlib.SigningMethodHS256, validClaims())
+		token.Header["kid"] = signingKey.ID
+		signed, err := token.SignedString([]byte("secret"))
+		require.NoError(t, err)
+
+		_, err = verifier.Verify(context.Background(), signed)
+		assert.Error(t, err)
+	})
+}
+
+func TestJWKSet(t *testing.T) {
+	for _, algorithm := range keypair.Algorithms {
+		t.Run(string(algorithm), func(t *testing.T) {
+			rotator := newRotator(t, 2, keypair.WithAlgorithm(algorithm))
+			rotator.Rotate()
+
+			set, err := jwt.NewJWKSet(rotator)
+			require.NoError(t, err)
+			require.Len(t, set.Keys, 2)
+			assert.Equal(t, "key_2", set.Keys[0].KeyID)
+			assert.Equal(t, "key_1", set.Keys[1].KeyID)
+
+			for _, jwk := range set.Keys {
+				assert.Equal(t, string(algorithm), jwk.Algorithm)
+
+				fromJWK, err := jwk.Key()
+				require.NoError(t, err)
+				fromPEM, err := jwt.NewKeySet(rotator).Key(context.Background(), jwk.KeyID)
+				require.NoError(t, err)
+				assert.True(t, fromJWK.(interface{ Equal(crypto.PublicKey) bool }).Equal(fromPEM))
+			}
+		})
+	}
+}
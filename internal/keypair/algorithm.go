@@ -0,0 +1,32 @@
+package keypair
+
+import "fmt"
+
+// Algorithm is the JWS algorithm a key signs with, as it appears in a token's alg header.
+type Algorithm string
+
+const (
+	AlgorithmRS256 Algorithm = "RS256"
+	AlgorithmES256 Algorithm = "ES256"
+	AlgorithmEdDSA Algorithm = "EdDSA"
+)
+
+// DefaultAlgorithm is used when no algorithm is configured.
+const DefaultAlgorithm = AlgorithmRS256
+
+// Algorithms lists every supported algorithm.
+var Algorithms = []Algorithm{AlgorithmRS256, AlgorithmES256, AlgorithmEdDSA}
+
+// ParseAlgorithm accepts the alg header names, plus Ed25519 as an alias for EdDSA.
+func ParseAlgorithm(value string) (Algorithm, error) {
+	switch value {
+	case "", string(AlgorithmRS256):
+		return AlgorithmRS256, nil
+	case string(AlgorithmES256):
+		return AlgorithmES256, nil
+	case string(AlgorithmEdDSA), "Ed25519":
+		return AlgorithmEdDSA, nil
+	}
+
+	return "", fmt.Errorf("unsupported signing algorithm %q", value)
+}
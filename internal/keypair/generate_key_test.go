@@ -1,6 +1,8 @@
 package keypair_test
 
 import (
+	"crypto/x509"
+	"encoding/pem"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -10,8 +12,46 @@ import (
 
 func TestGenerateKeyPair(t *testing.T) {
 	t.Run("can generate a valid key pair", func(t *testing.T) {
-		key, err := keypair.GenerateKeyPair()
+		key, err := keypair.GenerateKeyPair(keypair.AlgorithmRS256)
 		assert.NoError(t, err)
 		assert.NotNil(t, key)
 	})
+	for _, algorithm := range keypair.Algorithms {
+		t.Run(string(algorithm)+" keys are PKCS #8 and PKIX encoded", func(t *testing.T) {
+			key, err := keypair.GenerateKeyPair(algorithm)
+			assert.NoError(t, err)
+			assert.Equal(t, algorithm, key.Algorithm)
+
+			privateBlock, _ := pem.Decode([]byte(key.PrivateKey))
+			assert.Equal(t, "PRIVATE KEY", privateBlock.Type)
+			_, err = x509.ParsePKCS8PrivateKey(privateBlock.Bytes)
+			assert.NoError(t, err)
+
+			publicBlock, _ := pem.Decode([]byte(key.PublicKey))
+			assert.Equal(t, "PUBLIC KEY", publicBlock.Type)
+			_, err = x509.ParsePKIXPublicKey(publicBlock.Bytes)
+			assert.NoError(t, err)
+		})
+	}
+	t.Run("rejects unknown algorithms", func(t *testing.T) {
+		_, err := keypair.GenerateKeyPair("HS256")
+		assert.Error(t, err)
+	})
+}
+
+func TestParseAlgorithm(t *testing.T) {
+	for value, expected := range map[string]keypair.Algorithm{
+		"":        keypair.AlgorithmRS256,
+		"RS256":   keypair.AlgorithmRS256,
+		"ES256":   keypair.AlgorithmES256,
+		"EdDSA":   keypair.AlgorithmEdDSA,
+		"Ed25519": keypair.AlgorithmEdDSA,
+	} {
+		algorithm, err := keypair.ParseAlgorithm(value)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, algorithm)
+	}
+
+	_, err := keypair.ParseAlgorithm("none")
+	assert.Error(t, err)
 }
@@ -1,47 +1,68 @@
 package keypair
 
 import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
 	"crypto/rand"
 	"crypto/rsa"
 	"crypto/x509"
 	"encoding/pem"
+	"fmt"
 )
 
 const KeySize = 2048
 
-func GenerateKeyPair() (*key, error) { // nolint
-	// There's no need to handle the error since rsa.GenerateKey with the given parameter can never produce error.
-	keyPair, _ := rsa.GenerateKey(rand.Reader, KeySize)
+// GenerateKeyPair creates a key pair for algorithm. Both halves are PEM encoded, the private key
+// as PKCS #8 and the public key as PKIX, whatever the key type.
+func GenerateKeyPair(algorithm Algorithm) (*KeyPair, error) { // nolint
+	privateKey, err := generatePrivateKey(algorithm)
+	if err != nil {
+		return nil, err
+	}
 
-	privateKey, err := getEncodedPrivateKey(keyPair)
+	encodedPrivateKey, err := getEncodedPrivateKey(privateKey)
 	if err != nil {
 		return nil, err
 	}
 
-	publicKey, err := getEncodedPublicKey(keyPair)
+	encodedPublicKey, err := getEncodedPublicKey(privateKey.Public())
 	if err != nil {
 		return nil, err
 	}
 
-	return &key{PublicKey: publicKey, PrivateKey: privateKey}, nil
+	return &KeyPair{PublicKey: encodedPublicKey, PrivateKey: encodedPrivateKey, Algorithm: algorithm}, nil
+}
+
+func generatePrivateKey(algorithm Algorithm) (crypto.Signer, error) {
+	switch algorithm {
+	case AlgorithmRS256:
+		return rsa.GenerateKey(rand.Reader, KeySize)
+	case AlgorithmES256:
+		return ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	case AlgorithmEdDSA:
+		_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+		return privateKey, err
+	}
+
+	return nil, fmt.Errorf("unsupported signing algorithm %q", algorithm)
 }
 
-func getEncodedPrivateKey(keyPair *rsa.PrivateKey) (string, error) {
-	pkcs8Key, err := x509.MarshalPKCS8PrivateKey(keyPair)
+func getEncodedPrivateKey(privateKey crypto.Signer) (string, error) {
+	pkcs8Key, err := x509.MarshalPKCS8PrivateKey(privateKey)
 	if err != nil {
 		return "", err
 	}
 
-	return string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: pkcs8Key})), nil
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: pkcs8Key})), nil
 }
 
-func getEncodedPublicKey(keyPair *rsa.PrivateKey) (string, error) {
-	publicKey := keyPair.Public()
-
-	marshalledPublicKey, err := x509.MarshalPKIXPublicKey(publicKey.(*rsa.PublicKey))
+func getEncodedPublicKey(publicKey crypto.PublicKey) (string, error) {
+	marshalledPublicKey, err := x509.MarshalPKIXPublicKey(publicKey)
 	if err != nil {
 		return "", err
 	}
 
-	return string(pem.EncodeToMemory(&pem.Block{Type: "RSA PUBLIC KEY", Bytes: marshalledPublicKey})), nil
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: marshalledPublicKey})), nil
 }
@@ -0,0 +1,135 @@
+package keystore
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/weeb-vip/user-service/internal/db"
+	"github.com/weeb-vip/user-service/internal/keypair"
+	"github.com/weeb-vip/user-service/internal/ulid"
+	"github.com/weeb-vip/user-service/metrics"
+	"github.com/weeb-vip/user-service/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// SigningKeyLock is a row of signing_key_locks, a lease held by Holder until ExpiresAt.
+type SigningKeyLock struct {
+	Name      string    `gorm:"column:name;primaryKey"`
+	Holder    string    `gorm:"column:holder"`
+	ExpiresAt time.Time `gorm:"column:expires_at"`
+}
+
+type dbLock struct {
+	DBService db.DB
+	name      string
+	holder    string
+	ttl       time.Duration
+}
+
+// NewDBLock is a lease on a signing_key_locks row. It expires after ttl, so a leader that dies
+// mid-rotation only blocks the others until then. Unlike GET_LOCK it doesn't tie the lock to
+// one pooled connection.
+func NewDBLock(dbService db.DB, name string, ttl time.Duration) keypair.Lock {
+	return &dbLock{
+		DBService: dbService,
+		name:      name,
+		holder:    ulid.New("key_rotator"),
+		ttl:       ttl,
+	}
+}
+
+func (lock *dbLock) TryAcquire(ctx context.Context) error {
+	tracer := tracing.GetTracer(ctx)
+	ctx, span := tracer.Start(ctx, "repository.AcquireSigningKeyLock",
+		trace.WithAttributes(
+			attribute.String("lock.name", lock.name),
+			attribute.String("table", "signing_key_locks"),
+			attribute.String("operation", "upsert"),
+		),
+		tracing.GetEnvironmentAttribute(),
+	)
+	defer span.End()
+
+	start := time.Now()
+	database := lock.DBService.GetDB().WithContext(ctx)
+
+	now := time.Now()
+	acquired := false
+	err := database.Transaction(func(tx *gorm.DB) error {
+		created := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&SigningKeyLock{
+			Name:      lock.name,
+			Holder:    lock.holder,
+			ExpiresAt: now.Add(lock.ttl),
+		})
+		if created.Error != nil {
+			return created.Error
+		}
+		if created.RowsAffected == 1 {
+			acquired = true
+			return nil
+		}
+
+		taken := tx.Model(&SigningKeyLock{}).
+			Where("name = ? AND (expires_at < ? OR holder = ?)", lock.name, now, lock.holder).
+			Updates(map[string]interface{}{
+				"holder":     lock.holder,
+				"expires_at": now.Add(lock.ttl),
+			})
+		acquired = taken.RowsAffected == 1
+
+		return taken.Error
+	})
+
+	// Record database metrics
+	duration := float64(time.Since(start).Nanoseconds()) / float64(time.Millisecond)
+	appMetrics := metrics.GetAppMetrics()
+	result := metrics.Success
+	if err != nil {
+		result = metrics.Error
+	}
+	appMetrics.DatabaseMetric(duration, "signing_key_locks", "upsert", result)
+
+	if err != nil {
+		return err
+	}
+	if !acquired {
+		return keypair.ErrLockHeld
+	}
+
+	return nil
+}
+
+func (lock *dbLock) Release(ctx context.Context) error {
+	tracer := tracing.GetTracer(ctx)
+	ctx, span := tracer.Start(ctx, "repository.ReleaseSigningKeyLock",
+		trace.WithAttributes(
+			attribute.String("lock.name", lock.name),
+			attribute.String("table", "signing_key_locks"),
+			attribute.String("operation", "delete"),
+		),
+		tracing.GetEnvironmentAttribute(),
+	)
+	defer span.End()
+
+	start := time.Now()
+	database := lock.DBService.GetDB()
+
+	err := database.WithContext(ctx).
+		Where("name = ? AND holder = ?", lock.name, lock.holder).
+		Delete(&SigningKeyLock{}).Error
+
+	// Record database metrics
+	duration := float64(time.Since(start).Nanoseconds()) / float64(time.Millisecond)
+	appMetrics := metrics.GetAppMetrics()
+	result := metrics.Success
+	if err != nil {
+		result = metrics.Error
+	}
+	appMetrics.DatabaseMetric(duration, "signing_key_locks", "delete", result)
+
+	return err
+}
@@ -0,0 +1,155 @@
+package keystore
+
+import (
+	"context"
+	"encoding/base64"
+	"time"
+
+	"github.com/weeb-vip/user-service/internal/db"
+	"github.com/weeb-vip/user-service/internal/keypair"
+	"github.com/weeb-vip/user-service/metrics"
+	"github.com/weeb-vip/user-service/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// SigningKey is a row of signing_keys. PrivateKey is base64 AES-GCM ciphertext when the store
+// has a cipher, and the PEM itself otherwise.
+type SigningKey struct {
+	ID         string    `gorm:"column:id;primaryKey"`
+	Algorithm  string    `gorm:"column:algorithm"`
+	PrivateKey string    `gorm:"column:private_key"`
+	PublicKey  string    `gorm:"column:public_key"`
+	CreatedAt  time.Time `gorm:"column:created_at"`
+}
+
+type dbStore struct {
+	DBService db.DB
+	cipher    *Cipher
+}
+
+// NewDBStore shares keys between every replica using the same database. Private keys are
+// encrypted with cipher when it isn't nil.
+func NewDBStore(dbService db.DB, cipher *Cipher) keypair.KeyStore {
+	return &dbStore{DBService: dbService, cipher: cipher}
+}
+
+func (store *dbStore) Latest(ctx context.Context, limit int) ([]*keypair.KeyPair, error) {
+	tracer := tracing.GetTracer(ctx)
+	ctx, span := tracer.Start(ctx, "repository.LatestSigningKeys",
+		trace.WithAttributes(
+			attribute.Int("limit", limit),
+			attribute.String("table", "signing_keys"),
+			attribute.String("operation", "select"),
+		),
+		tracing.GetEnvironmentAttribute(),
+	)
+	defer span.End()
+
+	start := time.Now()
+	database := store.DBService.GetDB()
+
+	var rows []SigningKey
+	err := database.WithContext(ctx).Order("created_at DESC").Limit(limit).Find(&rows).Error
+
+	// Record database metrics
+	duration := float64(time.Since(start).Nanoseconds()) / float64(time.Millisecond)
+	appMetrics := metrics.GetAppMetrics()
+	result := metrics.Success
+	if err != nil {
+		result = metrics.Error
+	}
+	appMetrics.DatabaseMetric(duration, "signing_keys", "select", result)
+
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]*keypair.KeyPair, 0, len(rows))
+	for _, row := range rows {
+		privateKey, err := store.decrypt(row.PrivateKey)
+		if err != nil {
+			return nil, err
+		}
+
+		keys = append(keys, &keypair.KeyPair{
+			ID:         row.ID,
+			Algorithm:  keypair.Algorithm(row.Algorithm),
+			PrivateKey: privateKey,
+			PublicKey:  row.PublicKey,
+			CreatedAt:  row.CreatedAt,
+		})
+	}
+
+	return keys, nil
+}
+
+func (store *dbStore) Save(ctx context.Context, keyPair *keypair.KeyPair) error {
+	tracer := tracing.GetTracer(ctx)
+	ctx, span := tracer.Start(ctx, "repository.SaveSigningKey",
+		trace.WithAttributes(
+			attribute.String("signing_key.id", keyPair.ID),
+			attribute.String("table", "signing_keys"),
+			attribute.String("operation", "insert"),
+		),
+		tracing.GetEnvironmentAttribute(),
+	)
+	defer span.End()
+
+	privateKey, err := store.encrypt(keyPair.PrivateKey)
+	if err != nil {
+		return err
+	}
+
+	start := time.Now()
+	database := store.DBService.GetDB()
+
+	err = database.WithContext(ctx).Create(&SigningKey{
+		ID:         keyPair.ID,
+		Algorithm:  string(keyPair.Algorithm),
+		PrivateKey: privateKey,
+		PublicKey:  keyPair.PublicKey,
+		CreatedAt:  keyPair.CreatedAt,
+	}).Error
+
+	// Record database metrics
+	duration := float64(time.Since(start).Nanoseconds()) / float64(time.Millisecond)
+	appMetrics := metrics.GetAppMetrics()
+	result := metrics.Success
+	if err != nil {
+		result = metrics.Error
+	}
+	appMetrics.DatabaseMetric(duration, "signing_keys", "insert", result)
+
+	return err
+}
+
+func (store *dbStore) encrypt(privateKey string) (string, error) {
+	if store.cipher == nil {
+		return privateKey, nil
+	}
+
+	sealed, err := store.cipher.Seal([]byte(privateKey))
+	if err != nil {
+		return "", err
+	}
+
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+func (store *dbStore) decrypt(privateKey string) (string, error) {
+	if store.cipher == nil {
+		return privateKey, nil
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(privateKey)
+	if err != nil {
+		return "", err
+	}
+	plaintext, err := store.cipher.Open(sealed)
+	if err != nil {
+		return "", err
+	}
+
+	return string(plaintext), nil
+}
@@ -0,0 +1,113 @@
+package keystore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/weeb-vip/user-service/internal/keypair"
+)
+
+// maxFileKeys bounds the file, keys older than this many rotations are dropped on save.
+const maxFileKeys = 10
+
+type fileStore struct {
+	mu     sync.Mutex
+	path   string
+	cipher *Cipher
+}
+
+// NewFileStore keeps keys in a single file encrypted with cipher. It suits a single process; use
+// the db store to share keys between replicas.
+func NewFileStore(path string, cipher *Cipher) keypair.KeyStore {
+	return &fileStore{path: path, cipher: cipher}
+}
+
+func (f *fileStore) Latest(_ context.Context, limit int) ([]*keypair.KeyPair, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	keys, err := f.read()
+	if err != nil {
+		return nil, err
+	}
+	if len(keys) > limit {
+		keys = keys[:limit]
+	}
+
+	return keys, nil
+}
+
+func (f *fileStore) Save(_ context.Context, keyPair *keypair.KeyPair) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	keys, err := f.read()
+	if err != nil {
+		return err
+	}
+
+	keys = append([]*keypair.KeyPair{keyPair}, keys...)
+	sort.SliceStable(keys, func(i, j int) bool {
+		return keys[i].CreatedAt.After(keys[j].CreatedAt)
+	})
+	if len(keys) > maxFileKeys {
+		keys = keys[:maxFileKeys]
+	}
+
+	return f.write(keys)
+}
+
+func (f *fileStore) read() ([]*keypair.KeyPair, error) {
+	sealed, err := os.ReadFile(f.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := f.cipher.Open(sealed)
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []*keypair.KeyPair
+	if err := json.Unmarshal(data, &keys); err != nil {
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+// write replaces the file atomically so a crash mid-write never leaves it unreadable.
+func (f *fileStore) write(keys []*keypair.KeyPair) error {
+	data, err := json.Marshal(keys)
+	if err != nil {
+		return err
+	}
+	sealed, err := f.cipher.Seal(data)
+	if err != nil {
+		return err
+	}
+
+	temp, err := os.CreateTemp(filepath.Dir(f.path), filepath.Base(f.path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(temp.Name())
+
+	if _, err := temp.Write(sealed); err != nil {
+		temp.Close()
+		return err
+	}
+	if err := temp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(temp.Name(), f.path)
+}
@@ -0,0 +1,61 @@
+package keystore_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/weeb-vip/user-service/internal/keypair"
+	"github.com/weeb-vip/user-service/internal/keypair/keystore"
+)
+
+func TestFileStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys.enc")
+	cipher, err := keystore.NewCipher("test-secret")
+	require.NoError(t, err)
+	store := keystore.NewFileStore(path, cipher)
+
+	keys, err := store.Latest(context.Background(), 2)
+	require.NoError(t, err)
+	assert.Empty(t, keys)
+
+	now := time.Now()
+	for i, id := range []string{"key_1", "key_2", "key_3"} {
+		keyPair, err := keypair.GenerateKeyPair(keypair.AlgorithmEdDSA)
+		require.NoError(t, err)
+		keyPair.ID = id
+		keyPair.CreatedAt = now.Add(time.Duration(i) * time.Minute)
+		require.NoError(t, store.Save(context.Background(), keyPair))
+	}
+
+	t.Run("returns the newest keys first", func(t *testing.T) {
+		keys, err := keystore.NewFileStore(path, cipher).Latest(context.Background(), 2)
+		require.NoError(t, err)
+		require.Len(t, keys, 2)
+		assert.Equal(t, "key_3", keys[0].ID)
+		assert.Equal(t, "key_2", keys[1].ID)
+		assert.Equal(t, keypair.AlgorithmEdDSA, keys[0].Algorithm)
+		assert.Contains(t, keys[0].PrivateKey, "PRIVATE KEY")
+	})
+	t.Run("private keys are encrypted at rest", func(t *testing.T) {
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.False(t, strings.Contains(string(data), "PRIVATE KEY"))
+	})
+	t.Run("the wrong secret can't read the file", func(t *testing.T) {
+		wrong, err := keystore.NewCipher("other-secret")
+		require.NoError(t, err)
+		_, err = keystore.NewFileStore(path, wrong).Latest(context.Background(), 2)
+		assert.Error(t, err)
+	})
+}
+
+func TestNewCipher(t *testing.T) {
+	_, err := keystore.NewCipher("")
+	assert.Error(t, err)
+}
@@ -0,0 +1,61 @@
+// Package keystore persists signing keys for keypair.WithKeyStore.
+package keystore
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/weeb-vip/user-service/config"
+	"github.com/weeb-vip/user-service/internal/db"
+	"github.com/weeb-vip/user-service/internal/keypair"
+	"github.com/weeb-vip/user-service/internal/logger"
+)
+
+const (
+	TypeMemory = "memory"
+	TypeDB     = "db"
+	TypeFile   = "file"
+)
+
+// rotationLockName is the signing_key_locks row every replica competes for.
+const rotationLockName = "signing-key-rotation"
+
+// New returns the store and rotation lock configured by cfg. Both are nil for the memory type,
+// where every process keeps its own keys as before. The db type refuses to hold private keys
+// unencrypted unless cfg.AllowPlaintext is set.
+func New(cfg config.KeyStoreConfig) (keypair.KeyStore, keypair.Lock, error) {
+	switch cfg.Type {
+	case "", TypeMemory:
+		return nil, nil, nil
+	case TypeDB:
+		var cipher *Cipher
+		switch {
+		case cfg.EncryptionKey != "":
+			var err error
+			if cipher, err = NewCipher(cfg.EncryptionKey); err != nil {
+				return nil, nil, err
+			}
+		case cfg.AllowPlaintext:
+			log := logger.Get()
+			log.Warn().Msg("storing signing keys in the database without encryption")
+		default:
+			return nil, nil, errors.New(
+				"the db key store needs KEY_STORE_ENCRYPTION_KEY, or KEY_STORE_ALLOW_PLAINTEXT to store keys unencrypted",
+			)
+		}
+		dbService := db.GetDBService()
+		lockTTL := time.Duration(cfg.LockTTLSeconds) * time.Second
+
+		return NewDBStore(dbService, cipher), NewDBLock(dbService, rotationLockName, lockTTL), nil
+	case TypeFile:
+		cipher, err := NewCipher(cfg.EncryptionKey)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return NewFileStore(cfg.FilePath, cipher), nil, nil
+	}
+
+	return nil, nil, fmt.Errorf("unknown key store type %q", cfg.Type)
+}
@@ -0,0 +1,20 @@
+package keystore_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/weeb-vip/user-service/config"
+	"github.com/weeb-vip/user-service/internal/keypair/keystore"
+)
+
+func TestNew(t *testing.T) {
+	t.Run("db store needs an encryption key", func(t *testing.T) {
+		_, _, err := keystore.New(config.KeyStoreConfig{Type: keystore.TypeDB})
+		assert.ErrorContains(t, err, "KEY_STORE_ENCRYPTION_KEY")
+	})
+	t.Run("unknown type", func(t *testing.T) {
+		_, _, err := keystore.New(config.KeyStoreConfig{Type: "vault"})
+		assert.Error(t, err)
+	})
+}
@@ -1,79 +1,295 @@
 package keypair
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"time"
 
 	"github.com/weeb-vip/user-service/internal/container"
+	"github.com/weeb-vip/user-service/internal/logger"
+	"github.com/weeb-vip/user-service/metrics"
 )
 
-type PublicKeyIDGenerator func(publicKey string) (string, error)
+// PublicKeyIDGenerator registers a new public key, typically with the key-management service, and
+// returns the ID tokens signed by it will carry in their kid header.
+type PublicKeyIDGenerator func(ctx context.Context, publicKey string, algorithm Algorithm) (string, error)
+
+// DefaultPublicKeyWindow is how many keys, the current one included, stay published for
+// verification after rotation.
+const DefaultPublicKeyWindow = 2
+
+// Waiting for the rotating process to store the first key when a shared store is empty.
+const (
+	storeWaitAttempts = 20
+	storeWaitInterval = 500 * time.Millisecond
+)
 
 type keyRotator struct {
-	keyContainer   container.Container[*key]
+	// keyContainer holds the current key first, followed by the keys it replaced.
+	keyContainer   container.Container[[]*KeyPair]
 	keyIDGenerator PublicKeyIDGenerator
+	window         int
+	algorithm      Algorithm
+	store          *storeConfig
+}
+
+type RotatorOption func(*keyRotator)
+
+// WithAlgorithm sets the algorithm of every key the rotator generates. It defaults to RS256.
+func WithAlgorithm(algorithm Algorithm) RotatorOption {
+	return func(k *keyRotator) {
+		k.algorithm = algorithm
+	}
+}
+
+// WithPublicKeyWindow keeps size keys, the current one included, available from PublicKeys so
+// tokens signed before a rotation still verify. It should cover the longest token lifetime.
+func WithPublicKeyWindow(size int) RotatorOption {
+	return func(k *keyRotator) {
+		if size > 0 {
+			k.window = size
+		}
+	}
 }
 
 type RotatingSigningKey interface {
 	Rotate()
-	RotateInBackground(every time.Duration)
+	// RotateInBackground rotates the key every interval until ctx is done.
+	RotateInBackground(ctx context.Context, every time.Duration)
 	GetLatest() SigningKey
+	// PublicKeys returns the current public key followed by the ones it replaced, newest first.
+	PublicKeys() []PublicKey
 }
 
-func (k keyRotator) RotateInBackground(every time.Duration) {
+func (k keyRotator) RotateInBackground(ctx context.Context, every time.Duration) {
 	go func() {
+		ticker := time.NewTicker(every)
+		defer ticker.Stop()
+
 		for {
-			time.Sleep(every)
-			k.Rotate()
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				k.rotate(ctx)
+			}
 		}
 	}()
 }
 
 func (k keyRotator) Rotate() {
-	newKeyPair, err := generateNewKeyPairWithID(k.keyIDGenerator)
+	k.rotate(context.Background())
+}
+
+func (k keyRotator) rotate(ctx context.Context) {
+	defer func() {
+		metrics.GetAppMetrics().KeyAgeMetric(time.Since(k.GetLatest().CreatedAt))
+	}()
+
+	if k.store != nil {
+		if err := k.syncWithStore(ctx); err != nil {
+			log := logger.FromCtx(ctx)
+			log.Error().Err(err).Msg("failed to sync signing keys with key store")
+		}
+		return
+	}
+
+	newKeyPair, err := generateNewKeyPairWithID(ctx, k.algorithm, k.keyIDGenerator)
+	recordRotation(err)
 	if err != nil {
-		// Because it's okay to not rotate key for few times.
+		// It's okay to miss a few rotations, the previous key keeps signing and the freshness
+		// check reports it once it gets too old.
+		log := logger.FromCtx(ctx)
+		log.Error().Err(err).Str("key.id", k.GetLatest().ID).Msg("failed to rotate signing key")
 		return
 	}
 
-	k.keyContainer.ReplaceWith(newKeyPair)
+	keys := append([]*KeyPair{newKeyPair}, k.keyContainer.GetLatest()...)
+	if len(keys) > k.window {
+		keys = keys[:k.window]
+	}
+
+	k.keyContainer.ReplaceWith(keys)
+}
+
+// syncWithStore rotates the stored key if it is due and this process wins the lock, then loads
+// the current window of keys from the store.
+func (k keyRotator) syncWithStore(ctx context.Context) error {
+	keys, err := k.store.store.Latest(ctx, k.window)
+	if err != nil {
+		return err
+	}
+
+	if len(keys) == 0 || time.Since(keys[0].CreatedAt) >= k.store.rotateAfter {
+		rotated, err := k.rotateAsLeader(ctx)
+		if err != nil {
+			return err
+		}
+		if rotated != nil {
+			keys = rotated
+		}
+	}
+
+	if len(keys) > 0 {
+		k.keyContainer.ReplaceWith(keys)
+	}
+
+	return nil
+}
+
+// rotateAsLeader returns nil keys when another process holds the lock.
+func (k keyRotator) rotateAsLeader(ctx context.Context) ([]*KeyPair, error) {
+	if k.store.lock != nil {
+		err := k.store.lock.TryAcquire(ctx)
+		if errors.Is(err, ErrLockHeld) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		defer func() {
+			if err := k.store.lock.Release(ctx); err != nil {
+				log := logger.FromCtx(ctx)
+				log.Warn().Err(err).Msg("failed to release key rotation lock")
+			}
+		}()
+	}
+
+	// The previous leader may have rotated between our read and taking the lock
+	keys, err := k.store.store.Latest(ctx, k.window)
+	if err != nil {
+		return nil, err
+	}
+	if len(keys) > 0 && time.Since(keys[0].CreatedAt) < k.store.rotateAfter {
+		return keys, nil
+	}
+
+	newKeyPair, err := generateNewKeyPairWithID(ctx, k.algorithm, k.keyIDGenerator)
+	if err == nil {
+		err = k.store.store.Save(ctx, newKeyPair)
+	}
+	recordRotation(err)
+	if err != nil {
+		return nil, err
+	}
+
+	return k.store.store.Latest(ctx, k.window)
 }
 
 func (k keyRotator) GetLatest() SigningKey {
-	currentKey := k.keyContainer.GetLatest()
+	currentKey := k.keyContainer.GetLatest()[0]
 
 	return SigningKey{
-		Key: currentKey.PrivateKey,
-		ID:  currentKey.ID,
+		Key:       currentKey.PrivateKey,
+		ID:        currentKey.ID,
+		Algorithm: currentKey.Algorithm,
+		CreatedAt: currentKey.CreatedAt,
 	}
 }
 
-func NewSigningKeyRotator(idGenerator PublicKeyIDGenerator) (RotatingSigningKey, error) {
+func (k keyRotator) PublicKeys() []PublicKey {
+	keys := k.keyContainer.GetLatest()
+	publicKeys := make([]PublicKey, 0, len(keys))
+	for _, key := range keys {
+		publicKeys = append(publicKeys, PublicKey{
+			Key:       key.PublicKey,
+			ID:        key.ID,
+			Algorithm: key.Algorithm,
+			CreatedAt: key.CreatedAt,
+		})
+	}
+
+	return publicKeys
+}
+
+// NewFreshnessCheck returns a health check that fails once the current key is older than maxAge,
+// which means rotation has stopped or keeps failing.
+func NewFreshnessCheck(rotatingKey RotatingSigningKey, maxAge time.Duration) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		age := time.Since(rotatingKey.GetLatest().CreatedAt)
+		metrics.GetAppMetrics().KeyAgeMetric(age)
+		if age > maxAge {
+			return fmt.Errorf("signing key is %s old, rotation should keep it under %s", age.Round(time.Second), maxAge)
+		}
+
+		return nil
+	}
+}
+
+func NewSigningKeyRotator(ctx context.Context, idGenerator PublicKeyIDGenerator, opts ...RotatorOption) (RotatingSigningKey, error) {
+	rotator := keyRotator{
+		keyContainer:   container.New[[]*KeyPair](nil),
+		keyIDGenerator: idGenerator,
+		window:         DefaultPublicKeyWindow,
+		algorithm:      DefaultAlgorithm,
+	}
+	for _, opt := range opts {
+		opt(&rotator)
+	}
+
+	if rotator.store != nil {
+		if err := rotator.loadFromStore(ctx); err != nil {
+			return nil, err
+		}
+
+		return rotator, nil
+	}
+
 	// We start with generating a key and keeping it in container[key].
-	keyPair, err := generateNewKeyPairWithID(idGenerator)
+	keyPair, err := generateNewKeyPairWithID(ctx, rotator.algorithm, idGenerator)
+	recordRotation(err)
 	if err != nil {
 		return nil, err
 	}
+	rotator.keyContainer.ReplaceWith([]*KeyPair{keyPair})
 
-	keyContainer := container.New[*key](keyPair)
+	return rotator, nil
+}
 
-	return keyRotator{
-		keyContainer:   keyContainer,
-		keyIDGenerator: idGenerator,
-	}, nil
+// loadFromStore waits for a key when the store is empty and another process holds the lock,
+// since that process is about to store the first one.
+func (k keyRotator) loadFromStore(ctx context.Context) error {
+	for attempt := 0; ; attempt++ {
+		if err := k.syncWithStore(ctx); err != nil {
+			return err
+		}
+		if len(k.keyContainer.GetLatest()) > 0 {
+			return nil
+		}
+		if attempt == storeWaitAttempts {
+			return errors.New("no signing key available in key store")
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(storeWaitInterval):
+		}
+	}
 }
 
-func generateNewKeyPairWithID(idGenerator PublicKeyIDGenerator) (*key, error) {
-	keyPair, err := GenerateKeyPair()
+func generateNewKeyPairWithID(ctx context.Context, algorithm Algorithm, idGenerator PublicKeyIDGenerator) (*KeyPair, error) {
+	keyPair, err := GenerateKeyPair(algorithm)
 	if err != nil {
 		return nil, err
 	}
 
-	keyID, err := idGenerator(keyPair.PublicKey)
+	keyID, err := idGenerator(ctx, keyPair.PublicKey, keyPair.Algorithm)
 	if err != nil {
 		return nil, err
 	}
 
 	keyPair.ID = keyID
+	keyPair.CreatedAt = time.Now()
 
 	return keyPair, nil
 }
+
+func recordRotation(err error) {
+	result := metrics.Success
+	if err != nil {
+		result = metrics.Error
+	}
+	metrics.GetAppMetrics().KeyRotationMetric(result)
+}
@@ -1,9 +1,12 @@
 package keypair_test
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
@@ -12,7 +15,7 @@ import (
 
 func getIDGenerator(id string, err error) keypair.PublicKeyIDGenerator {
 	count := 0
-	return func(publicKey string) (string, error) {
+	return func(_ context.Context, publicKey string, algorithm keypair.Algorithm) (string, error) {
 		count = count + 1
 		return fmt.Sprintf(id, count), err
 	}
@@ -20,17 +23,17 @@ func getIDGenerator(id string, err error) keypair.PublicKeyIDGenerator {
 
 func TestNewSigningKeyRotator(t *testing.T) {
 	t.Run("if getting ID during startup fails, it returns error", func(t *testing.T) {
-		rotatingKeyPair, err := keypair.NewSigningKeyRotator(getIDGenerator("", errors.New("some error")))
+		rotatingKeyPair, err := keypair.NewSigningKeyRotator(context.Background(), getIDGenerator("", errors.New("some error")))
 		assert.Nil(t, rotatingKeyPair)
 		assert.Error(t, err)
 	})
 	t.Run("starts by creating a new keypair and saving", func(t *testing.T) {
-		rotatingKeyPair, err := keypair.NewSigningKeyRotator(getIDGenerator("key_%d", nil))
+		rotatingKeyPair, err := keypair.NewSigningKeyRotator(context.Background(), getIDGenerator("key_%d", nil))
 		assert.NoError(t, err)
 		assert.Equal(t, "key_1", rotatingKeyPair.GetLatest().ID)
 	})
 	t.Run("when called rotate, it rolls the key and we get a new ID", func(t *testing.T) {
-		rotatingKeyPair, err := keypair.NewSigningKeyRotator(getIDGenerator("key_%d", nil))
+		rotatingKeyPair, err := keypair.NewSigningKeyRotator(context.Background(), getIDGenerator("key_%d", nil))
 		assert.NoError(t, err)
 		assert.Equal(t, "key_1", rotatingKeyPair.GetLatest().ID)
 		publicKey := rotatingKeyPair.GetLatest().Key
@@ -41,14 +44,14 @@ func TestNewSigningKeyRotator(t *testing.T) {
 
 	t.Run("if it fails to generate key, it keeps the old keypair", func(t *testing.T) {
 		count := 0
-		customFailingIDGenerator := keypair.PublicKeyIDGenerator(func(publicKey string) (string, error) {
+		customFailingIDGenerator := keypair.PublicKeyIDGenerator(func(_ context.Context, publicKey string, algorithm keypair.Algorithm) (string, error) {
 			count = count + 1
 			if count%3 == 0 {
 				return "", errors.New("random error")
 			}
 			return fmt.Sprintf("key_%d", count), nil
 		})
-		rotatingKeyPair, err := keypair.NewSigningKeyRotator(customFailingIDGenerator)
+		rotatingKeyPair, err := keypair.NewSigningKeyRotator(context.Background(), customFailingIDGenerator)
 		assert.NoError(t, err)
 		assert.Equal(t, "key_1", rotatingKeyPair.GetLatest().ID)
 		rotatingKeyPair.Rotate()
@@ -65,3 +68,118 @@ func TestNewSigningKeyRotator(t *testing.T) {
 		assert.Equal(t, "key_7", rotatingKeyPair.GetLatest().ID)
 	})
 }
+
+func TestNewFreshnessCheck(t *testing.T) {
+	rotatingKeyPair, err := keypair.NewSigningKeyRotator(context.Background(), getIDGenerator("key_%d", nil))
+	assert.NoError(t, err)
+
+	assert.NoError(t, keypair.NewFreshnessCheck(rotatingKeyPair, time.Minute)(context.Background()))
+	assert.Error(t, keypair.NewFreshnessCheck(rotatingKeyPair, 0)(context.Background()))
+}
+
+func TestSigningKeyRotator_PublicKeys(t *testing.T) {
+	rotatingKeyPair, err := keypair.NewSigningKeyRotator(context.Background(), getIDGenerator("key_%d", nil), keypair.WithPublicKeyWindow(2))
+	assert.NoError(t, err)
+
+	ids := func() []string {
+		var ids []string
+		for _, publicKey := range rotatingKeyPair.PublicKeys() {
+			ids = append(ids, publicKey.ID)
+		}
+		return ids
+	}
+
+	assert.Equal(t, []string{"key_1"}, ids())
+	rotatingKeyPair.Rotate()
+	assert.Equal(t, []string{"key_2", "key_1"}, ids())
+	rotatingKeyPair.Rotate()
+	assert.Equal(t, []string{"key_3", "key_2"}, ids())
+}
+
+type memoryStore struct {
+	mu   sync.Mutex
+	keys []*keypair.KeyPair
+}
+
+func (m *memoryStore) Latest(_ context.Context, limit int) ([]*keypair.KeyPair, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if len(m.keys) > limit {
+		return append([]*keypair.KeyPair{}, m.keys[:limit]...), nil
+	}
+	return append([]*keypair.KeyPair{}, m.keys...), nil
+}
+
+func (m *memoryStore) Save(_ context.Context, keyPair *keypair.KeyPair) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.keys = append([]*keypair.KeyPair{keyPair}, m.keys...)
+	return nil
+}
+
+type heldLock struct {
+	held bool
+}
+
+func (l *heldLock) TryAcquire(context.Context) error {
+	if l.held {
+		return keypair.ErrLockHeld
+	}
+	return nil
+}
+
+func (l *heldLock) Release(context.Context) error {
+	return nil
+}
+
+func TestSigningKeyRotator_KeyStore(t *testing.T) {
+	t.Run("replicas and restarts share the stored key", func(t *testing.T) {
+		store := &memoryStore{}
+		generator := getIDGenerator("key_%d", nil)
+
+		first, err := keypair.NewSigningKeyRotator(context.Background(), generator, keypair.WithKeyStore(store, &heldLock{}, time.Hour))
+		assert.NoError(t, err)
+		second, err := keypair.NewSigningKeyRotator(context.Background(), generator, keypair.WithKeyStore(store, &heldLock{}, time.Hour))
+		assert.NoError(t, err)
+
+		assert.Equal(t, "key_1", first.GetLatest().ID)
+		assert.Equal(t, "key_1", second.GetLatest().ID)
+		assert.Len(t, store.keys, 1, "the key is only generated and published once")
+
+		first.Rotate()
+		assert.Equal(t, "key_1", first.GetLatest().ID, "keys are not rotated before they are due")
+	})
+	t.Run("only the lock holder rotates, the others pick the new key up", func(t *testing.T) {
+		store := &memoryStore{}
+		generator := getIDGenerator("key_%d", nil)
+		followerLock := &heldLock{}
+
+		leader, err := keypair.NewSigningKeyRotator(context.Background(), generator, keypair.WithKeyStore(store, &heldLock{}, 0))
+		assert.NoError(t, err)
+		followerLock.held = true
+		follower, err := keypair.NewSigningKeyRotator(context.Background(), generator, keypair.WithKeyStore(store, followerLock, 0))
+		assert.NoError(t, err)
+		assert.Equal(t, "key_1", follower.GetLatest().ID)
+
+		follower.Rotate()
+		assert.Len(t, store.keys, 1)
+
+		leader.Rotate()
+		assert.Equal(t, "key_2", leader.GetLatest().ID)
+		follower.Rotate()
+		assert.Equal(t, "key_2", follower.GetLatest().ID)
+
+		var ids []string
+		for _, publicKey := range follower.PublicKeys() {
+			ids = append(ids, publicKey.ID)
+		}
+		assert.Equal(t, []string{"key_2", "key_1"}, ids, "the previous key stays available for overlap")
+	})
+	t.Run("store errors fail startup", func(t *testing.T) {
+		rotatingKeyPair, err := keypair.NewSigningKeyRotator(context.Background(), getIDGenerator("", errors.New("unavailable")), keypair.WithKeyStore(&memoryStore{}, nil, time.Hour))
+		assert.Nil(t, rotatingKeyPair)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,40 @@
+package keypair
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrLockHeld is returned by Lock.TryAcquire when another process holds the lock.
+var ErrLockHeld = errors.New("lock is held by another process")
+
+// KeyStore persists signing keys so every replica signs with the same key and restarts reuse
+// it instead of generating and publishing a new one.
+type KeyStore interface {
+	// Latest returns up to limit keys, newest first.
+	Latest(ctx context.Context, limit int) ([]*KeyPair, error)
+	Save(ctx context.Context, keyPair *KeyPair) error
+}
+
+// Lock elects the single process allowed to rotate the key shared through a KeyStore.
+type Lock interface {
+	// TryAcquire returns ErrLockHeld rather than waiting when another process holds the lock.
+	TryAcquire(ctx context.Context) error
+	Release(ctx context.Context) error
+}
+
+type storeConfig struct {
+	store       KeyStore
+	lock        Lock
+	rotateAfter time.Duration
+}
+
+// WithKeyStore loads keys from store instead of generating one per process. Rotate then only
+// replaces the key once it is rotateAfter old, and only in the process holding lock; every other
+// process picks the new key up from the store. A nil lock suits stores only one process uses.
+func WithKeyStore(store KeyStore, lock Lock, rotateAfter time.Duration) RotatorOption {
+	return func(k *keyRotator) {
+		k.store = &storeConfig{store: store, lock: lock, rotateAfter: rotateAfter}
+	}
+}
@@ -1,12 +1,28 @@
 package keypair
 
-type key struct {
+import "time"
+
+// KeyPair is a PEM encoded signing key, private half included, as generated and persisted.
+type KeyPair struct {
 	PrivateKey string
 	PublicKey  string
 	ID         string
+	Algorithm  Algorithm
+	CreatedAt  time.Time
 }
 
 type SigningKey struct {
-	Key string
-	ID  string
+	Key       string
+	ID        string
+	Algorithm Algorithm
+	// CreatedAt is when the key was generated.
+	CreatedAt time.Time
+}
+
+// PublicKey is the PEM encoded public half of a signing key, published so tokens can be verified.
+type PublicKey struct {
+	Key       string
+	ID        string
+	Algorithm Algorithm
+	CreatedAt time.Time
 }
@@ -0,0 +1,82 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/weeb-vip/user-service/config"
+	"github.com/weeb-vip/user-service/internal/logger"
+)
+
+// Lifecycle runs shutdown hooks in the reverse order they were registered, so whatever started
+// last stops first, all within one deadline.
+type Lifecycle struct {
+	timeout  time.Duration
+	draining atomic.Bool
+
+	mu    sync.Mutex
+	hooks []hook
+}
+
+type hook struct {
+	name string
+	stop func(ctx context.Context) error
+}
+
+func New(cfg config.ShutdownConfig) *Lifecycle {
+	return &Lifecycle{timeout: time.Duration(cfg.TimeoutSeconds) * time.Second}
+}
+
+// SignalContext returns a context cancelled on SIGINT or SIGTERM.
+func SignalContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	return signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+}
+
+// OnShutdown registers stop to run during Shutdown. stop should return once ctx is done.
+func (l *Lifecycle) OnShutdown(name string, stop func(ctx context.Context) error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.hooks = append(l.hooks, hook{name: name, stop: stop})
+}
+
+// Draining reports whether Shutdown has started.
+func (l *Lifecycle) Draining() bool {
+	return l.draining.Load()
+}
+
+// Shutdown runs every hook once, even when earlier ones fail or the deadline passes, and returns
+// their errors joined.
+func (l *Lifecycle) Shutdown(ctx context.Context) error {
+	if l.draining.Swap(true) {
+		return nil
+	}
+
+	log := logger.FromCtx(ctx)
+	ctx, cancel := context.WithTimeout(ctx, l.timeout)
+	defer cancel()
+
+	l.mu.Lock()
+	hooks := l.hooks
+	l.mu.Unlock()
+
+	var errs []error
+	for i := len(hooks) - 1; i >= 0; i-- {
+		start := time.Now()
+		if err := hooks[i].stop(ctx); err != nil {
+			log.Error().Err(err).Str("hook", hooks[i].name).Msg("Shutdown step failed")
+			errs = append(errs, fmt.Errorf("%s: %w", hooks[i].name, err))
+			continue
+		}
+		log.Info().Str("hook", hooks[i].name).Dur("duration", time.Since(start)).Msg("Shutdown step finished")
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,63 @@
+package lifecycle_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/weeb-vip/user-service/config"
+	"github.com/weeb-vip/user-service/internal/lifecycle"
+)
+
+func TestLifecycle_Shutdown(t *testing.T) {
+	shutdown := lifecycle.New(config.ShutdownConfig{TimeoutSeconds: 1})
+
+	var order []string
+	record := func(name string, err error) func(context.Context) error {
+		return func(context.Context) error {
+			order = append(order, name)
+			return err
+		}
+	}
+	shutdown.OnShutdown("tracing", record("tracing", nil))
+	shutdown.OnShutdown("database", record("database", errors.New("already closed")))
+	shutdown.OnShutdown("http server", func(ctx context.Context) error {
+		assert.True(t, shutdown.Draining(), "should be draining while hooks run")
+		return record("http server", nil)(ctx)
+	})
+
+	assert.False(t, shutdown.Draining())
+	err := shutdown.Shutdown(context.Background())
+
+	assert.Equal(t, []string{"http server", "database", "tracing"}, order)
+	assert.ErrorContains(t, err, "database: already closed")
+
+	// A second shutdown is a no-op
+	assert.NoError(t, shutdown.Shutdown(context.Background()))
+	assert.Len(t, order, 3)
+}
+
+func TestLifecycle_ShutdownDeadline(t *testing.T) {
+	shutdown := lifecycle.New(config.ShutdownConfig{TimeoutSeconds: 0})
+
+	ran := false
+	shutdown.OnShutdown("flush", func(context.Context) error {
+		ran = true
+		return nil
+	})
+	shutdown.OnShutdown("stuck", func(ctx context.Context) error {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Second):
+			return nil
+		}
+	})
+
+	err := shutdown.Shutdown(context.Background())
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.True(t, ran, "later steps still run after the deadline")
+}
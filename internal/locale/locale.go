@@ -0,0 +1,109 @@
+package locale
+
+import (
+	"errors"
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+// Default is used when nothing better matches what a user asked for.
+const Default = "en"
+
+// Supported lists the BCP-47 locales users can pick, in their canonical form.
+var Supported = []string{ // nolint
+	"en",
+	"th",
+	"ja",
+	"ko",
+	"zh-Hans",
+	"zh-Hant",
+	"pt-BR",
+	"es",
+	"fr",
+	"de",
+	"id",
+	"vi",
+}
+
+var ErrUnsupported = errors.New("unsupported locale")
+
+var (
+	supportedTags = mustParseAll(Supported) // nolint
+	matcher       = language.NewMatcher(supportedTags)
+)
+
+// Normalize returns the canonical form of value if it names a supported locale exactly.
+// Matching is case-insensitive and accepts underscores, so "EN", "pt_br" and "zh-hant" are valid.
+func Normalize(value string) (string, error) {
+	tag, err := parse(value)
+	if err != nil {
+		return "", ErrUnsupported
+	}
+
+	for i, supported := range supportedTags {
+		if tag == supported {
+			return Supported[i], nil
+		}
+	}
+
+	return "", ErrUnsupported
+}
+
+// Resolve returns the supported locale closest to value, or Default when nothing is close.
+// It never fails, so it suits untrusted input such as event payloads.
+func Resolve(value string) string {
+	return Fallbacks(value)[0]
+}
+
+// Fallbacks returns the supported locales to try for value, most specific first and always
+// ending with Default. For "zh-Hant-TW" that is [zh-Hant en].
+func Fallbacks(value string) []string {
+	var chain []string
+	add := func(locale string) {
+		for _, existing := range chain {
+			if existing == locale {
+				return
+			}
+		}
+		chain = append(chain, locale)
+	}
+
+	tag, err := parse(value)
+	if err == nil {
+		for parent := tag; !parent.IsRoot(); parent = parent.Parent() {
+			if normalized, err := Normalize(parent.String()); err == nil {
+				add(normalized)
+			}
+		}
+
+		// Let the matcher handle variants with no supported parent, such as zh-CN -> zh-Hans or pt-PT -> pt-BR
+		if len(chain) == 0 {
+			_, index, confidence := matcher.Match(tag)
+			if confidence >= language.High {
+				add(Supported[index])
+			}
+		}
+	}
+	add(Default)
+
+	return chain
+}
+
+func parse(value string) (language.Tag, error) {
+	value = strings.ReplaceAll(strings.TrimSpace(value), "_", "-")
+	if value == "" {
+		return language.Und, ErrUnsupported
+	}
+
+	return language.Parse(value)
+}
+
+func mustParseAll(values []string) []language.Tag {
+	tags := make([]language.Tag, 0, len(values))
+	for _, value := range values {
+		tags = append(tags, language.MustParse(value))
+	}
+
+	return tags
+}
@@ -0,0 +1,80 @@
+package locale_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/weeb-vip/user-service/internal/locale"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected string
+		wantErr  bool
+	}{
+		{value: "en", expected: "en"},
+		{value: "EN", expected: "en"},
+		{value: "TH", expected: "th"},
+		{value: "pt-BR", expected: "pt-BR"},
+		{value: "pt_br", expected: "pt-BR"},
+		{value: "PT_BR", expected: "pt-BR"},
+		{value: "zh-hant", expected: "zh-Hant"},
+		{value: "ZH_HANS", expected: "zh-Hans"},
+		{value: "en-US", wantErr: true},
+		{value: "pt", wantErr: true},
+		{value: "klingon", wantErr: true},
+		{value: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			normalized, err := locale.Normalize(tt.value)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, locale.ErrUnsupported)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, normalized)
+		})
+	}
+}
+
+func TestSupportedLocalesAreCanonical(t *testing.T) {
+	for _, supported := range locale.Supported {
+		normalized, err := locale.Normalize(supported)
+		assert.NoError(t, err)
+		assert.Equal(t, supported, normalized)
+	}
+}
+
+func TestResolve(t *testing.T) {
+	tests := map[string]string{
+		"ja":         "ja",
+		"ja-JP":      "ja",
+		"en-GB":      "en",
+		"pt":         "pt-BR",
+		"pt-PT":      "pt-BR",
+		"zh":         "zh-Hans",
+		"zh-CN":      "zh-Hans",
+		"zh-TW":      "zh-Hant",
+		"zh-Hant-HK": "zh-Hant",
+		"sr":         locale.Default,
+		"not a tag":  locale.Default,
+		"":           locale.Default,
+	}
+
+	for value, expected := range tests {
+		t.Run(value, func(t *testing.T) {
+			assert.Equal(t, expected, locale.Resolve(value))
+		})
+	}
+}
+
+func TestFallbacks(t *testing.T) {
+	assert.Equal(t, []string{"pt-BR", "en"}, locale.Fallbacks("pt-BR"))
+	assert.Equal(t, []string{"zh-Hant", "en"}, locale.Fallbacks("zh-Hant-TW"))
+	assert.Equal(t, []string{"en"}, locale.Fallbacks("en-US"))
+	assert.Equal(t, []string{"en"}, locale.Fallbacks("xx"))
+}
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS images
+(
+    hash       CHAR(64)     PRIMARY KEY,
+    path       VARCHAR(500) NOT NULL,
+    size       BIGINT       NOT NULL,
+    ref_count  INT          NOT NULL DEFAULT 0,
+    created_at timestamp    NOT NULL,
+    updated_at timestamp    NOT NULL
+);
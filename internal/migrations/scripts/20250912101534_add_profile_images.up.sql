@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS profile_images
+(
+    id          VARCHAR(100) PRIMARY KEY,
+    user_id     VARCHAR(100) NOT NULL,
+    path        VARCHAR(500) NOT NULL,
+    status      VARCHAR(20)  NOT NULL,
+    reason      VARCHAR(500) NULL,
+    reviewed_by VARCHAR(100) NULL,
+    reviewed_at timestamp    NULL,
+    created_at  timestamp    NOT NULL,
+    updated_at  timestamp    NOT NULL,
+    INDEX idx_profile_images_status_created_at (status, created_at),
+    INDEX idx_profile_images_user_id_status (user_id, status)
+);
@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS user_settings
+(
+    user_id    VARCHAR(100) PRIMARY KEY,
+    version    INT          NOT NULL DEFAULT 0,
+    settings   JSON         NOT NULL,
+    created_at timestamp    NOT NULL,
+    updated_at timestamp    NOT NULL
+);
@@ -0,0 +1 @@
+ALTER TABLE users MODIFY language VARCHAR(35) NOT NULL;
@@ -0,0 +1,3 @@
+-- Only TH and EN existed before locales were expanded
+UPDATE users
+SET language = CASE WHEN language = 'th' THEN 'TH' ELSE 'EN' END;
@@ -0,0 +1,6 @@
+-- Languages used to be stored as upper-case enum names, store lower-case BCP-47 tags instead
+UPDATE users
+SET language = CASE
+                   WHEN UPPER(language) IN ('EN', 'TH', 'JA', 'KO', 'ES', 'FR', 'DE', 'ID', 'VI') THEN LOWER(language)
+                   ELSE 'en'
+    END;
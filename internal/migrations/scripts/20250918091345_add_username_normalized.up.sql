@@ -0,0 +1,4 @@
+-- Existing rows are backfilled by `db migrate` once migrations have run. NULLs never collide.
+ALTER TABLE users
+    ADD COLUMN username_normalized VARCHAR(255) NULL AFTER username,
+    ADD UNIQUE INDEX idx_users_username_normalized (username_normalized);
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS username_history
+(
+    id                  VARCHAR(100) PRIMARY KEY,
+    user_id             VARCHAR(100) NOT NULL,
+    username            VARCHAR(255) NOT NULL,
+    username_normalized VARCHAR(255) NOT NULL,
+    created_at          timestamp    NOT NULL,
+    updated_at          timestamp    NOT NULL,
+    INDEX idx_username_history_username_normalized (username_normalized, created_at),
+    INDEX idx_username_history_user_id (user_id, created_at)
+);
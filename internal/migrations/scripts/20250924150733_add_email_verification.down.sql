@@ -0,0 +1,3 @@
+ALTER TABLE users
+    DROP COLUMN email_verification_token_hash,
+    DROP COLUMN email_verified_at;
@@ -0,0 +1,3 @@
+ALTER TABLE users
+    ADD COLUMN email_verified_at timestamp NULL AFTER email,
+    ADD COLUMN email_verification_token_hash CHAR(64) NULL AFTER email_verified_at;
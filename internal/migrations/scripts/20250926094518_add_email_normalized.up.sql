@@ -0,0 +1,4 @@
+-- Existing rows are backfilled by `db migrate` once migrations have run. NULLs never collide.
+ALTER TABLE users
+    ADD COLUMN email_normalized VARCHAR(254) NULL AFTER email,
+    ADD UNIQUE INDEX idx_users_email_normalized (email_normalized);
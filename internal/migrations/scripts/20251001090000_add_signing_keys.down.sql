@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS signing_key_locks;
+DROP TABLE IF EXISTS signing_keys;
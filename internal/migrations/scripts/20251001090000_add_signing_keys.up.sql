@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS signing_keys
+(
+    id          VARCHAR(255) PRIMARY KEY,
+    algorithm   VARCHAR(16)  NOT NULL,
+    private_key TEXT         NOT NULL,
+    public_key  TEXT         NOT NULL,
+    created_at  timestamp    NOT NULL,
+    INDEX idx_signing_keys_created_at (created_at)
+);
+
+CREATE TABLE IF NOT EXISTS signing_key_locks
+(
+    name       VARCHAR(100) PRIMARY KEY,
+    holder     VARCHAR(100) NOT NULL,
+    expires_at timestamp    NOT NULL
+);
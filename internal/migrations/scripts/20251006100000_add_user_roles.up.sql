@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS user_roles
+(
+    user_id    VARCHAR(100) NOT NULL,
+    role       VARCHAR(32)  NOT NULL,
+    created_at timestamp    NOT NULL,
+    PRIMARY KEY (user_id, role)
+);
+
+CREATE TABLE IF NOT EXISTS role_changes
+(
+    id         VARCHAR(100) PRIMARY KEY,
+    user_id    VARCHAR(100) NOT NULL,
+    role       VARCHAR(32)  NOT NULL,
+    action     VARCHAR(16)  NOT NULL,
+    actor_id   VARCHAR(100) NOT NULL,
+    reason     TEXT,
+    created_at timestamp    NOT NULL,
+    updated_at timestamp    NOT NULL,
+    INDEX idx_role_changes_user_id (user_id, created_at)
+);
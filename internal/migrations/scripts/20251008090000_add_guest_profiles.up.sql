@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS guest_profiles
+(
+    guest_id   VARCHAR(100) PRIMARY KEY,
+    language   VARCHAR(16)  NOT NULL,
+    settings   JSON         NOT NULL,
+    expires_at timestamp    NOT NULL,
+    created_at timestamp    NOT NULL,
+    updated_at timestamp    NOT NULL,
+    INDEX idx_guest_profiles_expires_at (expires_at)
+);
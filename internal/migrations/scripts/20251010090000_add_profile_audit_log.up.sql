@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS profile_audit_log
+(
+    id         VARCHAR(100) PRIMARY KEY,
+    user_id    VARCHAR(100) NOT NULL,
+    actor_id   VARCHAR(100) NOT NULL,
+    action     VARCHAR(64)  NOT NULL,
+    changes    JSON         NOT NULL,
+    ip         VARCHAR(64),
+    user_agent TEXT,
+    trace_id   VARCHAR(32),
+    created_at timestamp    NOT NULL,
+    updated_at timestamp    NOT NULL,
+    INDEX idx_profile_audit_log_user_id (user_id, created_at),
+    INDEX idx_profile_audit_log_created_at (created_at)
+);
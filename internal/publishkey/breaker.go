@@ -0,0 +1,62 @@
+package publishkey
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/weeb-vip/user-service/metrics"
+)
+
+// ErrCircuitOpen is returned without calling the key-management service while it keeps failing.
+var ErrCircuitOpen = errors.New("key-management service circuit is open")
+
+// breaker opens after failureThreshold consecutive failures. Once openFor has passed it lets a
+// single call through, closing again if it succeeds and reopening if it fails.
+type breaker struct {
+	mu               sync.Mutex
+	failureThreshold int
+	openFor          time.Duration
+	failures         int
+	openedAt         time.Time
+	trialInFlight    bool
+}
+
+func newBreaker(failureThreshold int, openFor time.Duration) *breaker {
+	return &breaker{failureThreshold: failureThreshold, openFor: openFor}
+}
+
+// allow reports whether a call may go ahead. Every allowed call must be followed by record.
+func (b *breaker) allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.failures < b.failureThreshold {
+		return nil
+	}
+	if time.Since(b.openedAt) < b.openFor || b.trialInFlight {
+		return ErrCircuitOpen
+	}
+
+	b.trialInFlight = true
+
+	return nil
+}
+
+func (b *breaker) record(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.trialInFlight = false
+	if err == nil {
+		b.failures = 0
+		metrics.GetAppMetrics().KeyPublishCircuitMetric(false)
+		return
+	}
+
+	b.failures++
+	if b.failures >= b.failureThreshold {
+		b.openedAt = time.Now()
+		metrics.GetAppMetrics().KeyPublishCircuitMetric(true)
+	}
+}
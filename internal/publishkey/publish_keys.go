@@ -2,15 +2,20 @@ package publishkey
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/url"
+	"time"
 
 	"github.com/machinebox/graphql"
+	"github.com/weeb-vip/user-service/internal/keypair"
+	"github.com/weeb-vip/user-service/internal/logger"
+	"github.com/weeb-vip/user-service/metrics"
 )
 
 const graphqlOperation = `
-mutation PublishAPublicKey($publicKey: String!){
-  registerPublicKey(publicKey: $publicKey) {
+mutation PublishAPublicKey($publicKey: String!, $algorithm: String!){
+  registerPublicKey(publicKey: $publicKey, algorithm: $algorithm) {
     body
     id
   }
@@ -37,10 +42,14 @@ func (p keyPublisher) getOriginHeader() (string, error) {
 	return fmt.Sprintf("%s://%s:%s", urlInfo.Scheme, urlInfo.Host, urlInfo.Port()), nil
 }
 
-func (p keyPublisher) PublishToKeyManagementService(publicKey string) (string, error) {
+// PublishToKeyManagementService registers publicKey and returns its ID. Failed attempts are
+// retried with exponential backoff until maxAttempts or ctx is done, and while the service keeps
+// failing the circuit breaker fails calls fast with ErrCircuitOpen.
+func (p keyPublisher) PublishToKeyManagementService(ctx context.Context, publicKey string, algorithm keypair.Algorithm) (string, error) {
 	// Publish and get an ID.
 	request := graphql.NewRequest(graphqlOperation)
 	request.Var("publicKey", publicKey)
+	request.Var("algorithm", string(algorithm))
 
 	origin, err := p.getOriginHeader()
 	if err != nil {
@@ -49,18 +58,53 @@ func (p keyPublisher) PublishToKeyManagementService(publicKey string) (string, e
 
 	request.Header.Add("Origin", origin)
 
-	response, err := run[Response](graphql.NewClient(p.graphQLEndpoint), request)
+	log := logger.FromCtx(ctx)
+	backoff := p.initialBackoff
+	for attempt := 1; ; attempt++ {
+		response, err := p.attempt(ctx, request)
+		if err == nil {
+			return response.RegisterPublicKey.ID, nil
+		}
+		if errors.Is(err, ErrCircuitOpen) || attempt >= p.maxAttempts {
+			return "", err
+		}
+
+		log.Warn().Err(err).Int("attempt", attempt).Msg("failed to publish key, retrying")
+		select {
+		case <-ctx.Done():
+			return "", errors.Join(err, ctx.Err())
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, p.maxBackoff)
+	}
+}
+
+func (p keyPublisher) attempt(ctx context.Context, request *graphql.Request) (*Response, error) {
+	if err := p.breaker.allow(); err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	attemptCtx, cancel := context.WithTimeout(ctx, p.attemptTimeout)
+	defer cancel()
+
+	response, err := run[Response](attemptCtx, p.client, request)
+	p.breaker.record(err)
+
+	duration := float64(time.Since(start).Nanoseconds()) / float64(time.Millisecond)
+	result := metrics.Success
 	if err != nil {
-		return "", err
+		result = metrics.Error
 	}
+	metrics.GetAppMetrics().KeyPublishMetric(duration, result)
 
-	return response.RegisterPublicKey.ID, nil
+	return response, err
 }
 
-func run[T any](client *graphql.Client, request *graphql.Request) (*T, error) {
+func run[T any](ctx context.Context, client graphQLClient, request *graphql.Request) (*T, error) {
 	response := new(T)
 
-	err := client.Run(context.Background(), request, &response)
+	err := client.Run(ctx, request, &response)
 	if err != nil {
 		return nil, err
 	}
@@ -15,8 +15,8 @@ package publishkey_test
 //// later, we'll most likely introduce proper integration test
 //
 //func TestNewKeyPublisher(t *testing.T) {
-//	publisher := publishkey.NewKeyPublisher("http://localhost:5001/graphql")
-//	id, err := publisher.PublishToKeyManagementService("my-public-key")
+//	publisher := publishkey.NewKeyPublisher("http://localhost:5001/graphql", config.KeyPublishConfig{MaxAttempts: 1})
+//	id, err := publisher.PublishToKeyManagementService(context.Background(), "my-public-key", keypair.AlgorithmRS256)
 //	assert.NoError(t, err)
 //	assert.NotEmpty(t, id)
 //}
@@ -0,0 +1,99 @@
+package publishkey_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/weeb-vip/user-service/config"
+	"github.com/weeb-vip/user-service/internal/keypair"
+	"github.com/weeb-vip/user-service/internal/publishkey"
+)
+
+func newKeyManagementService(t *testing.T, failures int32) (*httptest.Server, *atomic.Int32) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, _ *http.Request) {
+		if calls.Add(1) <= failures {
+			writer.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		writer.Header().Set("Content-Type", "application/json")
+		_, _ = writer.Write([]byte(`{"data":{"registerPublicKey":{"id":"key_1","body":"pem"}}}`))
+	}))
+	t.Cleanup(server.Close)
+
+	return server, &calls
+}
+
+func testConfig() config.KeyPublishConfig {
+	return config.KeyPublishConfig{
+		AttemptTimeoutMs:   1000,
+		MaxAttempts:        3,
+		InitialBackoffMs:   1,
+		MaxBackoffMs:       5,
+		BreakerFailures:    5,
+		BreakerOpenSeconds: 60,
+	}
+}
+
+func TestPublishToKeyManagementService(t *testing.T) {
+	t.Run("retries failed attempts", func(t *testing.T) {
+		server, calls := newKeyManagementService(t, 2)
+
+		id, err := publishkey.NewKeyPublisher(server.URL, testConfig()).
+			PublishToKeyManagementService(context.Background(), "pem", keypair.AlgorithmRS256)
+		require.NoError(t, err)
+		assert.Equal(t, "key_1", id)
+		assert.Equal(t, int32(3), calls.Load())
+	})
+	t.Run("gives up after max attempts", func(t *testing.T) {
+		server, calls := newKeyManagementService(t, 10)
+
+		_, err := publishkey.NewKeyPublisher(server.URL, testConfig()).
+			PublishToKeyManagementService(context.Background(), "pem", keypair.AlgorithmRS256)
+		assert.Error(t, err)
+		assert.Equal(t, int32(3), calls.Load())
+	})
+	t.Run("fails fast once the circuit opens", func(t *testing.T) {
+		server, calls := newKeyManagementService(t, 10)
+		cfg := testConfig()
+		cfg.BreakerFailures = 2
+		publisher := publishkey.NewKeyPublisher(server.URL, cfg)
+
+		_, err := publisher.PublishToKeyManagementService(context.Background(), "pem", keypair.AlgorithmRS256)
+		assert.ErrorIs(t, err, publishkey.ErrCircuitOpen)
+		assert.Equal(t, int32(2), calls.Load())
+
+		_, err = publisher.PublishToKeyManagementService(context.Background(), "pem", keypair.AlgorithmRS256)
+		assert.ErrorIs(t, err, publishkey.ErrCircuitOpen)
+		assert.Equal(t, int32(2), calls.Load(), "the service isn't called while the circuit is open")
+	})
+	t.Run("half-open circuit closes after a success", func(t *testing.T) {
+		server, calls := newKeyManagementService(t, 2)
+		cfg := testConfig()
+		cfg.BreakerFailures = 2
+		cfg.BreakerOpenSeconds = 0
+		publisher := publishkey.NewKeyPublisher(server.URL, cfg)
+
+		id, err := publisher.PublishToKeyManagementService(context.Background(), "pem", keypair.AlgorithmRS256)
+		require.NoError(t, err)
+		assert.Equal(t, "key_1", id)
+		assert.Equal(t, int32(3), calls.Load())
+	})
+	t.Run("stops retrying when the context is done", func(t *testing.T) {
+		server, _ := newKeyManagementService(t, 10)
+		cfg := testConfig()
+		cfg.InitialBackoffMs = int(time.Hour.Milliseconds())
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		_, err := publishkey.NewKeyPublisher(server.URL, cfg).
+			PublishToKeyManagementService(ctx, "pem", keypair.AlgorithmRS256)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
@@ -1,13 +1,40 @@
 package publishkey
 
+import (
+	"context"
+	"time"
+
+	"github.com/machinebox/graphql"
+	"github.com/weeb-vip/user-service/config"
+	"github.com/weeb-vip/user-service/internal/keypair"
+)
+
 type KeyPublisher interface {
-	PublishToKeyManagementService(publicKey string) (string, error)
+	PublishToKeyManagementService(ctx context.Context, publicKey string, algorithm keypair.Algorithm) (string, error)
 }
 
 type keyPublisher struct {
 	graphQLEndpoint string
+	client          graphQLClient
+	attemptTimeout  time.Duration
+	maxAttempts     int
+	initialBackoff  time.Duration
+	maxBackoff      time.Duration
+	breaker         *breaker
+}
+
+type graphQLClient interface {
+	Run(ctx context.Context, request *graphql.Request, response interface{}) error
 }
 
-func NewKeyPublisher(graphqlEndpoint string) KeyPublisher {
-	return keyPublisher{graphQLEndpoint: graphqlEndpoint}
+func NewKeyPublisher(graphqlEndpoint string, cfg config.KeyPublishConfig) KeyPublisher {
+	return keyPublisher{
+		graphQLEndpoint: graphqlEndpoint,
+		client:          graphql.NewClient(graphqlEndpoint),
+		attemptTimeout:  time.Duration(cfg.AttemptTimeoutMs) * time.Millisecond,
+		maxAttempts:     max(cfg.MaxAttempts, 1),
+		initialBackoff:  time.Duration(cfg.InitialBackoffMs) * time.Millisecond,
+		maxBackoff:      time.Duration(cfg.MaxBackoffMs) * time.Millisecond,
+		breaker:         newBreaker(max(cfg.BreakerFailures, 1), time.Duration(cfg.BreakerOpenSeconds)*time.Second),
+	}
 }
@@ -0,0 +1,63 @@
+package ratelimit
+
+import (
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// idleTimeout is how long a key's bucket is kept after its last request. A bucket idle this
+// long has refilled anyway, so dropping it changes nothing for the caller.
+const idleTimeout = 10 * time.Minute
+
+// Limiter applies a token bucket per key, such as a user ID or client IP. State is kept in
+// memory, so each replica enforces its own limit.
+type Limiter struct {
+	mu        sync.Mutex
+	limit     rate.Limit
+	burst     int
+	buckets   map[string]*bucket
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+type bucket struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// NewPerMinute allows perMinute requests per key on average, with bursts of up to burst.
+func NewPerMinute(perMinute int, burst int) *Limiter {
+	return &Limiter{
+		limit:   rate.Limit(float64(perMinute) / time.Minute.Seconds()),
+		burst:   burst,
+		buckets: map[string]*bucket{},
+		now:     time.Now,
+	}
+}
+
+// Allow reports whether key may make another request now, consuming a token if so.
+func (l *Limiter) Allow(key string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	if now.Sub(l.lastSweep) > idleTimeout {
+		for k, b := range l.buckets {
+			if now.Sub(b.lastSeen) > idleTimeout {
+				delete(l.buckets, k)
+			}
+		}
+		l.lastSweep = now
+	}
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{limiter: rate.NewLimiter(l.limit, l.burst)}
+		l.buckets[key] = b
+	}
+	b.lastSeen = now
+
+	return b.limiter.AllowN(now, 1)
+}
@@ -0,0 +1,37 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLimiter_Allow(t *testing.T) {
+	now := time.Date(2025, 9, 20, 12, 0, 0, 0, time.UTC)
+	limiter := NewPerMinute(60, 2)
+	limiter.now = func() time.Time { return now }
+
+	assert.True(t, limiter.Allow("a"))
+	assert.True(t, limiter.Allow("a"))
+	assert.False(t, limiter.Allow("a"), "burst exhausted")
+	assert.True(t, limiter.Allow("b"), "keys are limited independently")
+
+	now = now.Add(time.Second)
+	assert.True(t, limiter.Allow("a"), "one token refills per second")
+	assert.False(t, limiter.Allow("a"))
+}
+
+func TestLimiter_EvictsIdleKeys(t *testing.T) {
+	now := time.Date(2025, 9, 20, 12, 0, 0, 0, time.UTC)
+	limiter := NewPerMinute(60, 1)
+	limiter.now = func() time.Time { return now }
+
+	limiter.Allow("a")
+	limiter.Allow("b")
+	assert.Len(t, limiter.buckets, 2)
+
+	now = now.Add(idleTimeout + time.Second)
+	limiter.Allow("b")
+	assert.Len(t, limiter.buckets, 1)
+}
@@ -0,0 +1,163 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/weeb-vip/user-service/graph/model"
+	"github.com/weeb-vip/user-service/http/handlers/requestinfo"
+	"github.com/weeb-vip/user-service/internal/services/audit"
+	"github.com/weeb-vip/user-service/internal/services/audit/models"
+	"github.com/weeb-vip/user-service/metrics"
+	"github.com/weeb-vip/user-service/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// MyAuditLog lists changes to the caller's profile. The IP and user agent of changes someone
+// else made, such as a moderator, are left out.
+func MyAuditLog( // nolint
+	ctx context.Context,
+	auditLog audit.AuditLog,
+	limit *int,
+	offset *int,
+) ([]*model.AuditEntry, error) {
+	tracer := tracing.GetTracer(ctx)
+	ctx, span := tracer.Start(ctx, "MyAuditLog",
+		trace.WithAttributes(
+			attribute.String("resolver.name", "MyAuditLog"),
+		),
+		tracing.GetEnvironmentAttribute(),
+	)
+	defer span.End()
+
+	startTime := time.Now()
+	req := requestinfo.FromContext(ctx)
+
+	if req.UserID == nil {
+		metrics.GetAppMetrics().ResolverMetric(
+			float64(time.Since(startTime).Milliseconds()),
+			"MyAuditLog",
+			metrics.Error,
+		)
+		return nil, fmt.Errorf("unauthorized")
+	}
+
+	span.SetAttributes(attribute.String("user.id", *req.UserID))
+
+	entries, err := listAuditEntries(ctx, auditLog, req.UserID, limit, offset)
+	if err != nil {
+		metrics.GetAppMetrics().ResolverMetric(
+			float64(time.Since(startTime).Milliseconds()),
+			"MyAuditLog",
+			metrics.Error,
+		)
+		return nil, err
+	}
+
+	metrics.GetAppMetrics().ResolverMetric(
+		float64(time.Since(startTime).Milliseconds()),
+		"MyAuditLog",
+		metrics.Success,
+	)
+
+	result := make([]*model.AuditEntry, 0, len(entries))
+	for _, entry := range entries {
+		result = append(result, toAuditEntryModel(entry, entry.ActorID == *req.UserID))
+	}
+
+	return result, nil
+}
+
+// AuditLog lists changes to every user's profile, or to userID's when it is set.
+func AuditLog( // nolint
+	ctx context.Context,
+	auditLog audit.AuditLog,
+	userID *string,
+	limit *int,
+	offset *int,
+) ([]*model.AuditEntry, error) {
+	tracer := tracing.GetTracer(ctx)
+	ctx, span := tracer.Start(ctx, "AuditLog",
+		trace.WithAttributes(
+			attribute.String("resolver.name", "AuditLog"),
+		),
+		tracing.GetEnvironmentAttribute(),
+	)
+	defer span.End()
+
+	startTime := time.Now()
+
+	if userID != nil {
+		span.SetAttributes(attribute.String("target.user.id", *userID))
+	}
+
+	entries, err := listAuditEntries(ctx, auditLog, userID, limit, offset)
+	if err != nil {
+		metrics.GetAppMetrics().ResolverMetric(
+			float64(time.Since(startTime).Milliseconds()),
+			"AuditLog",
+			metrics.Error,
+		)
+		return nil, err
+	}
+
+	metrics.GetAppMetrics().ResolverMetric(
+		float64(time.Since(startTime).Milliseconds()),
+		"AuditLog",
+		metrics.Success,
+	)
+
+	result := make([]*model.AuditEntry, 0, len(entries))
+	for _, entry := range entries {
+		result = append(result, toAuditEntryModel(entry, true))
+	}
+
+	return result, nil
+}
+
+func listAuditEntries(
+	ctx context.Context,
+	auditLog audit.AuditLog,
+	userID *string,
+	limit *int,
+	offset *int,
+) ([]*models.Entry, error) {
+	pageLimit, pageOffset := 0, 0
+	if limit != nil {
+		pageLimit = *limit
+	}
+	if offset != nil {
+		pageOffset = *offset
+	}
+
+	return auditLog.ListEntries(ctx, userID, pageLimit, pageOffset)
+}
+
+// toAuditEntryModel converts entry, leaving out where the request came from unless showOrigin.
+func toAuditEntryModel(entry *models.Entry, showOrigin bool) *model.AuditEntry {
+	result := &model.AuditEntry{
+		ID:        entry.ID,
+		UserID:    entry.UserID,
+		ActorID:   entry.ActorID,
+		Action:    entry.Action,
+		Changes:   make([]*model.FieldChange, 0, len(entry.Changes)),
+		TraceID:   entry.TraceID,
+		CreatedAt: entry.CreatedAt,
+	}
+	if showOrigin {
+		result.IP = entry.IP
+		result.UserAgent = entry.UserAgent
+	}
+	for _, change := range entry.Changes {
+		result.Changes = append(result.Changes, &model.FieldChange{
+			Field:    change.Field,
+			Before:   change.Before,
+			After:    change.After,
+			Redacted: change.Redacted,
+		})
+	}
+
+	return result
+}
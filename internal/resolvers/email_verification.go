@@ -0,0 +1,109 @@
+package resolvers
+
+import (
+	"context"
+	"time"
+
+	"github.com/weeb-vip/user-service/graph/model"
+	"github.com/weeb-vip/user-service/http/handlers/requestinfo"
+	"github.com/weeb-vip/user-service/internal/logger"
+	"github.com/weeb-vip/user-service/internal/services/users"
+	"github.com/weeb-vip/user-service/metrics"
+	"github.com/weeb-vip/user-service/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func RequestEmailVerification( // nolint
+	ctx context.Context,
+	userService users.User,
+) (bool, error) {
+	tracer := tracing.GetTracer(ctx)
+	ctx, span := tracer.Start(ctx, "RequestEmailVerification",
+		trace.WithAttributes(
+			attribute.String("resolver.name", "RequestEmailVerification"),
+		),
+		tracing.GetEnvironmentAttribute(),
+	)
+	defer span.End()
+
+	startTime := time.Now()
+	log := logger.FromCtx(ctx)
+	req := requestinfo.FromContext(ctx)
+
+	userID := req.UserID
+	if userID == nil {
+		log.Error().Msg("User ID is missing")
+		metrics.GetAppMetrics().ResolverMetric(
+			float64(time.Since(startTime).Milliseconds()),
+			"RequestEmailVerification",
+			metrics.Error,
+		)
+		return false, nil
+	}
+
+	span.SetAttributes(attribute.String("user.id", *userID))
+
+	if err := userService.RequestEmailVerification(ctx, *userID); err != nil {
+		metrics.GetAppMetrics().ResolverMetric(
+			float64(time.Since(startTime).Milliseconds()),
+			"RequestEmailVerification",
+			metrics.Error,
+		)
+		return false, err
+	}
+
+	metrics.GetAppMetrics().ResolverMetric(
+		float64(time.Since(startTime).Milliseconds()),
+		"RequestEmailVerification",
+		metrics.Success,
+	)
+
+	return true, nil
+}
+
+func VerifyEmail( // nolint
+	ctx context.Context,
+	userService users.User,
+	token string,
+) (*model.User, error) {
+	tracer := tracing.GetTracer(ctx)
+	ctx, span := tracer.Start(ctx, "VerifyEmail",
+		trace.WithAttributes(
+			attribute.String("resolver.name", "VerifyEmail"),
+		),
+		tracing.GetEnvironmentAttribute(),
+	)
+	defer span.End()
+
+	startTime := time.Now()
+
+	user, err := userService.VerifyEmail(ctx, token)
+	if err != nil {
+		metrics.GetAppMetrics().ResolverMetric(
+			float64(time.Since(startTime).Milliseconds()),
+			"VerifyEmail",
+			metrics.Error,
+		)
+		return nil, err
+	}
+
+	span.SetAttributes(attribute.String("user.id", user.ID))
+
+	metrics.GetAppMetrics().ResolverMetric(
+		float64(time.Since(startTime).Milliseconds()),
+		"VerifyEmail",
+		metrics.Success,
+	)
+
+	// The token holder may not be signed in, so only the verification state is returned
+	return &model.User{
+		ID:              user.ID,
+		Firstname:       user.FirstName,
+		Lastname:        user.LastName,
+		Username:        user.Username,
+		Language:        LanguageFromLocale(user.Language),
+		EmailVerifiedAt: user.EmailVerifiedAt,
+		ProfileImageURL: user.ProfileImageURL,
+	}, nil
+}
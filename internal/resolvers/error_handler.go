@@ -3,10 +3,17 @@ package resolvers
 import (
 	"context"
 	"errors"
+	"github.com/weeb-vip/user-service/internal/services/audit"
+	"github.com/weeb-vip/user-service/internal/services/guests"
+	"github.com/weeb-vip/user-service/internal/services/moderation"
+	"github.com/weeb-vip/user-service/internal/services/roles"
+	"github.com/weeb-vip/user-service/internal/services/servicetokens"
+	"github.com/weeb-vip/user-service/internal/services/settings"
 	"github.com/weeb-vip/user-service/internal/services/users"
 	"log"
 
 	"github.com/99designs/gqlgen/graphql"
+	"github.com/vektah/gqlparser/v2/gqlerror"
 
 	"github.com/weeb-vip/user-service/internal/entities"
 	"github.com/weeb-vip/user-service/internal/xerrors"
@@ -21,12 +28,62 @@ func handleError(ctx context.Context, result interface{}, err error) (interface{
 	return result, nil
 }
 
+// ErrorPresenter exposes the service error code of resolver errors as the "code" extension, so
+// clients can tell e.g. USERNAME_TAKEN from USERNAME_INVALID without parsing messages.
+func ErrorPresenter(ctx context.Context, err error) *gqlerror.Error {
+	presented := graphql.DefaultErrorPresenter(ctx, err)
+
+	code := getCode(err)
+	if code == "UNKNOWN_ERROR" {
+		return presented
+	}
+
+	if presented.Extensions == nil {
+		presented.Extensions = map[string]interface{}{}
+	}
+	if _, ok := presented.Extensions["code"]; !ok {
+		presented.Extensions["code"] = code
+	}
+
+	return presented
+}
+
 func getCode(err error) string {
 	var credErr *users.Error
 	if ok := errors.As(err, &credErr); ok {
 		return credErr.Code.String()
 	}
 
+	var moderationErr *moderation.Error
+	if ok := errors.As(err, &moderationErr); ok {
+		return moderationErr.Code.String()
+	}
+
+	var settingsErr *settings.Error
+	if ok := errors.As(err, &settingsErr); ok {
+		return settingsErr.Code.String()
+	}
+
+	var serviceTokenErr *servicetokens.Error
+	if ok := errors.As(err, &serviceTokenErr); ok {
+		return serviceTokenErr.Code.String()
+	}
+
+	var rolesErr *roles.Error
+	if ok := errors.As(err, &rolesErr); ok {
+		return rolesErr.Code.String()
+	}
+
+	var guestsErr *guests.Error
+	if ok := errors.As(err, &guestsErr); ok {
+		return guestsErr.Code.String()
+	}
+
+	var auditErr *audit.Error
+	if ok := errors.As(err, &auditErr); ok {
+		return auditErr.Code.String()
+	}
+
 	var servErr *entities.ServiceError
 	if ok := errors.As(err, &servErr); ok {
 		return servErr.Code
@@ -0,0 +1,174 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/weeb-vip/user-service/graph/model"
+	"github.com/weeb-vip/user-service/http/handlers/requestinfo"
+	"github.com/weeb-vip/user-service/internal/services/guests"
+	"github.com/weeb-vip/user-service/internal/services/guests/models"
+	"github.com/weeb-vip/user-service/metrics"
+	"github.com/weeb-vip/user-service/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func GetGuestProfile(ctx context.Context, guestService guests.Guests) (*model.GuestProfile, error) { // nolint
+	tracer := tracing.GetTracer(ctx)
+	ctx, span := tracer.Start(ctx, "GetGuestProfile",
+		trace.WithAttributes(
+			attribute.String("resolver.name", "GetGuestProfile"),
+		),
+		tracing.GetEnvironmentAttribute(),
+	)
+	defer span.End()
+
+	startTime := time.Now()
+	req := requestinfo.FromContext(ctx)
+
+	if req.UserID == nil {
+		metrics.GetAppMetrics().ResolverMetric(
+			float64(time.Since(startTime).Milliseconds()),
+			"GetGuestProfile",
+			metrics.Error,
+		)
+		return nil, fmt.Errorf("unauthorized")
+	}
+
+	span.SetAttributes(attribute.String("user.id", *req.UserID))
+
+	profile, err := guestService.GetProfile(ctx, *req.UserID)
+	if err != nil {
+		metrics.GetAppMetrics().ResolverMetric(
+			float64(time.Since(startTime).Milliseconds()),
+			"GetGuestProfile",
+			metrics.Error,
+		)
+		return nil, err
+	}
+
+	metrics.GetAppMetrics().ResolverMetric(
+		float64(time.Since(startTime).Milliseconds()),
+		"GetGuestProfile",
+		metrics.Success,
+	)
+
+	return toGuestProfileModel(profile), nil
+}
+
+func UpdateGuestProfile( // nolint
+	ctx context.Context,
+	guestService guests.Guests,
+	input *model.UpdateGuestProfileInput,
+) (*model.GuestProfile, error) {
+	tracer := tracing.GetTracer(ctx)
+	ctx, span := tracer.Start(ctx, "UpdateGuestProfile",
+		trace.WithAttributes(
+			attribute.String("resolver.name", "UpdateGuestProfile"),
+		),
+		tracing.GetEnvironmentAttribute(),
+	)
+	defer span.End()
+
+	startTime := time.Now()
+	req := requestinfo.FromContext(ctx)
+
+	if req.UserID == nil {
+		metrics.GetAppMetrics().ResolverMetric(
+			float64(time.Since(startTime).Milliseconds()),
+			"UpdateGuestProfile",
+			metrics.Error,
+		)
+		return nil, fmt.Errorf("unauthorized")
+	}
+
+	span.SetAttributes(attribute.String("user.id", *req.UserID))
+
+	var language *string
+	if input.Language != nil {
+		language = new(string)
+		*language = input.Language.String()
+	}
+
+	profile, err := guestService.UpdateProfile(ctx, *req.UserID, language, nil)
+	if err != nil {
+		metrics.GetAppMetrics().ResolverMetric(
+			float64(time.Since(startTime).Milliseconds()),
+			"UpdateGuestProfile",
+			metrics.Error,
+		)
+		return nil, err
+	}
+
+	metrics.GetAppMetrics().ResolverMetric(
+		float64(time.Since(startTime).Milliseconds()),
+		"UpdateGuestProfile",
+		metrics.Success,
+	)
+
+	return toGuestProfileModel(profile), nil
+}
+
+// UpgradeGuest carries guestID's settings over to the caller, who has just signed up.
+func UpgradeGuest( // nolint
+	ctx context.Context,
+	guestService guests.Guests,
+	guestID string,
+) (*model.UserSettings, error) {
+	tracer := tracing.GetTracer(ctx)
+	ctx, span := tracer.Start(ctx, "UpgradeGuest",
+		trace.WithAttributes(
+			attribute.String("resolver.name", "UpgradeGuest"),
+			attribute.String("guest.id", guestID),
+		),
+		tracing.GetEnvironmentAttribute(),
+	)
+	defer span.End()
+
+	startTime := time.Now()
+	req := requestinfo.FromContext(ctx)
+
+	if req.UserID == nil {
+		metrics.GetAppMetrics().ResolverMetric(
+			float64(time.Since(startTime).Milliseconds()),
+			"UpgradeGuest",
+			metrics.Error,
+		)
+		return nil, fmt.Errorf("unauthorized")
+	}
+
+	span.SetAttributes(attribute.String("user.id", *req.UserID))
+
+	userSettings, err := guestService.UpgradeGuest(ctx, guestID, *req.UserID)
+	if err != nil {
+		metrics.GetAppMetrics().ResolverMetric(
+			float64(time.Since(startTime).Milliseconds()),
+			"UpgradeGuest",
+			metrics.Error,
+		)
+		return nil, err
+	}
+
+	metrics.GetAppMetrics().ResolverMetric(
+		float64(time.Since(startTime).Milliseconds()),
+		"UpgradeGuest",
+		metrics.Success,
+	)
+
+	return toUserSettingsModel(userSettings), nil
+}
+
+func toGuestProfileModel(profile *models.GuestProfile) *model.GuestProfile {
+	result := &model.GuestProfile{
+		ID:       profile.GuestID,
+		Language: LanguageFromLocale(profile.Language),
+		Settings: toUserSettingsModel(guests.SettingsOf(profile)),
+	}
+	if !profile.ExpiresAt.IsZero() {
+		result.ExpiresAt = &profile.ExpiresAt
+	}
+
+	return result
+}
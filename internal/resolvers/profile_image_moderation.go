@@ -0,0 +1,190 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/weeb-vip/user-service/graph/model"
+	"github.com/weeb-vip/user-service/http/handlers/requestinfo"
+	"github.com/weeb-vip/user-service/internal/services/moderation"
+	"github.com/weeb-vip/user-service/internal/services/moderation/models"
+	"github.com/weeb-vip/user-service/metrics"
+	"github.com/weeb-vip/user-service/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func ListPendingProfileImages( // nolint
+	ctx context.Context,
+	moderationService moderation.Moderation,
+	limit *int,
+	offset *int,
+) ([]*model.ProfileImage, error) {
+	tracer := tracing.GetTracer(ctx)
+	ctx, span := tracer.Start(ctx, "ListPendingProfileImages",
+		trace.WithAttributes(
+			attribute.String("resolver.name", "ListPendingProfileImages"),
+		),
+		tracing.GetEnvironmentAttribute(),
+	)
+	defer span.End()
+
+	startTime := time.Now()
+
+	pageLimit, pageOffset := 0, 0
+	if limit != nil {
+		pageLimit = *limit
+	}
+	if offset != nil {
+		pageOffset = *offset
+	}
+
+	profileImages, err := moderationService.ListPending(ctx, pageLimit, pageOffset)
+	if err != nil {
+		metrics.GetAppMetrics().ResolverMetric(
+			float64(time.Since(startTime).Milliseconds()),
+			"ListPendingProfileImages",
+			metrics.Error,
+		)
+		return nil, err
+	}
+
+	metrics.GetAppMetrics().ResolverMetric(
+		float64(time.Since(startTime).Milliseconds()),
+		"ListPendingProfileImages",
+		metrics.Success,
+	)
+
+	result := make([]*model.ProfileImage, 0, len(profileImages))
+	for _, profileImage := range profileImages {
+		result = append(result, toProfileImageModel(profileImage))
+	}
+
+	return result, nil
+}
+
+func ApproveProfileImage( // nolint
+	ctx context.Context,
+	moderationService moderation.Moderation,
+	id string,
+) (*model.ProfileImage, error) {
+	tracer := tracing.GetTracer(ctx)
+	ctx, span := tracer.Start(ctx, "ApproveProfileImage",
+		trace.WithAttributes(
+			attribute.String("resolver.name", "ApproveProfileImage"),
+			attribute.String("profile_image.id", id),
+		),
+		tracing.GetEnvironmentAttribute(),
+	)
+	defer span.End()
+
+	startTime := time.Now()
+	req := requestinfo.FromContext(ctx)
+
+	if req.UserID == nil {
+		metrics.GetAppMetrics().ResolverMetric(
+			float64(time.Since(startTime).Milliseconds()),
+			"ApproveProfileImage",
+			metrics.Error,
+		)
+		return nil, fmt.Errorf("unauthorized")
+	}
+
+	profileImage, err := moderationService.Approve(ctx, id, *req.UserID)
+	if err != nil {
+		metrics.GetAppMetrics().ResolverMetric(
+			float64(time.Since(startTime).Milliseconds()),
+			"ApproveProfileImage",
+			metrics.Error,
+		)
+		return nil, err
+	}
+
+	metrics.GetAppMetrics().ResolverMetric(
+		float64(time.Since(startTime).Milliseconds()),
+		"ApproveProfileImage",
+		metrics.Success,
+	)
+
+	return toProfileImageModel(profileImage), nil
+}
+
+func RejectProfileImage( // nolint
+	ctx context.Context,
+	moderationService moderation.Moderation,
+	id string,
+	reason *string,
+) (*model.ProfileImage, error) {
+	tracer := tracing.GetTracer(ctx)
+	ctx, span := tracer.Start(ctx, "RejectProfileImage",
+		trace.WithAttributes(
+			attribute.String("resolver.name", "RejectProfileImage"),
+			attribute.String("profile_image.id", id),
+		),
+		tracing.GetEnvironmentAttribute(),
+	)
+	defer span.End()
+
+	startTime := time.Now()
+	req := requestinfo.FromContext(ctx)
+
+	if req.UserID == nil {
+		metrics.GetAppMetrics().ResolverMetric(
+			float64(time.Since(startTime).Milliseconds()),
+			"RejectProfileImage",
+			metrics.Error,
+		)
+		return nil, fmt.Errorf("unauthorized")
+	}
+
+	profileImage, err := moderationService.Reject(ctx, id, *req.UserID, reason)
+	if err != nil {
+		metrics.GetAppMetrics().ResolverMetric(
+			float64(time.Since(startTime).Milliseconds()),
+			"RejectProfileImage",
+			metrics.Error,
+		)
+		return nil, err
+	}
+
+	metrics.GetAppMetrics().ResolverMetric(
+		float64(time.Since(startTime).Milliseconds()),
+		"RejectProfileImage",
+		metrics.Success,
+	)
+
+	return toProfileImageModel(profileImage), nil
+}
+
+// GetPendingProfileImage resolves User.pendingProfileImage. Pending uploads are only visible to their owner.
+func GetPendingProfileImage( // nolint
+	ctx context.Context,
+	moderationService moderation.Moderation,
+	user *model.User,
+) (*model.ProfileImage, error) {
+	req := requestinfo.FromContext(ctx)
+	if req.UserID == nil || *req.UserID != user.ID {
+		return nil, nil
+	}
+
+	profileImage, err := moderationService.GetPendingForUser(ctx, user.ID)
+	if err != nil || profileImage == nil {
+		return nil, err
+	}
+
+	return toProfileImageModel(profileImage), nil
+}
+
+func toProfileImageModel(profileImage *models.ProfileImage) *model.ProfileImage {
+	return &model.ProfileImage{
+		ID:         profileImage.ID,
+		UserID:     profileImage.UserID,
+		URL:        profileImage.Path,
+		Status:     model.ProfileImageStatus(strings.ToUpper(profileImage.Status.String())),
+		Reason:     profileImage.Reason,
+		CreatedAt:  profileImage.CreatedAt,
+		ReviewedAt: profileImage.ReviewedAt,
+	}
+}
@@ -0,0 +1,146 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/weeb-vip/user-service/graph/model"
+	"github.com/weeb-vip/user-service/http/handlers/requestinfo"
+	"github.com/weeb-vip/user-service/internal/services/roles"
+	"github.com/weeb-vip/user-service/metrics"
+	"github.com/weeb-vip/user-service/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func MyRoles(ctx context.Context, rolesService roles.Roles) (*model.UserRoles, error) { // nolint
+	tracer := tracing.GetTracer(ctx)
+	ctx, span := tracer.Start(ctx, "MyRoles",
+		trace.WithAttributes(
+			attribute.String("resolver.name", "MyRoles"),
+		),
+		tracing.GetEnvironmentAttribute(),
+	)
+	defer span.End()
+
+	startTime := time.Now()
+	req := requestinfo.FromContext(ctx)
+
+	if req.UserID == nil {
+		metrics.GetAppMetrics().ResolverMetric(
+			float64(time.Since(startTime).Milliseconds()),
+			"MyRoles",
+			metrics.Error,
+		)
+		return nil, fmt.Errorf("unauthorized")
+	}
+
+	span.SetAttributes(attribute.String("user.id", *req.UserID))
+
+	userRoles, err := rolesService.RolesFor(ctx, *req.UserID)
+	if err != nil {
+		metrics.GetAppMetrics().ResolverMetric(
+			float64(time.Since(startTime).Milliseconds()),
+			"MyRoles",
+			metrics.Error,
+		)
+		return nil, err
+	}
+
+	metrics.GetAppMetrics().ResolverMetric(
+		float64(time.Since(startTime).Milliseconds()),
+		"MyRoles",
+		metrics.Success,
+	)
+
+	return toUserRolesModel(userRoles), nil
+}
+
+func AssignRole( // nolint
+	ctx context.Context,
+	rolesService roles.Roles,
+	userID string,
+	role model.Role,
+	reason *string,
+) (*model.UserRoles, error) {
+	return changeRole(ctx, "AssignRole", rolesService.AssignRole, userID, role, reason)
+}
+
+func RevokeRole( // nolint
+	ctx context.Context,
+	rolesService roles.Roles,
+	userID string,
+	role model.Role,
+	reason *string,
+) (*model.UserRoles, error) {
+	return changeRole(ctx, "RevokeRole", rolesService.RevokeRole, userID, role, reason)
+}
+
+type roleChange func(ctx context.Context, actorID string, userID string, role roles.Role, reason *string) (*roles.UserRoles, error)
+
+func changeRole(
+	ctx context.Context,
+	name string,
+	change roleChange,
+	userID string,
+	role model.Role,
+	reason *string,
+) (*model.UserRoles, error) {
+	tracer := tracing.GetTracer(ctx)
+	ctx, span := tracer.Start(ctx, name,
+		trace.WithAttributes(
+			attribute.String("resolver.name", name),
+			attribute.String("target.user.id", userID),
+			attribute.String("role", role.String()),
+		),
+		tracing.GetEnvironmentAttribute(),
+	)
+	defer span.End()
+
+	startTime := time.Now()
+	req := requestinfo.FromContext(ctx)
+
+	if req.UserID == nil {
+		metrics.GetAppMetrics().ResolverMetric(
+			float64(time.Since(startTime).Milliseconds()),
+			name,
+			metrics.Error,
+		)
+		return nil, fmt.Errorf("unauthorized")
+	}
+
+	span.SetAttributes(attribute.String("user.id", *req.UserID))
+
+	userRoles, err := change(ctx, *req.UserID, userID, roles.Role(strings.ToLower(role.String())), reason)
+	if err != nil {
+		metrics.GetAppMetrics().ResolverMetric(
+			float64(time.Since(startTime).Milliseconds()),
+			name,
+			metrics.Error,
+		)
+		return nil, err
+	}
+
+	metrics.GetAppMetrics().ResolverMetric(
+		float64(time.Since(startTime).Milliseconds()),
+		name,
+		metrics.Success,
+	)
+
+	return toUserRolesModel(userRoles), nil
+}
+
+func toUserRolesModel(userRoles *roles.UserRoles) *model.UserRoles {
+	result := &model.UserRoles{
+		UserID: userRoles.UserID,
+		Roles:  make([]model.Role, 0, len(userRoles.Roles)),
+		Scopes: userRoles.Scopes,
+	}
+	for _, role := range userRoles.Roles {
+		result.Roles = append(result.Roles, model.Role(strings.ToUpper(role.String())))
+	}
+
+	return result
+}
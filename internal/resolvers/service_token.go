@@ -0,0 +1,76 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/weeb-vip/user-service/graph/model"
+	"github.com/weeb-vip/user-service/http/handlers/requestinfo"
+	"github.com/weeb-vip/user-service/internal/services/servicetokens"
+	"github.com/weeb-vip/user-service/metrics"
+	"github.com/weeb-vip/user-service/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func IssueServiceToken( // nolint
+	ctx context.Context,
+	serviceTokens servicetokens.ServiceTokens,
+	audience string,
+	scopes []string,
+	ttlSeconds *int,
+) (*model.ServiceToken, error) {
+	tracer := tracing.GetTracer(ctx)
+	ctx, span := tracer.Start(ctx, "IssueServiceToken",
+		trace.WithAttributes(
+			attribute.String("resolver.name", "IssueServiceToken"),
+			attribute.String("token.audience", audience),
+		),
+		tracing.GetEnvironmentAttribute(),
+	)
+	defer span.End()
+
+	startTime := time.Now()
+	req := requestinfo.FromContext(ctx)
+
+	if req.UserID == nil {
+		metrics.GetAppMetrics().ResolverMetric(
+			float64(time.Since(startTime).Milliseconds()),
+			"IssueServiceToken",
+			metrics.Error,
+		)
+		return nil, fmt.Errorf("unauthorized")
+	}
+
+	span.SetAttributes(attribute.String("user.id", *req.UserID))
+
+	var ttl *time.Duration
+	if ttlSeconds != nil {
+		duration := time.Duration(*ttlSeconds) * time.Second
+		ttl = &duration
+	}
+
+	token, err := serviceTokens.IssueServiceToken(ctx, *req.UserID, audience, scopes, ttl)
+	if err != nil {
+		metrics.GetAppMetrics().ResolverMetric(
+			float64(time.Since(startTime).Milliseconds()),
+			"IssueServiceToken",
+			metrics.Error,
+		)
+		return nil, err
+	}
+
+	metrics.GetAppMetrics().ResolverMetric(
+		float64(time.Since(startTime).Milliseconds()),
+		"IssueServiceToken",
+		metrics.Success,
+	)
+
+	return &model.ServiceToken{
+		Token:     token.Token,
+		Audience:  token.Audience,
+		Scopes:    token.Scopes,
+		ExpiresAt: token.ExpiresAt,
+	}, nil
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/weeb-vip/user-service/graph/model"
 	"github.com/weeb-vip/user-service/http/handlers/requestinfo"
 	"github.com/weeb-vip/user-service/internal/services/image"
+	"github.com/weeb-vip/user-service/internal/services/moderation"
 	"github.com/weeb-vip/user-service/internal/services/users"
 	"github.com/weeb-vip/user-service/metrics"
 	"github.com/weeb-vip/user-service/tracing"
@@ -16,7 +17,13 @@ import (
 	"go.opentelemetry.io/otel/trace"
 )
 
-func UploadProfileImage(ctx context.Context, userService users.User, imageService *image.ImageService, upload graphql.Upload) (*model.User, error) {
+func UploadProfileImage(
+	ctx context.Context,
+	userService users.User,
+	imageService *image.ImageService,
+	moderationService moderation.Moderation,
+	upload graphql.Upload,
+) (*model.User, error) {
 	tracer := tracing.GetTracer(ctx)
 	ctx, span := tracer.Start(ctx, "UploadProfileImage",
 		trace.WithAttributes(
@@ -44,59 +51,46 @@ func UploadProfileImage(ctx context.Context, userService users.User, imageServic
 	userID := *req.UserID
 	span.SetAttributes(attribute.String("user.id", userID))
 
-	// Get current user to check for existing profile image
-	currentUser, err := userService.GetUserDetails(ctx, userID)
+	// Upload new image to MinIO
+	uploaded, err := imageService.UploadProfileImage(ctx, userID, upload)
 	if err != nil {
 		metrics.GetAppMetrics().ResolverMetric(
 			float64(time.Since(startTime).Milliseconds()),
 			"UploadProfileImage",
 			metrics.Error,
 		)
-		return nil, fmt.Errorf("failed to get user: %w", err)
+		return nil, fmt.Errorf("failed to upload image: %w", err)
 	}
 
-	// Store old image path for deletion after successful upload
-	oldImagePath := ""
-	if currentUser.ProfileImageURL != nil && *currentUser.ProfileImageURL != "" {
-		oldImagePath = *currentUser.ProfileImageURL
-	}
+	span.SetAttributes(attribute.String("image.path", uploaded.Path))
 
-	// Upload new image to MinIO
-	imagePath, err := imageService.UploadProfileImage(ctx, userID, upload)
+	// Queue the image for moderation; it only replaces the public avatar once approved
+	profileImage, err := moderationService.Submit(ctx, userID, uploaded)
 	if err != nil {
+		// Try to clean up the uploaded image if it could not be queued
+		_ = imageService.DeleteProfileImage(ctx, uploaded.Path)
 		metrics.GetAppMetrics().ResolverMetric(
 			float64(time.Since(startTime).Milliseconds()),
 			"UploadProfileImage",
 			metrics.Error,
 		)
-		return nil, fmt.Errorf("failed to upload image: %w", err)
+		return nil, fmt.Errorf("failed to submit profile image: %w", err)
 	}
 
-	span.SetAttributes(attribute.String("image.path", imagePath))
+	span.SetAttributes(attribute.String("moderation.status", profileImage.Status.String()))
 
-	// Update user profile with new image URL
-	updatedUser, err := userService.UpdateProfileImageURL(ctx, userID, imagePath)
+	updatedUser, err := userService.GetUserDetails(ctx, userID)
 	if err != nil {
-		// Try to clean up the uploaded image if database update fails
-		_ = imageService.DeleteProfileImage(ctx, imagePath)
 		metrics.GetAppMetrics().ResolverMetric(
 			float64(time.Since(startTime).Milliseconds()),
 			"UploadProfileImage",
 			metrics.Error,
 		)
-		return nil, fmt.Errorf("failed to update user profile: %w", err)
-	}
-
-	// Delete old image from storage after successful update (if it existed)
-	if oldImagePath != "" {
-		// Use goroutine to delete old image asynchronously to not block the response
-		go func() {
-			_ = imageService.DeleteProfileImage(context.Background(), oldImagePath)
-		}()
+		return nil, fmt.Errorf("failed to get user: %w", err)
 	}
 
 	// Convert to GraphQL model
-	language := model.Language(updatedUser.Language)
+	language := LanguageFromLocale(updatedUser.Language)
 
 	metrics.GetAppMetrics().ResolverMetric(
 		float64(time.Since(startTime).Milliseconds()),
@@ -113,4 +107,4 @@ func UploadProfileImage(ctx context.Context, userService users.User, imageServic
 		Email:           updatedUser.Email,
 		ProfileImageURL: updatedUser.ProfileImageURL,
 	}, nil
-}
\ No newline at end of file
+}
@@ -2,10 +2,12 @@ package resolvers
 
 import (
 	"context"
+	"strings"
 	"time"
 
 	"github.com/weeb-vip/user-service/graph/model"
 	"github.com/weeb-vip/user-service/http/handlers/requestinfo"
+	"github.com/weeb-vip/user-service/internal/locale"
 	"github.com/weeb-vip/user-service/internal/logger"
 	"github.com/weeb-vip/user-service/internal/services/users"
 	"github.com/weeb-vip/user-service/metrics"
@@ -54,7 +56,7 @@ func CreateUser( // nolint
 		return nil, nil
 	}
 	language := input.Language.String()
-	createdUser, err := userService.AddUser(ctx, input.ID, input.Username, input.Firstname, input.Lastname, language)
+	createdUser, err := userService.AddUser(ctx, input.ID, input.Username, input.Firstname, input.Lastname, language, input.Email)
 
 	if err != nil {
 		metrics.GetAppMetrics().ResolverMetric(
@@ -76,15 +78,19 @@ func CreateUser( // nolint
 	}, nil
 }
 
+// CreateUserFromEvent creates the user announced by a user-created event, or merges the event
+// into the existing user when it was already handled.
 func CreateUserFromEvent( // nolint
 	ctx context.Context,
 	userService users.User,
-	input *model.CreateUserInput,
+	userID string,
+	email *string,
+	language string,
 ) (*model.User, error) {
 	tracer := tracing.GetTracer(ctx)
 	ctx, span := tracer.Start(ctx, "CreateUserFromEvent",
 		trace.WithAttributes(
-			attribute.String("user.id", input.ID),
+			attribute.String("user.id", userID),
 			attribute.String("resolver.name", "CreateUserFromEvent"),
 			attribute.String("event.source", "kafka"),
 		),
@@ -94,8 +100,7 @@ func CreateUserFromEvent( // nolint
 
 	startTime := time.Now()
 
-	language := input.Language.String()
-	createdUser, err := userService.AddUser(ctx, input.ID, input.Username, input.Firstname, input.Lastname, language)
+	user, created, err := userService.EnsureUser(ctx, userID, email, language)
 
 	if err != nil {
 		metrics.GetAppMetrics().ResolverMetric(
@@ -106,6 +111,8 @@ func CreateUserFromEvent( // nolint
 		return nil, err
 	}
 
+	span.SetAttributes(attribute.Bool("user.created", created))
+
 	metrics.GetAppMetrics().ResolverMetric(
 		float64(time.Since(startTime).Milliseconds()),
 		"CreateUserFromEvent",
@@ -113,7 +120,7 @@ func CreateUserFromEvent( // nolint
 	)
 
 	return &model.User{
-		ID: createdUser.ID,
+		ID: user.ID,
 	}, nil
 }
 
@@ -160,8 +167,7 @@ func GetUser( // nolint
 		return nil, err
 	}
 
-	// convert user language to model.Language
-	language := model.Language(user.Language)
+	language := LanguageFromLocale(user.Language)
 
 	metrics.GetAppMetrics().ResolverMetric(
 		float64(time.Since(startTime).Milliseconds()),
@@ -176,6 +182,7 @@ func GetUser( // nolint
 		Username:        user.Username,
 		Language:        language,
 		Email:           user.Email,
+		EmailVerifiedAt: user.EmailVerifiedAt,
 		ProfileImageURL: user.ProfileImageURL,
 	}, nil
 }
@@ -223,7 +230,7 @@ func UpdateUser( // nolint
 
 	var userLanguage model.Language
 	if updatedUser.Language != "" {
-		userLanguage = model.Language(updatedUser.Language)
+		userLanguage = LanguageFromLocale(updatedUser.Language)
 	}
 
 	metrics.GetAppMetrics().ResolverMetric(
@@ -239,6 +246,13 @@ func UpdateUser( // nolint
 		Username:        updatedUser.Username,
 		Language:        userLanguage,
 		Email:           updatedUser.Email,
+		EmailVerifiedAt: updatedUser.EmailVerifiedAt,
 		ProfileImageURL: updatedUser.ProfileImageURL,
 	}, nil
 }
+
+// LanguageFromLocale maps a stored BCP-47 locale such as "pt-BR" to its GraphQL enum value PT_BR,
+// falling back to the closest supported locale for anything stored before validation existed.
+func LanguageFromLocale(value string) model.Language {
+	return model.Language(strings.ToUpper(strings.ReplaceAll(locale.Resolve(value), "-", "_")))
+}
@@ -0,0 +1,120 @@
+package resolvers
+
+import (
+	"context"
+	"time"
+
+	"github.com/weeb-vip/user-service/graph/model"
+	"github.com/weeb-vip/user-service/http/handlers/requestinfo"
+	"github.com/weeb-vip/user-service/internal/services/users"
+	"github.com/weeb-vip/user-service/metrics"
+	"github.com/weeb-vip/user-service/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func UserByUsername( // nolint
+	ctx context.Context,
+	userService users.User,
+	username string,
+) (*model.UsernameLookup, error) {
+	tracer := tracing.GetTracer(ctx)
+	ctx, span := tracer.Start(ctx, "UserByUsername",
+		trace.WithAttributes(
+			attribute.String("user.username", username),
+			attribute.String("resolver.name", "UserByUsername"),
+		),
+		tracing.GetEnvironmentAttribute(),
+	)
+	defer span.End()
+
+	startTime := time.Now()
+	req := requestinfo.FromContext(ctx)
+
+	user, redirected, err := userService.GetUserByUsername(ctx, username)
+	if err != nil {
+		metrics.GetAppMetrics().ResolverMetric(
+			float64(time.Since(startTime).Milliseconds()),
+			"UserByUsername",
+			metrics.Error,
+		)
+		return nil, err
+	}
+
+	metrics.GetAppMetrics().ResolverMetric(
+		float64(time.Since(startTime).Milliseconds()),
+		"UserByUsername",
+		metrics.Success,
+	)
+
+	if user == nil {
+		return nil, nil
+	}
+
+	result := &model.User{
+		ID:              user.ID,
+		Firstname:       user.FirstName,
+		Lastname:        user.LastName,
+		Username:        user.Username,
+		Language:        LanguageFromLocale(user.Language),
+		ProfileImageURL: user.ProfileImageURL,
+	}
+	// Email is private to its owner
+	if req.UserID != nil && *req.UserID == user.ID {
+		result.Email = user.Email
+		result.EmailVerifiedAt = user.EmailVerifiedAt
+	}
+
+	return &model.UsernameLookup{
+		User:       result,
+		Redirected: redirected,
+	}, nil
+}
+
+func UserByEmail( // nolint
+	ctx context.Context,
+	userService users.User,
+	email string,
+) (*model.User, error) {
+	tracer := tracing.GetTracer(ctx)
+	ctx, span := tracer.Start(ctx, "UserByEmail",
+		trace.WithAttributes(
+			attribute.String("resolver.name", "UserByEmail"),
+		),
+		tracing.GetEnvironmentAttribute(),
+	)
+	defer span.End()
+
+	startTime := time.Now()
+
+	user, err := userService.GetUserByEmail(ctx, email)
+	if err != nil {
+		metrics.GetAppMetrics().ResolverMetric(
+			float64(time.Since(startTime).Milliseconds()),
+			"UserByEmail",
+			metrics.Error,
+		)
+		return nil, err
+	}
+
+	metrics.GetAppMetrics().ResolverMetric(
+		float64(time.Since(startTime).Milliseconds()),
+		"UserByEmail",
+		metrics.Success,
+	)
+
+	if user == nil {
+		return nil, nil
+	}
+
+	return &model.User{
+		ID:              user.ID,
+		Firstname:       user.FirstName,
+		Lastname:        user.LastName,
+		Username:        user.Username,
+		Language:        LanguageFromLocale(user.Language),
+		Email:           user.Email,
+		EmailVerifiedAt: user.EmailVerifiedAt,
+		ProfileImageURL: user.ProfileImageURL,
+	}, nil
+}
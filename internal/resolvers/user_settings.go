@@ -0,0 +1,139 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/weeb-vip/user-service/graph/model"
+	"github.com/weeb-vip/user-service/http/handlers/requestinfo"
+	"github.com/weeb-vip/user-service/internal/services/guests"
+	guestModels "github.com/weeb-vip/user-service/internal/services/guests/models"
+	"github.com/weeb-vip/user-service/internal/services/settings"
+	"github.com/weeb-vip/user-service/internal/services/settings/models"
+	"github.com/weeb-vip/user-service/metrics"
+	"github.com/weeb-vip/user-service/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func UpdateSettings( // nolint
+	ctx context.Context,
+	settingsService settings.Settings,
+	guestService guests.Guests,
+	input *model.UpdateSettingsInput,
+) (*model.UserSettings, error) {
+	tracer := tracing.GetTracer(ctx)
+	ctx, span := tracer.Start(ctx, "UpdateSettings",
+		trace.WithAttributes(
+			attribute.String("resolver.name", "UpdateSettings"),
+		),
+		tracing.GetEnvironmentAttribute(),
+	)
+	defer span.End()
+
+	startTime := time.Now()
+	req := requestinfo.FromContext(ctx)
+
+	if req.UserID == nil {
+		metrics.GetAppMetrics().ResolverMetric(
+			float64(time.Since(startTime).Milliseconds()),
+			"UpdateSettings",
+			metrics.Error,
+		)
+		return nil, fmt.Errorf("unauthorized")
+	}
+
+	span.SetAttributes(attribute.String("user.id", *req.UserID))
+
+	var userSettings *models.UserSettings
+	var err error
+	if req.IsGuest() {
+		// Guest settings are not versioned, so expectedVersion does not apply
+		var profile *guestModels.GuestProfile
+		profile, err = guestService.UpdateProfile(ctx, *req.UserID, nil, settingsChanges(input))
+		if err == nil {
+			userSettings = guests.SettingsOf(profile)
+		}
+	} else {
+		userSettings, err = settingsService.UpdateSettings(ctx, *req.UserID, settingsChanges(input), input.ExpectedVersion)
+	}
+	if err != nil {
+		metrics.GetAppMetrics().ResolverMetric(
+			float64(time.Since(startTime).Milliseconds()),
+			"UpdateSettings",
+			metrics.Error,
+		)
+		return nil, err
+	}
+
+	metrics.GetAppMetrics().ResolverMetric(
+		float64(time.Since(startTime).Milliseconds()),
+		"UpdateSettings",
+		metrics.Success,
+	)
+
+	return toUserSettingsModel(userSettings), nil
+}
+
+// GetUserSettings resolves User.settings. Settings are only visible to their owner.
+func GetUserSettings( // nolint
+	ctx context.Context,
+	settingsService settings.Settings,
+	user *model.User,
+) (*model.UserSettings, error) {
+	req := requestinfo.FromContext(ctx)
+	if req.UserID == nil || *req.UserID != user.ID {
+		return nil, nil
+	}
+
+	userSettings, err := settingsService.GetSettings(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return toUserSettingsModel(userSettings), nil
+}
+
+// settingsChanges turns the fields set on the input into registry keys, so unset fields keep
+// whatever value is stored.
+func settingsChanges(input *model.UpdateSettingsInput) map[string]any {
+	changes := map[string]any{}
+	if input.TitleLanguage != nil {
+		changes[settings.KeyTitleLanguage] = strings.ToLower(input.TitleLanguage.String())
+	}
+	if input.ShowSpoilers != nil {
+		changes[settings.KeyShowSpoilers] = *input.ShowSpoilers
+	}
+	if input.ShowNsfw != nil {
+		changes[settings.KeyShowNSFW] = *input.ShowNsfw
+	}
+	if input.Theme != nil {
+		changes[settings.KeyTheme] = strings.ToLower(input.Theme.String())
+	}
+	if input.TimeZone != nil {
+		changes[settings.KeyTimeZone] = *input.TimeZone
+	}
+	if input.EmailNewsletter != nil {
+		changes[settings.KeyEmailNewsletter] = *input.EmailNewsletter
+	}
+	if input.EmailNotifications != nil {
+		changes[settings.KeyEmailNotifications] = *input.EmailNotifications
+	}
+
+	return changes
+}
+
+func toUserSettingsModel(userSettings *models.UserSettings) *model.UserSettings {
+	return &model.UserSettings{
+		Version:            userSettings.Version,
+		TitleLanguage:      model.TitleLanguage(strings.ToUpper(userSettings.String(settings.KeyTitleLanguage))),
+		ShowSpoilers:       userSettings.Bool(settings.KeyShowSpoilers),
+		ShowNsfw:           userSettings.Bool(settings.KeyShowNSFW),
+		Theme:              model.Theme(strings.ToUpper(userSettings.String(settings.KeyTheme))),
+		TimeZone:           userSettings.String(settings.KeyTimeZone),
+		EmailNewsletter:    userSettings.Bool(settings.KeyEmailNewsletter),
+		EmailNotifications: userSettings.Bool(settings.KeyEmailNotifications),
+	}
+}
@@ -0,0 +1,106 @@
+package resolvers
+
+import (
+	"context"
+	"time"
+
+	"github.com/weeb-vip/user-service/graph/model"
+	"github.com/weeb-vip/user-service/http/handlers/requestinfo"
+	"github.com/weeb-vip/user-service/internal/entities"
+	"github.com/weeb-vip/user-service/internal/ratelimit"
+	"github.com/weeb-vip/user-service/internal/services/users"
+	"github.com/weeb-vip/user-service/metrics"
+	"github.com/weeb-vip/user-service/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func UsernameAvailable( // nolint
+	ctx context.Context,
+	userService users.User,
+	limiter *ratelimit.Limiter,
+	username string,
+	firstname *string,
+	lastname *string,
+) (*model.UsernameAvailability, error) {
+	tracer := tracing.GetTracer(ctx)
+	ctx, span := tracer.Start(ctx, "UsernameAvailable",
+		trace.WithAttributes(
+			attribute.String("resolver.name", "UsernameAvailable"),
+		),
+		tracing.GetEnvironmentAttribute(),
+	)
+	defer span.End()
+
+	startTime := time.Now()
+	req := requestinfo.FromContext(ctx)
+
+	if !limiter.Allow(callerKey(req)) {
+		metrics.GetAppMetrics().ResolverMetric(
+			float64(time.Since(startTime).Milliseconds()),
+			"UsernameAvailable",
+			metrics.Error,
+		)
+		return nil, &entities.ServiceError{
+			Code:    "RATE_LIMITED",
+			Message: "too many username checks, try again shortly",
+		}
+	}
+
+	var first, last string
+	if firstname != nil {
+		first = *firstname
+	}
+	if lastname != nil {
+		last = *lastname
+	}
+
+	availability, err := userService.CheckUsername(ctx, username, first, last)
+	if err != nil {
+		metrics.GetAppMetrics().ResolverMetric(
+			float64(time.Since(startTime).Milliseconds()),
+			"UsernameAvailable",
+			metrics.Error,
+		)
+		return nil, err
+	}
+
+	span.SetAttributes(attribute.Bool("username.available", availability.Available))
+
+	metrics.GetAppMetrics().ResolverMetric(
+		float64(time.Since(startTime).Milliseconds()),
+		"UsernameAvailable",
+		metrics.Success,
+	)
+
+	result := &model.UsernameAvailability{
+		Username:    availability.Username,
+		Available:   availability.Available,
+		Message:     availability.Message,
+		Suggestions: availability.Suggestions,
+	}
+	if result.Suggestions == nil {
+		result.Suggestions = []string{}
+	}
+	if availability.Reason != nil {
+		reason := model.UsernameUnavailableReasonInvalid
+		if *availability.Reason == users.UserErrorUsernameTaken {
+			reason = model.UsernameUnavailableReasonTaken
+		}
+		result.Reason = &reason
+	}
+
+	return result, nil
+}
+
+// callerKey identifies the caller for rate limiting: the user when signed in, otherwise the client IP.
+func callerKey(req requestinfo.RequestInfo) string {
+	if req.UserID != nil {
+		return "user:" + *req.UserID
+	}
+	if req.RemoteIP != nil {
+		return "ip:" + *req.RemoteIP
+	}
+
+	return "anonymous"
+}
@@ -0,0 +1,78 @@
+package audit
+
+import (
+	"context"
+	"time"
+
+	"github.com/weeb-vip/user-service/internal/services/audit/models"
+	"github.com/weeb-vip/user-service/internal/services/audit/repositories"
+	"github.com/weeb-vip/user-service/metrics"
+	"github.com/weeb-vip/user-service/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+type auditLogService struct {
+	auditLogRepository repositories.AuditLogRepository
+}
+
+func NewAuditLogService(auditLogRepository repositories.AuditLogRepository) AuditLog {
+	return &auditLogService{
+		auditLogRepository: auditLogRepository,
+	}
+}
+
+func (service *auditLogService) ListEntries(
+	ctx context.Context,
+	userID *string,
+	limit int,
+	offset int,
+) ([]*models.Entry, error) {
+	tracer := tracing.GetTracer(ctx)
+	ctx, span := tracer.Start(ctx, "service.ListAuditEntries",
+		trace.WithAttributes(
+			attribute.Int("limit", limit),
+			attribute.Int("offset", offset),
+			attribute.String("service", "audit"),
+			attribute.String("method", "ListEntries"),
+		),
+		tracing.GetEnvironmentAttribute(),
+	)
+	defer span.End()
+
+	startTime := time.Now()
+
+	if limit <= 0 {
+		limit = defaultPageSize
+	}
+	if limit > maxPageSize {
+		limit = maxPageSize
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	entries, err := service.auditLogRepository.ListEntries(ctx, userID, limit, offset)
+	if err != nil {
+		service.recordMetric(startTime, "ListEntries", metrics.Error)
+		return nil, &Error{Code: AuditErrorInternalError, Message: "database error"}
+	}
+
+	service.recordMetric(startTime, "ListEntries", metrics.Success)
+
+	return entries, nil
+}
+
+func (service *auditLogService) recordMetric(startTime time.Time, method string, result string) {
+	metrics.GetAppMetrics().ServiceMetric(
+		float64(time.Since(startTime).Milliseconds()),
+		"audit",
+		method,
+		result,
+	)
+}
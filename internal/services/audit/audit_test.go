@@ -0,0 +1,50 @@
+package audit_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/weeb-vip/user-service/internal/services/audit"
+	"github.com/weeb-vip/user-service/internal/services/audit/models"
+	"github.com/weeb-vip/user-service/mocks"
+	"go.uber.org/mock/gomock"
+)
+
+func ptr(value string) *string {
+	return &value
+}
+
+func TestAuditLogService_ListEntries(t *testing.T) {
+	t.Run("clamps the page", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		repository := mocks.NewMockAuditLogRepository(ctrl)
+		userID := "user1"
+		repository.EXPECT().ListEntries(gomock.Any(), &userID, 100, 0).Return([]*models.Entry{}, nil)
+
+		_, err := audit.NewAuditLogService(repository).ListEntries(context.Background(), &userID, 1000, -5)
+		require.NoError(t, err)
+	})
+
+	t.Run("defaults the page size", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		repository := mocks.NewMockAuditLogRepository(ctrl)
+		repository.EXPECT().ListEntries(gomock.Any(), nil, 20, 40).Return([]*models.Entry{}, nil)
+
+		_, err := audit.NewAuditLogService(repository).ListEntries(context.Background(), nil, 0, 40)
+		require.NoError(t, err)
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		repository := mocks.NewMockAuditLogRepository(ctrl)
+		repository.EXPECT().ListEntries(gomock.Any(), nil, 20, 0).Return(nil, errors.New("connection refused"))
+
+		_, err := audit.NewAuditLogService(repository).ListEntries(context.Background(), nil, 0, 0)
+		var auditErr *audit.Error
+		require.True(t, errors.As(err, &auditErr), "expected audit error, got %v", err)
+		assert.Equal(t, audit.AuditErrorInternalError, auditErr.Code)
+	})
+}
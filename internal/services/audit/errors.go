@@ -0,0 +1,20 @@
+package audit
+
+const (
+	AuditErrorInternalError ErrorCode = "INTERNAL_ERROR" // nolint
+)
+
+type ErrorCode string
+
+type Error struct {
+	Code    ErrorCode
+	Message string
+}
+
+func (c ErrorCode) String() string {
+	return string(c)
+}
+
+func (e Error) Error() string {
+	return e.Message
+}
@@ -0,0 +1,13 @@
+package audit
+
+import (
+	"context"
+
+	"github.com/weeb-vip/user-service/internal/services/audit/models"
+)
+
+type AuditLog interface {
+	// ListEntries returns profile changes newest first, only those about userID when it is not
+	// nil. limit is clamped to a page size the service can serve.
+	ListEntries(ctx context.Context, userID *string, limit int, offset int) ([]*models.Entry, error)
+}
@@ -0,0 +1,33 @@
+package models
+
+import (
+	"context"
+)
+
+// SystemActorID is recorded as the actor of changes made without a caller, such as those
+// triggered by events from other services.
+const SystemActorID = "system"
+
+// Actor is who made a change and where their request came from.
+type Actor struct {
+	ID        string
+	IP        *string
+	UserAgent *string
+}
+
+type actorKey struct{}
+
+// WithActor makes actor the author of profile changes made with the returned context.
+func WithActor(ctx context.Context, actor Actor) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFromContext returns the actor set by WithActor, or the system actor.
+func ActorFromContext(ctx context.Context) Actor {
+	actor, ok := ctx.Value(actorKey{}).(Actor)
+	if !ok || actor.ID == "" {
+		return Actor{ID: SystemActorID}
+	}
+
+	return actor
+}
@@ -0,0 +1,104 @@
+package models
+
+import (
+	"context"
+	"strings"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	ActionUpdateUser         = "update_user"
+	ActionUpdateProfileImage = "update_profile_image"
+)
+
+const (
+	FieldUsername        = "username"
+	FieldFirstName       = "first_name"
+	FieldLastName        = "last_name"
+	FieldLanguage        = "language"
+	FieldEmail           = "email"
+	FieldProfileImageURL = "profile_image_url"
+)
+
+// sensitiveFields are masked before they are written, so the log never holds their values.
+var sensitiveFields = map[string]bool{
+	FieldEmail: true,
+}
+
+// Diff appends a change of field to changes when before and after differ.
+func Diff(changes Changes, field string, before *string, after *string) Changes {
+	if equal(before, after) {
+		return changes
+	}
+
+	// Copies, so the entry keeps these values when the caller goes on to change the originals
+	change := FieldChange{Field: field, Before: copyOf(before), After: copyOf(after)}
+	if sensitiveFields[field] {
+		change.Before = redact(before)
+		change.After = redact(after)
+		change.Redacted = true
+	}
+
+	return append(changes, change)
+}
+
+// NewEntry returns an entry for changes to userID made by the actor of ctx, tagged with the
+// current trace.
+func NewEntry(ctx context.Context, userID string, action string, changes Changes) *Entry {
+	actor := ActorFromContext(ctx)
+	entry := &Entry{
+		UserID:    userID,
+		ActorID:   actor.ID,
+		Action:    action,
+		Changes:   changes,
+		IP:        actor.IP,
+		UserAgent: actor.UserAgent,
+	}
+
+	if spanContext := trace.SpanContextFromContext(ctx); spanContext.HasTraceID() {
+		traceID := spanContext.TraceID().String()
+		entry.TraceID = &traceID
+	}
+
+	return entry
+}
+
+func copyOf(value *string) *string {
+	if value == nil {
+		return nil
+	}
+
+	copied := *value
+
+	return &copied
+}
+
+func equal(a *string, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	return *a == *b
+}
+
+// redact keeps just enough of value to tell changes apart: the first character and, for email
+// addresses, the domain.
+func redact(value *string) *string {
+	if value == nil {
+		return nil
+	}
+
+	masked := "***"
+	if local, domain, found := strings.Cut(*value, "@"); found && local != "" {
+		masked = firstRune(local) + "***@" + domain
+	} else if *value != "" {
+		masked = firstRune(*value) + "***"
+	}
+
+	return &masked
+}
+
+func firstRune(value string) string {
+	return string([]rune(value)[:1])
+}
@@ -0,0 +1,106 @@
+package models_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/weeb-vip/user-service/internal/services/audit/models"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func ptr(value string) *string {
+	return &value
+}
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name   string
+		field  string
+		before *string
+		after  *string
+		want   models.Changes
+	}{
+		{name: "unchanged", field: models.FieldFirstName, before: ptr("Jane"), after: ptr("Jane")},
+		{name: "unchanged unset", field: models.FieldProfileImageURL},
+		{
+			name:   "changed",
+			field:  models.FieldFirstName,
+			before: ptr("Jane"),
+			after:  ptr("Janet"),
+			want:   models.Changes{{Field: models.FieldFirstName, Before: ptr("Jane"), After: ptr("Janet")}},
+		},
+		{
+			name:  "set",
+			field: models.FieldProfileImageURL,
+			after: ptr("images/a.png"),
+			want:  models.Changes{{Field: models.FieldProfileImageURL, After: ptr("images/a.png")}},
+		},
+		{
+			name:   "email is redacted",
+			field:  models.FieldEmail,
+			before: ptr("jane@example.com"),
+			after:  ptr("ünal@example.org"),
+			want: models.Changes{{
+				Field:    models.FieldEmail,
+				Before:   ptr("j***@example.com"),
+				After:    ptr("ü***@example.org"),
+				Redacted: true,
+			}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, models.Diff(nil, tt.field, tt.before, tt.after))
+		})
+	}
+}
+
+func TestDiff_KeepsValuesWhenOriginalsChange(t *testing.T) {
+	name := "Jane"
+	changes := models.Diff(nil, models.FieldFirstName, &name, ptr("Janet"))
+	name = "Janet"
+
+	assert.Equal(t, "Jane", *changes[0].Before)
+}
+
+func TestNewEntry(t *testing.T) {
+	changes := models.Changes{{Field: models.FieldFirstName, Before: ptr("Jane"), After: ptr("Janet")}}
+
+	t.Run("without an actor the system made the change", func(t *testing.T) {
+		entry := models.NewEntry(context.Background(), "user1", models.ActionUpdateUser, changes)
+		assert.Equal(t, models.SystemActorID, entry.ActorID)
+		assert.Nil(t, entry.IP)
+		assert.Nil(t, entry.TraceID)
+	})
+
+	t.Run("records the actor and trace", func(t *testing.T) {
+		traceID := trace.TraceID{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}
+		ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+			TraceID: traceID,
+			SpanID:  trace.SpanID{1},
+		}))
+		ctx = models.WithActor(ctx, models.Actor{ID: "mod1", IP: ptr("10.0.0.1"), UserAgent: ptr("curl/8")})
+
+		entry := models.NewEntry(ctx, "user1", models.ActionUpdateProfileImage, changes)
+		assert.Equal(t, "user1", entry.UserID)
+		assert.Equal(t, "mod1", entry.ActorID)
+		assert.Equal(t, models.ActionUpdateProfileImage, entry.Action)
+		assert.Equal(t, "10.0.0.1", *entry.IP)
+		assert.Equal(t, "curl/8", *entry.UserAgent)
+		assert.Equal(t, traceID.String(), *entry.TraceID)
+	})
+}
+
+func TestChanges_RoundTrip(t *testing.T) {
+	changes := models.Changes{{Field: models.FieldEmail, After: ptr("j***@example.com"), Redacted: true}}
+
+	value, err := changes.Value()
+	require.NoError(t, err)
+
+	var scanned models.Changes
+	require.NoError(t, scanned.Scan(value))
+	assert.Equal(t, changes, scanned)
+}
@@ -0,0 +1,74 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+
+	"github.com/weeb-vip/user-service/internal/db"
+)
+
+// Entry records one change to a user's profile. Entries are only ever appended.
+type Entry struct {
+	db.BaseModel
+	UserID  string  `json:"user_id"`
+	ActorID string  `json:"actor_id"`
+	Action  string  `json:"action"`
+	Changes Changes `json:"changes"`
+	// IP and UserAgent are those of the actor's request, nil for changes the service made itself.
+	IP        *string `json:"ip"`
+	UserAgent *string `json:"user_agent"`
+	TraceID   *string `json:"trace_id"`
+}
+
+func (Entry) TableName() string {
+	return "profile_audit_log"
+}
+
+// FieldChange is the value of one field before and after a change. Nil means the field was unset.
+type FieldChange struct {
+	Field  string  `json:"field"`
+	Before *string `json:"before"`
+	After  *string `json:"after"`
+	// Redacted is set when Before and After are masked because the field is sensitive.
+	Redacted bool `json:"redacted,omitempty"`
+}
+
+// Changes holds the changed fields of an entry, stored as a JSON document.
+type Changes []FieldChange
+
+func (c Changes) Value() (driver.Value, error) {
+	if c == nil {
+		return "[]", nil
+	}
+
+	data, err := json.Marshal(c)
+	if err != nil {
+		return nil, err
+	}
+
+	return string(data), nil
+}
+
+func (c *Changes) Scan(src any) error {
+	var data []byte
+	switch value := src.(type) {
+	case nil:
+		*c = Changes{}
+		return nil
+	case []byte:
+		data = value
+	case string:
+		data = []byte(value)
+	default:
+		return fmt.Errorf("unsupported changes column type %T", src)
+	}
+
+	changes := Changes{}
+	if err := json.Unmarshal(data, &changes); err != nil {
+		return err
+	}
+	*c = changes
+
+	return nil
+}
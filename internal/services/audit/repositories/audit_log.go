@@ -0,0 +1,95 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/weeb-vip/user-service/internal/db"
+	"github.com/weeb-vip/user-service/internal/services/audit/models"
+	"github.com/weeb-vip/user-service/metrics"
+	"github.com/weeb-vip/user-service/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// AuditLogRepository only reads the log. Entries are written by the users repository in the same
+// transaction as the change they record.
+type AuditLogRepository interface {
+	// ListEntries returns entries newest first, only those about userID when it is not nil.
+	ListEntries(ctx context.Context, userID *string, limit int, offset int) ([]*models.Entry, error)
+}
+
+type auditLogRepository struct {
+	DBService db.DB
+}
+
+var auditLogRepositorySingleton AuditLogRepository // nolint
+
+func NewAuditLogRepository() AuditLogRepository {
+	dbService := db.GetDBService()
+
+	return &auditLogRepository{
+		DBService: dbService,
+	}
+}
+
+func (repository *auditLogRepository) ListEntries(
+	ctx context.Context,
+	userID *string,
+	limit int,
+	offset int,
+) ([]*models.Entry, error) {
+	tracer := tracing.GetTracer(ctx)
+	ctx, span := tracer.Start(ctx, "repository.ListAuditEntries",
+		trace.WithAttributes(
+			attribute.String("table", "profile_audit_log"),
+			attribute.String("operation", "select"),
+		),
+		tracing.GetEnvironmentAttribute(),
+	)
+	defer span.End()
+
+	if userID != nil {
+		span.SetAttributes(attribute.String("user.id", *userID))
+	}
+
+	start := time.Now()
+	database := repository.DBService.GetDB()
+
+	query := database.WithContext(ctx)
+	if userID != nil {
+		query = query.Where("user_id = ?", *userID)
+	}
+
+	var entries []*models.Entry
+	// IDs are ULIDs, so they break ties between entries written in the same second
+	err := query.
+		Order("created_at DESC").
+		Order("id DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&entries).Error
+
+	// Record database metrics
+	duration := float64(time.Since(start).Nanoseconds()) / float64(time.Millisecond)
+	appMetrics := metrics.GetAppMetrics()
+	result := metrics.Success
+	if err != nil {
+		result = metrics.Error
+	}
+	appMetrics.DatabaseMetric(duration, "profile_audit_log", "select", result)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+func GetAuditLogRepository() AuditLogRepository {
+	if auditLogRepositorySingleton == nil {
+		auditLogRepositorySingleton = NewAuditLogRepository()
+	}
+
+	return auditLogRepositorySingleton
+}
@@ -0,0 +1,28 @@
+package guests
+
+const (
+	GuestsErrorInternalError       ErrorCode = "INTERNAL_ERROR"             // nolint
+	GuestsErrorNotAllowed          ErrorCode = "GUEST_NOT_ALLOWED"          // nolint
+	GuestsErrorInvalidID           ErrorCode = "GUEST_ID_INVALID"           // nolint
+	GuestsErrorUnsupportedLanguage ErrorCode = "GUEST_UNSUPPORTED_LANGUAGE" // nolint
+)
+
+type ErrorCode string
+
+type Error struct {
+	Code    ErrorCode
+	Message string
+}
+
+func (c ErrorCode) String() string {
+	return string(c)
+}
+
+func (e Error) Error() string {
+	return e.Message
+}
+
+// NotAllowed is returned for anything that needs a registered account.
+func NotAllowed() error {
+	return &Error{Code: GuestsErrorNotAllowed, Message: "sign up to use this"}
+}
@@ -0,0 +1,271 @@
+package guests
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/weeb-vip/user-service/config"
+	"github.com/weeb-vip/user-service/internal/events"
+	"github.com/weeb-vip/user-service/internal/locale"
+	"github.com/weeb-vip/user-service/internal/services/guests/models"
+	"github.com/weeb-vip/user-service/internal/services/guests/repositories"
+	"github.com/weeb-vip/user-service/internal/services/settings"
+	settingsModels "github.com/weeb-vip/user-service/internal/services/settings/models"
+	"github.com/weeb-vip/user-service/internal/services/users"
+	"github.com/weeb-vip/user-service/metrics"
+	"github.com/weeb-vip/user-service/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// guestIDPrefix marks IDs the auth service issues to guests, see requestinfo.UserTypeGuest.
+const guestIDPrefix = "guest_"
+
+type guestService struct {
+	guestProfilesRepository repositories.GuestProfilesRepository
+	settingsService         settings.Settings
+	usersService            users.User
+	publisher               events.Publisher
+	profileTTL              time.Duration
+}
+
+func NewGuestService(
+	guestProfilesRepository repositories.GuestProfilesRepository,
+	settingsService settings.Settings,
+	usersService users.User,
+	publisher events.Publisher,
+	cfg config.GuestConfig,
+) Guests {
+	return &guestService{
+		guestProfilesRepository: guestProfilesRepository,
+		settingsService:         settingsService,
+		usersService:            usersService,
+		publisher:               publisher,
+		profileTTL:              time.Duration(cfg.ProfileTTLHours) * time.Hour,
+	}
+}
+
+func (service *guestService) GetProfile(ctx context.Context, guestID string) (*models.GuestProfile, error) {
+	tracer := tracing.GetTracer(ctx)
+	ctx, span := tracer.Start(ctx, "service.GetGuestProfile",
+		trace.WithAttributes(
+			attribute.String("guest.id", guestID),
+			attribute.String("service", "guests"),
+			attribute.String("method", "GetProfile"),
+		),
+		tracing.GetEnvironmentAttribute(),
+	)
+	defer span.End()
+
+	startTime := time.Now()
+
+	profile, err := service.getProfile(ctx, guestID)
+	if err != nil {
+		service.recordMetric(startTime, "GetProfile", metrics.Error)
+		return nil, err
+	}
+
+	service.recordMetric(startTime, "GetProfile", metrics.Success)
+
+	return profile, nil
+}
+
+func (service *guestService) UpdateProfile(
+	ctx context.Context,
+	guestID string,
+	language *string,
+	changes map[string]any,
+) (*models.GuestProfile, error) {
+	tracer := tracing.GetTracer(ctx)
+	ctx, span := tracer.Start(ctx, "service.UpdateGuestProfile",
+		trace.WithAttributes(
+			attribute.String("guest.id", guestID),
+			attribute.Int("settings.changes", len(changes)),
+			attribute.String("service", "guests"),
+			attribute.String("method", "UpdateProfile"),
+		),
+		tracing.GetEnvironmentAttribute(),
+	)
+	defer span.End()
+
+	startTime := time.Now()
+
+	if language != nil {
+		normalized, err := locale.Normalize(*language)
+		if err != nil {
+			service.recordMetric(startTime, "UpdateProfile", metrics.Error)
+			return nil, &Error{
+				Code:    GuestsErrorUnsupportedLanguage,
+				Message: "unsupported language, expected one of " + strings.Join(locale.Supported, ", "),
+			}
+		}
+		language = &normalized
+	}
+
+	if err := settings.Validate(changes); err != nil {
+		service.recordMetric(startTime, "UpdateProfile", metrics.Error)
+		return nil, err
+	}
+
+	profile, err := service.getProfile(ctx, guestID)
+	if err != nil {
+		service.recordMetric(startTime, "UpdateProfile", metrics.Error)
+		return nil, err
+	}
+
+	if language != nil {
+		profile.Language = *language
+	}
+	for key, value := range changes {
+		profile.Settings[key] = value
+	}
+	now := time.Now()
+	profile.ExpiresAt = now.Add(service.profileTTL)
+	profile.UpdatedAt = now
+	if profile.CreatedAt.IsZero() {
+		profile.CreatedAt = now
+	}
+
+	if err := service.guestProfilesRepository.SaveGuestProfile(ctx, profile); err != nil {
+		service.recordMetric(startTime, "UpdateProfile", metrics.Error)
+		return nil, &Error{Code: GuestsErrorInternalError, Message: "database error"}
+	}
+
+	service.recordMetric(startTime, "UpdateProfile", metrics.Success)
+
+	return profile, nil
+}
+
+func (service *guestService) UpgradeGuest(ctx context.Context, guestID string, userID string) (*settingsModels.UserSettings, error) {
+	tracer := tracing.GetTracer(ctx)
+	ctx, span := tracer.Start(ctx, "service.UpgradeGuest",
+		trace.WithAttributes(
+			attribute.String("guest.id", guestID),
+			attribute.String("user.id", userID),
+			attribute.String("service", "guests"),
+			attribute.String("method", "UpgradeGuest"),
+		),
+		tracing.GetEnvironmentAttribute(),
+	)
+	defer span.End()
+
+	startTime := time.Now()
+
+	if !strings.HasPrefix(guestID, guestIDPrefix) || strings.HasPrefix(userID, guestIDPrefix) {
+		service.recordMetric(startTime, "UpgradeGuest", metrics.Error)
+		return nil, &Error{Code: GuestsErrorInvalidID, Message: "expected a guest ID and a registered user ID"}
+	}
+
+	stored, err := service.guestProfilesRepository.GetGuestProfile(ctx, guestID)
+	if err != nil {
+		service.recordMetric(startTime, "UpgradeGuest", metrics.Error)
+		return nil, &Error{Code: GuestsErrorInternalError, Message: "database error"}
+	}
+
+	upgraded := events.GuestUpgraded{GuestID: guestID, UserID: userID, Settings: []string{}}
+	changes := map[string]any{}
+	if stored != nil {
+		upgraded.Language = stored.Language
+		for key, value := range stored.Settings {
+			// Skip values that a newer registry no longer accepts rather than failing the signup
+			if settings.Validate(map[string]any{key: value}) == nil {
+				changes[key] = value
+				upgraded.Settings = append(upgraded.Settings, key)
+			}
+		}
+		sort.Strings(upgraded.Settings)
+	}
+
+	// Merging first keeps a retry after a failed publish or delete safe, since applying the same
+	// changes again leaves the user's settings as they were
+	userSettings, err := service.settingsService.UpdateSettings(ctx, userID, changes, nil)
+	if err != nil {
+		service.recordMetric(startTime, "UpgradeGuest", metrics.Error)
+		return nil, err
+	}
+
+	if stored != nil {
+		if err := service.applyLanguage(ctx, userID, stored.Language); err != nil {
+			service.recordMetric(startTime, "UpgradeGuest", metrics.Error)
+			return nil, err
+		}
+	}
+
+	if err := service.publisher.Publish(ctx, userID, upgraded); err != nil {
+		service.recordMetric(startTime, "UpgradeGuest", metrics.Error)
+		return nil, &Error{Code: GuestsErrorInternalError, Message: "failed to publish event"}
+	}
+
+	if stored != nil {
+		if err := service.guestProfilesRepository.DeleteGuestProfile(ctx, guestID); err != nil {
+			service.recordMetric(startTime, "UpgradeGuest", metrics.Error)
+			return nil, &Error{Code: GuestsErrorInternalError, Message: "database error"}
+		}
+	}
+
+	service.recordMetric(startTime, "UpgradeGuest", metrics.Success)
+
+	return userSettings, nil
+}
+
+// applyLanguage gives the user the guest's language unless they already picked one. Users start
+// on locale.Default, so a user still on it is taken to have no preference.
+func (service *guestService) applyLanguage(ctx context.Context, userID string, language string) error {
+	if language == "" || language == locale.Default {
+		return nil
+	}
+
+	user, err := service.usersService.GetUserDetails(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user.Language != "" && user.Language != locale.Default {
+		return nil
+	}
+
+	_, err = service.usersService.UpdateUser(ctx, userID, nil, nil, nil, &language, nil)
+
+	return err
+}
+
+// getProfile returns the stored profile or a default one, which is not saved until it changes.
+func (service *guestService) getProfile(ctx context.Context, guestID string) (*models.GuestProfile, error) {
+	if !strings.HasPrefix(guestID, guestIDPrefix) {
+		return nil, &Error{Code: GuestsErrorInvalidID, Message: "not a guest ID"}
+	}
+
+	profile, err := service.guestProfilesRepository.GetGuestProfile(ctx, guestID)
+	if err != nil {
+		return nil, &Error{Code: GuestsErrorInternalError, Message: "database error"}
+	}
+	if profile == nil {
+		profile = &models.GuestProfile{GuestID: guestID, Language: locale.Default}
+	}
+	if profile.Settings == nil {
+		profile.Settings = settingsModels.Values{}
+	}
+
+	return profile, nil
+}
+
+func (service *guestService) recordMetric(startTime time.Time, method string, result string) {
+	metrics.GetAppMetrics().ServiceMetric(
+		float64(time.Since(startTime).Milliseconds()),
+		"guests",
+		method,
+		result,
+	)
+}
+
+// SettingsOf returns the guest's settings with defaults filled in, in the same shape as a
+// registered user's. Guest settings are not versioned, so Version is always 0.
+func SettingsOf(profile *models.GuestProfile) *settingsModels.UserSettings {
+	return settings.WithDefaults(profile.GuestID, &settingsModels.UserSettings{
+		UserID:    profile.GuestID,
+		Values:    profile.Settings,
+		CreatedAt: profile.CreatedAt,
+		UpdatedAt: profile.UpdatedAt,
+	})
+}
@@ -0,0 +1,238 @@
+package guests
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/weeb-vip/user-service/config"
+	"github.com/weeb-vip/user-service/internal/events"
+	"github.com/weeb-vip/user-service/internal/locale"
+	"github.com/weeb-vip/user-service/internal/services/guests/models"
+	"github.com/weeb-vip/user-service/internal/services/settings"
+	settingsModels "github.com/weeb-vip/user-service/internal/services/settings/models"
+	"github.com/weeb-vip/user-service/internal/services/users"
+	usersModels "github.com/weeb-vip/user-service/internal/services/users/models"
+	"github.com/weeb-vip/user-service/mocks"
+	"go.uber.org/mock/gomock"
+)
+
+const guestID = "guest_01J9A3K5W2R7M8N4P6Q0S1T2V3"
+
+type recordingPublisher struct {
+	published []events.Event
+	err       error
+}
+
+func (p *recordingPublisher) Publish(_ context.Context, _ string, event events.Event) error {
+	if p.err != nil {
+		return p.err
+	}
+	p.published = append(p.published, event)
+
+	return nil
+}
+
+// fakeUsers stands in for the users service, which is mocked with a different gomock.
+type fakeUsers struct {
+	users.User
+	user      usersModels.User
+	languages []string
+}
+
+func (f *fakeUsers) GetUserDetails(_ context.Context, _ string) (*usersModels.User, error) {
+	user := f.user
+
+	return &user, nil
+}
+
+func (f *fakeUsers) UpdateUser(
+	_ context.Context,
+	_ string,
+	_ *string,
+	_ *string,
+	_ *string,
+	language *string,
+	_ *string,
+) (*usersModels.User, error) {
+	f.languages = append(f.languages, *language)
+	f.user.Language = *language
+	user := f.user
+
+	return &user, nil
+}
+
+func newGuestService(ctrl *gomock.Controller) (
+	Guests,
+	*mocks.MockGuestProfilesRepository,
+	*mocks.MockSettings,
+	*recordingPublisher,
+	*fakeUsers,
+) {
+	repository := mocks.NewMockGuestProfilesRepository(ctrl)
+	settingsService := mocks.NewMockSettings(ctrl)
+	publisher := &recordingPublisher{}
+	usersService := &fakeUsers{user: usersModels.User{Language: locale.Default}}
+
+	return NewGuestService(repository, settingsService, usersService, publisher, config.GuestConfig{ProfileTTLHours: 24}),
+		repository, settingsService, publisher, usersService
+}
+
+func TestGuestService_GetProfile(t *testing.T) {
+	t.Run("defaults when nothing is stored", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		service, repository, _, _, _ := newGuestService(ctrl)
+		repository.EXPECT().GetGuestProfile(gomock.Any(), guestID).Return(nil, nil)
+
+		profile, err := service.GetProfile(context.Background(), guestID)
+		require.NoError(t, err)
+		assert.Equal(t, "en", profile.Language)
+		assert.True(t, profile.ExpiresAt.IsZero())
+
+		guestSettings := SettingsOf(profile)
+		assert.Equal(t, guestID, guestSettings.UserID)
+		assert.Equal(t, "romaji", guestSettings.String(settings.KeyTitleLanguage))
+	})
+
+	t.Run("refuses registered users", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		service, _, _, _, _ := newGuestService(ctrl)
+
+		_, err := service.GetProfile(context.Background(), "user_01J9A3M1X6Y8Z0B2C4D6F8G0H2")
+		assertErrorCode(t, err, GuestsErrorInvalidID)
+	})
+}
+
+func TestGuestService_UpdateProfile(t *testing.T) {
+	t.Run("merges changes and pushes the expiry back", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		service, repository, _, _, _ := newGuestService(ctrl)
+		repository.EXPECT().GetGuestProfile(gomock.Any(), guestID).Return(&models.GuestProfile{
+			GuestID:   guestID,
+			Language:  "en",
+			Settings:  settingsModels.Values{settings.KeyTheme: "dark"},
+			ExpiresAt: time.Now().Add(time.Hour),
+		}, nil)
+		repository.EXPECT().SaveGuestProfile(gomock.Any(), gomock.Any()).Return(nil)
+
+		language := "pt_br"
+		profile, err := service.UpdateProfile(context.Background(), guestID, &language, map[string]any{
+			settings.KeyShowSpoilers: true,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "pt-BR", profile.Language)
+		assert.Equal(t, settingsModels.Values{settings.KeyTheme: "dark", settings.KeyShowSpoilers: true}, profile.Settings)
+		assert.WithinDuration(t, time.Now().Add(24*time.Hour), profile.ExpiresAt, time.Minute)
+	})
+
+	t.Run("rejects invalid settings", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		service, _, _, _, _ := newGuestService(ctrl)
+
+		_, err := service.UpdateProfile(context.Background(), guestID, nil, map[string]any{settings.KeyTheme: "neon"})
+		var settingsErr *settings.Error
+		require.True(t, errors.As(err, &settingsErr), "expected settings error, got %v", err)
+		assert.Equal(t, settings.SettingsErrorInvalid, settingsErr.Code)
+	})
+
+	t.Run("rejects unsupported languages", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		service, _, _, _, _ := newGuestService(ctrl)
+
+		language := "klingon"
+		_, err := service.UpdateProfile(context.Background(), guestID, &language, nil)
+		assertErrorCode(t, err, GuestsErrorUnsupportedLanguage)
+	})
+}
+
+func TestGuestService_UpgradeGuest(t *testing.T) {
+	userID := "user_01J9A3M1X6Y8Z0B2C4D6F8G0H2"
+
+	t.Run("carries valid settings over and drops the profile", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		service, repository, settingsService, publisher, usersService := newGuestService(ctrl)
+		repository.EXPECT().GetGuestProfile(gomock.Any(), guestID).Return(&models.GuestProfile{
+			GuestID:  guestID,
+			Language: "ja",
+			Settings: settingsModels.Values{
+				settings.KeyTheme:         "dark",
+				settings.KeyTitleLanguage: "klingon",
+			},
+		}, nil)
+		merged := &settingsModels.UserSettings{UserID: userID, Version: 1}
+		settingsService.EXPECT().
+			UpdateSettings(gomock.Any(), userID, map[string]any{settings.KeyTheme: "dark"}, nil).
+			Return(merged, nil)
+		repository.EXPECT().DeleteGuestProfile(gomock.Any(), guestID).Return(nil)
+
+		result, err := service.UpgradeGuest(context.Background(), guestID, userID)
+		require.NoError(t, err)
+		assert.Equal(t, merged, result)
+		assert.Equal(t, []events.Event{events.GuestUpgraded{
+			GuestID:  guestID,
+			UserID:   userID,
+			Language: "ja",
+			Settings: []string{settings.KeyTheme},
+		}}, publisher.published)
+		assert.Equal(t, []string{"ja"}, usersService.languages)
+	})
+
+	t.Run("keeps a language the user picked", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		service, repository, settingsService, _, usersService := newGuestService(ctrl)
+		usersService.user.Language = "th"
+		repository.EXPECT().GetGuestProfile(gomock.Any(), guestID).
+			Return(&models.GuestProfile{GuestID: guestID, Language: "ja"}, nil)
+		settingsService.EXPECT().UpdateSettings(gomock.Any(), userID, map[string]any{}, nil).
+			Return(&settingsModels.UserSettings{UserID: userID}, nil)
+		repository.EXPECT().DeleteGuestProfile(gomock.Any(), guestID).Return(nil)
+
+		_, err := service.UpgradeGuest(context.Background(), guestID, userID)
+		require.NoError(t, err)
+		assert.Empty(t, usersService.languages)
+		assert.Equal(t, "th", usersService.user.Language)
+	})
+
+	t.Run("publishes without a profile", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		service, repository, settingsService, publisher, _ := newGuestService(ctrl)
+		repository.EXPECT().GetGuestProfile(gomock.Any(), guestID).Return(nil, nil)
+		settingsService.EXPECT().UpdateSettings(gomock.Any(), userID, map[string]any{}, nil).
+			Return(&settingsModels.UserSettings{UserID: userID}, nil)
+
+		_, err := service.UpgradeGuest(context.Background(), guestID, userID)
+		require.NoError(t, err)
+		assert.Len(t, publisher.published, 1)
+	})
+
+	t.Run("keeps the profile when publishing fails", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		service, repository, settingsService, publisher, _ := newGuestService(ctrl)
+		publisher.err = errors.New("broker unavailable")
+		repository.EXPECT().GetGuestProfile(gomock.Any(), guestID).Return(&models.GuestProfile{GuestID: guestID}, nil)
+		settingsService.EXPECT().UpdateSettings(gomock.Any(), userID, gomock.Any(), nil).
+			Return(&settingsModels.UserSettings{UserID: userID}, nil)
+
+		_, err := service.UpgradeGuest(context.Background(), guestID, userID)
+		assertErrorCode(t, err, GuestsErrorInternalError)
+	})
+
+	t.Run("rejects swapped IDs", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		service, _, _, _, _ := newGuestService(ctrl)
+
+		_, err := service.UpgradeGuest(context.Background(), userID, guestID)
+		assertErrorCode(t, err, GuestsErrorInvalidID)
+	})
+}
+
+func assertErrorCode(t *testing.T, err error, code ErrorCode) {
+	t.Helper()
+
+	var guestsErr *Error
+	require.True(t, errors.As(err, &guestsErr), "expected guests error, got %v", err)
+	assert.Equal(t, code, guestsErr.Code)
+}
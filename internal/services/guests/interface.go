@@ -0,0 +1,21 @@
+package guests
+
+import (
+	"context"
+
+	"github.com/weeb-vip/user-service/internal/services/guests/models"
+	settingsModels "github.com/weeb-vip/user-service/internal/services/settings/models"
+)
+
+type Guests interface {
+	// GetProfile returns the guest's profile, or an unsaved default profile when they have none.
+	GetProfile(ctx context.Context, guestID string) (*models.GuestProfile, error)
+	// UpdateProfile sets the language when it is not nil and applies changes on top of the stored
+	// settings, pushing the profile's expiry back.
+	UpdateProfile(ctx context.Context, guestID string, language *string, changes map[string]any) (*models.GuestProfile, error)
+	// UpgradeGuest carries the guest's settings over to userID, replacing the user's values for
+	// every key the guest set, and the guest's language when the user has not picked one. It then
+	// drops the guest profile and publishes events.GuestUpgraded.
+	// Upgrading a guest without a profile only publishes the event.
+	UpgradeGuest(ctx context.Context, guestID string, userID string) (*settingsModels.UserSettings, error)
+}
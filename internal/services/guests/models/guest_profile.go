@@ -0,0 +1,22 @@
+package models
+
+import (
+	"time"
+
+	settingsModels "github.com/weeb-vip/user-service/internal/services/settings/models"
+)
+
+// GuestProfile is what a guest can keep before signing up. It is dropped once ExpiresAt passes,
+// and every change pushes ExpiresAt back.
+type GuestProfile struct {
+	GuestID   string                `json:"guest_id" gorm:"primaryKey"`
+	Language  string                `json:"language"`
+	Settings  settingsModels.Values `json:"settings" gorm:"column:settings"`
+	ExpiresAt time.Time             `json:"expires_at"`
+	CreatedAt time.Time             `json:"created_at"`
+	UpdatedAt time.Time             `json:"updated_at"`
+}
+
+func (GuestProfile) TableName() string {
+	return "guest_profiles"
+}
@@ -0,0 +1,181 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/weeb-vip/user-service/internal/db"
+	"github.com/weeb-vip/user-service/internal/services/guests/models"
+	"github.com/weeb-vip/user-service/metrics"
+	"github.com/weeb-vip/user-service/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type GuestProfilesRepository interface {
+	// GetGuestProfile returns nil when the guest has no profile or it expired.
+	GetGuestProfile(ctx context.Context, guestID string) (*models.GuestProfile, error)
+	// SaveGuestProfile creates or replaces the guest's profile.
+	SaveGuestProfile(ctx context.Context, profile *models.GuestProfile) error
+	DeleteGuestProfile(ctx context.Context, guestID string) error
+	// DeleteExpiredGuestProfiles removes profiles that expired before the given time.
+	DeleteExpiredGuestProfiles(ctx context.Context, before time.Time) (int64, error)
+}
+
+type guestProfilesRepository struct {
+	DBService db.DB
+}
+
+var guestProfilesRepositorySingleton GuestProfilesRepository // nolint
+
+func NewGuestProfilesRepository() GuestProfilesRepository {
+	dbService := db.GetDBService()
+
+	return &guestProfilesRepository{
+		DBService: dbService,
+	}
+}
+
+func (repository *guestProfilesRepository) GetGuestProfile(ctx context.Context, guestID string) (*models.GuestProfile, error) {
+	tracer := tracing.GetTracer(ctx)
+	ctx, span := tracer.Start(ctx, "repository.GetGuestProfile",
+		trace.WithAttributes(
+			attribute.String("guest.id", guestID),
+			attribute.String("table", "guest_profiles"),
+			attribute.String("operation", "select"),
+		),
+		tracing.GetEnvironmentAttribute(),
+	)
+	defer span.End()
+
+	start := time.Now()
+	database := repository.DBService.GetDB()
+
+	var profile models.GuestProfile
+	err := database.WithContext(ctx).
+		Where("guest_id = ? AND expires_at > ?", guestID, time.Now()).
+		First(&profile).Error
+
+	// Record database metrics
+	duration := float64(time.Since(start).Nanoseconds()) / float64(time.Millisecond)
+	appMetrics := metrics.GetAppMetrics()
+	result := metrics.Success
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		result = metrics.Error
+	}
+	appMetrics.DatabaseMetric(duration, "guest_profiles", "select", result)
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &profile, nil
+}
+
+func (repository *guestProfilesRepository) SaveGuestProfile(ctx context.Context, profile *models.GuestProfile) error {
+	tracer := tracing.GetTracer(ctx)
+	ctx, span := tracer.Start(ctx, "repository.SaveGuestProfile",
+		trace.WithAttributes(
+			attribute.String("guest.id", profile.GuestID),
+			attribute.String("table", "guest_profiles"),
+			attribute.String("operation", "upsert"),
+		),
+		tracing.GetEnvironmentAttribute(),
+	)
+	defer span.End()
+
+	start := time.Now()
+	database := repository.DBService.GetDB()
+
+	err := database.WithContext(ctx).Clauses(clause.OnConflict{
+		DoUpdates: clause.AssignmentColumns([]string{"language", "settings", "expires_at", "updated_at"}),
+	}).Create(profile).Error
+
+	// Record database metrics
+	duration := float64(time.Since(start).Nanoseconds()) / float64(time.Millisecond)
+	appMetrics := metrics.GetAppMetrics()
+	result := metrics.Success
+	if err != nil {
+		result = metrics.Error
+	}
+	appMetrics.DatabaseMetric(duration, "guest_profiles", "upsert", result)
+
+	return err
+}
+
+func (repository *guestProfilesRepository) DeleteGuestProfile(ctx context.Context, guestID string) error {
+	tracer := tracing.GetTracer(ctx)
+	ctx, span := tracer.Start(ctx, "repository.DeleteGuestProfile",
+		trace.WithAttributes(
+			attribute.String("guest.id", guestID),
+			attribute.String("table", "guest_profiles"),
+			attribute.String("operation", "delete"),
+		),
+		tracing.GetEnvironmentAttribute(),
+	)
+	defer span.End()
+
+	start := time.Now()
+	database := repository.DBService.GetDB()
+
+	err := database.WithContext(ctx).Where("guest_id = ?", guestID).Delete(&models.GuestProfile{}).Error
+
+	// Record database metrics
+	duration := float64(time.Since(start).Nanoseconds()) / float64(time.Millisecond)
+	appMetrics := metrics.GetAppMetrics()
+	result := metrics.Success
+	if err != nil {
+		result = metrics.Error
+	}
+	appMetrics.DatabaseMetric(duration, "guest_profiles", "delete", result)
+
+	return err
+}
+
+func (repository *guestProfilesRepository) DeleteExpiredGuestProfiles(ctx context.Context, before time.Time) (int64, error) {
+	tracer := tracing.GetTracer(ctx)
+	ctx, span := tracer.Start(ctx, "repository.DeleteExpiredGuestProfiles",
+		trace.WithAttributes(
+			attribute.String("table", "guest_profiles"),
+			attribute.String("operation", "delete"),
+		),
+		tracing.GetEnvironmentAttribute(),
+	)
+	defer span.End()
+
+	start := time.Now()
+	database := repository.DBService.GetDB()
+
+	result := database.WithContext(ctx).Where("expires_at <= ?", before).Delete(&models.GuestProfile{})
+	err := result.Error
+
+	// Record database metrics
+	duration := float64(time.Since(start).Nanoseconds()) / float64(time.Millisecond)
+	appMetrics := metrics.GetAppMetrics()
+	metricResult := metrics.Success
+	if err != nil {
+		metricResult = metrics.Error
+	}
+	appMetrics.DatabaseMetric(duration, "guest_profiles", "delete", metricResult)
+
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected, nil
+}
+
+func GetGuestProfilesRepository() GuestProfilesRepository {
+	if guestProfilesRepositorySingleton == nil {
+		guestProfilesRepositorySingleton = NewGuestProfilesRepository()
+	}
+
+	return guestProfilesRepositorySingleton
+}
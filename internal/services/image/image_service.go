@@ -3,6 +3,8 @@ package image
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"image"
 	"image/gif"
@@ -14,6 +16,8 @@ import (
 	"time"
 
 	"github.com/99designs/gqlgen/graphql"
+	"github.com/weeb-vip/user-service/internal/services/image/models"
+	"github.com/weeb-vip/user-service/internal/services/image/repositories"
 	"github.com/weeb-vip/user-service/internal/storage"
 	"github.com/weeb-vip/user-service/metrics"
 	"github.com/weeb-vip/user-service/tracing"
@@ -22,17 +26,41 @@ import (
 	"golang.org/x/image/draw"
 )
 
+// contentAddressedPrefix is where images keyed by the SHA-256 of their bytes are stored.
+// Keys under it never change content, so they can be cached forever.
+const contentAddressedPrefix = "images/"
+
+const immutableCacheControl = "public, max-age=31536000, immutable"
+
+var thumbnailSizes = []int{32, 64}
+
 type ImageService struct {
-	storage storage.Storage
+	storage          storage.Storage
+	imagesRepository repositories.ImagesRepository
+}
+
+// UploadedImage describes an image stored for a profile.
+type UploadedImage struct {
+	Path   string
+	Hash   string
+	Size   int64
+	Width  int
+	Height int
+}
+
+type thumbnail struct {
+	size int
+	data []byte
 }
 
-func NewImageService(storage storage.Storage) *ImageService {
+func NewImageService(storage storage.Storage, imagesRepository repositories.ImagesRepository) *ImageService {
 	return &ImageService{
-		storage: storage,
+		storage:          storage,
+		imagesRepository: imagesRepository,
 	}
 }
 
-func (s *ImageService) UploadProfileImage(ctx context.Context, userID string, file graphql.Upload) (string, error) {
+func (s *ImageService) UploadProfileImage(ctx context.Context, userID string, file graphql.Upload) (*UploadedImage, error) {
 	tracer := tracing.GetTracer(ctx)
 	ctx, span := tracer.Start(ctx, "imageService.UploadProfileImage",
 		trace.WithAttributes(
@@ -58,7 +86,7 @@ func (s *ImageService) UploadProfileImage(ctx context.Context, userID string, fi
 			"UploadProfileImage",
 			metrics.Error,
 		)
-		return "", fmt.Errorf("failed to read file: %w", err)
+		return nil, fmt.Errorf("failed to read file: %w", err)
 	}
 
 	// Get file extension
@@ -77,27 +105,17 @@ func (s *ImageService) UploadProfileImage(ctx context.Context, userID string, fi
 		}
 	}
 	if !isValidExt {
-		return "", fmt.Errorf("invalid file extension: %s", ext)
+		return nil, fmt.Errorf("invalid file extension: %s", ext)
 	}
 
 	// Process the image data
 	processedData, processedExt, err := s.processImage(buf.Bytes(), ext)
 	if err != nil {
-		return "", fmt.Errorf("failed to process image: %w", err)
+		return nil, fmt.Errorf("failed to process image: %w", err)
 	}
 
-	// Use a consistent filename pattern for each user
-	// Include timestamp with milliseconds to avoid collisions and for cache busting
-	timestamp := time.Now().Format("20060102150405.000")
-	// Replace dots in timestamp to avoid issues with file extensions
-	timestamp = strings.ReplaceAll(timestamp, ".", "")
-	
-	// Get base filename without extension for creating multiple versions
-	baseFilename := fmt.Sprintf("profiles/%s/profile_%s", userID, timestamp)
-	originalFilename := baseFilename + processedExt
-
-	// Upload original image
-	err = s.storage.Put(ctx, processedData, originalFilename)
+	// Generate thumbnails before anything is stored, so undecodable images never reach storage
+	thumbnails, err := s.generateThumbnails(processedData)
 	if err != nil {
 		metrics.GetAppMetrics().ServiceMetric(
 			float64(time.Since(startTime).Milliseconds()),
@@ -105,23 +123,43 @@ func (s *ImageService) UploadProfileImage(ctx context.Context, userID string, fi
 			"UploadProfileImage",
 			metrics.Error,
 		)
-		return "", fmt.Errorf("failed to upload original image to storage: %w", err)
+		return nil, fmt.Errorf("failed to generate thumbnails: %w", err)
 	}
 
-	span.SetAttributes(attribute.String("image.path", originalFilename))
+	// Key the image by its content so identical uploads share one stored copy
+	sum := sha256.Sum256(processedData)
+	hash := hex.EncodeToString(sum[:])
+	storedExt := normalizeExt(processedExt)
+	baseFilename := contentAddressedPrefix + hash
+	originalFilename := baseFilename + storedExt
+
+	span.SetAttributes(
+		attribute.String("image.hash", hash),
+		attribute.String("image.path", originalFilename),
+	)
+
+	created, err := s.imagesRepository.Acquire(ctx, hash, originalFilename, int64(len(processedData)))
+	if err != nil {
+		metrics.GetAppMetrics().ServiceMetric(
+			float64(time.Since(startTime).Milliseconds()),
+			"image",
+			"UploadProfileImage",
+			metrics.Error,
+		)
+		return nil, fmt.Errorf("failed to reference image: %w", err)
+	}
 
-	// Generate and upload thumbnails
-	err = s.generateAndUploadThumbnails(ctx, processedData, baseFilename, processedExt)
+	err = s.storeIfMissing(ctx, created, processedData, thumbnails, baseFilename, storedExt)
 	if err != nil {
-		// If thumbnail generation fails, delete the original and return error
-		_ = s.storage.Delete(ctx, originalFilename)
+		// Give the reference back; the last holder removes whatever was stored
+		_ = s.releaseImage(ctx, hash)
 		metrics.GetAppMetrics().ServiceMetric(
 			float64(time.Since(startTime).Milliseconds()),
 			"image",
 			"UploadProfileImage",
 			metrics.Error,
 		)
-		return "", fmt.Errorf("failed to generate thumbnails: %w", err)
+		return nil, err
 	}
 
 	metrics.GetAppMetrics().ServiceMetric(
@@ -131,8 +169,16 @@ func (s *ImageService) UploadProfileImage(ctx context.Context, userID string, fi
 		metrics.Success,
 	)
 
-	// Return the original image path
-	return originalFilename, nil
+	// Thumbnails decoded the image already, so this cannot fail
+	dimensions, _, _ := image.DecodeConfig(bytes.NewReader(processedData))
+
+	return &UploadedImage{
+		Path:   originalFilename,
+		Hash:   hash,
+		Size:   int64(len(processedData)),
+		Width:  dimensions.Width,
+		Height: dimensions.Height,
+	}, nil
 }
 
 // processImage handles image processing, converting GIFs to still images
@@ -173,70 +219,87 @@ func (s *ImageService) convertGifToStill(gifData []byte) ([]byte, string, error)
 	return buf.Bytes(), ".png", nil
 }
 
-// generateAndUploadThumbnails creates 32x32 and 64x64 thumbnails and uploads them
-func (s *ImageService) generateAndUploadThumbnails(ctx context.Context, imageData []byte, baseFilename, ext string) error {
-	// Decode the original image
-	img, format, err := image.Decode(bytes.NewReader(imageData))
-	if err != nil {
-		return fmt.Errorf("failed to decode image for thumbnails: %w", err)
-	}
+// storeIfMissing uploads the image and its thumbnails unless an identical copy is already stored
+func (s *ImageService) storeIfMissing(ctx context.Context, created bool, imageData []byte, thumbnails []thumbnail, baseFilename, ext string) error {
+	originalFilename := baseFilename + ext
 
-	// Generate 32x32 thumbnail
-	thumb32, err := s.resizeImage(img, 32, 32)
-	if err != nil {
-		return fmt.Errorf("failed to create 32x32 thumbnail: %w", err)
+	if !created {
+		exists, err := storage.Exists(ctx, s.storage, originalFilename)
+		if err != nil {
+			return fmt.Errorf("failed to check stored image: %w", err)
+		}
+		if exists {
+			return nil
+		}
 	}
 
-	// Generate 64x64 thumbnail
-	thumb64, err := s.resizeImage(img, 64, 64)
+	err := s.storage.Put(ctx, imageData, originalFilename, storage.WithCacheControl(immutableCacheControl))
 	if err != nil {
-		return fmt.Errorf("failed to create 64x64 thumbnail: %w", err)
+		return fmt.Errorf("failed to upload original image to storage: %w", err)
 	}
 
-	// Encode and upload 32x32 thumbnail
-	thumb32Data, err := s.encodeImage(thumb32, format)
-	if err != nil {
-		return fmt.Errorf("failed to encode 32x32 thumbnail: %w", err)
+	for _, thumb := range thumbnails {
+		thumbFilename := fmt.Sprintf("%s_%d%s", baseFilename, thumb.size, ext)
+		err = s.storage.Put(ctx, thumb.data, thumbFilename, storage.WithCacheControl(immutableCacheControl))
+		if err != nil {
+			return fmt.Errorf("failed to upload %dx%d thumbnail: %w", thumb.size, thumb.size, err)
+		}
 	}
-	
-	thumb32Filename := baseFilename + "_32" + ext
-	err = s.storage.Put(ctx, thumb32Data, thumb32Filename)
+
+	return nil
+}
+
+// generateThumbnails creates a square thumbnail for every size in thumbnailSizes
+func (s *ImageService) generateThumbnails(imageData []byte) ([]thumbnail, error) {
+	// Decode the original image
+	img, format, err := image.Decode(bytes.NewReader(imageData))
 	if err != nil {
-		return fmt.Errorf("failed to upload 32x32 thumbnail: %w", err)
+		return nil, fmt.Errorf("failed to decode image for thumbnails: %w", err)
 	}
 
-	// Encode and upload 64x64 thumbnail
-	thumb64Data, err := s.encodeImage(thumb64, format)
-	if err != nil {
-		return fmt.Errorf("failed to encode 64x64 thumbnail: %w", err)
+	thumbnails := make([]thumbnail, 0, len(thumbnailSizes))
+	for _, size := range thumbnailSizes {
+		resized, err := s.resizeImage(img, size, size)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create %dx%d thumbnail: %w", size, size, err)
+		}
+
+		data, err := s.encodeImage(resized, format)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode %dx%d thumbnail: %w", size, size, err)
+		}
+
+		thumbnails = append(thumbnails, thumbnail{size: size, data: data})
 	}
-	
-	thumb64Filename := baseFilename + "_64" + ext
-	err = s.storage.Put(ctx, thumb64Data, thumb64Filename)
-	if err != nil {
-		// If 64x64 upload fails, try to clean up the 32x32 thumbnail
-		_ = s.storage.Delete(ctx, thumb32Filename)
-		return fmt.Errorf("failed to upload 64x64 thumbnail: %w", err)
+
+	return thumbnails, nil
+}
+
+// normalizeExt lower-cases the extension and folds .jpeg into .jpg so equal bytes map to one key
+func normalizeExt(ext string) string {
+	ext = strings.ToLower(ext)
+	if ext == ".jpeg" {
+		return ".jpg"
 	}
 
-	return nil
+	return ext
 }
 
 // resizeImage resizes an image to the specified dimensions
 func (s *ImageService) resizeImage(src image.Image, width, height int) (image.Image, error) {
 	// Create a new image with the target size
 	dst := image.NewRGBA(image.Rect(0, 0, width, height))
-	
+
 	// Use BiLinear scaling for good quality thumbnails
 	draw.BiLinear.Scale(dst, dst.Bounds(), src, src.Bounds(), draw.Over, nil)
-	
+
 	return dst, nil
 }
 
 // encodeImage encodes an image based on the original format
 func (s *ImageService) encodeImage(img image.Image, format string) ([]byte, error) {
 	var buf bytes.Buffer
-	
+
 	switch format {
 	case "jpeg":
 		err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: 85})
@@ -255,7 +318,7 @@ func (s *ImageService) encodeImage(img image.Image, format string) ([]byte, erro
 			return nil, fmt.Errorf("failed to encode as PNG: %w", err)
 		}
 	}
-	
+
 	return buf.Bytes(), nil
 }
 
@@ -283,8 +346,13 @@ func (s *ImageService) DeleteProfileImage(ctx context.Context, imagePath string)
 		return nil
 	}
 
-	// Delete original image
-	err := s.storage.Delete(ctx, imagePath)
+	var err error
+	if hash, ok := contentHash(imagePath); ok {
+		// Shared images are only removed once no other profile references them
+		err = s.releaseImage(ctx, hash)
+	} else {
+		err = s.deleteVariants(ctx, imagePath)
+	}
 	if err != nil {
 		metrics.GetAppMetrics().ServiceMetric(
 			float64(time.Since(startTime).Milliseconds()),
@@ -292,22 +360,9 @@ func (s *ImageService) DeleteProfileImage(ctx context.Context, imagePath string)
 			"DeleteProfileImage",
 			metrics.Error,
 		)
-		return fmt.Errorf("failed to delete original image from storage: %w", err)
+		return err
 	}
 
-	// Generate thumbnail paths and delete them
-	// Extract base filename and extension
-	ext := filepath.Ext(imagePath)
-	baseWithoutExt := strings.TrimSuffix(imagePath, ext)
-	
-	// Delete 32x32 thumbnail
-	thumb32Path := baseWithoutExt + "_32" + ext
-	_ = s.storage.Delete(ctx, thumb32Path) // Don't fail if thumbnail doesn't exist
-
-	// Delete 64x64 thumbnail
-	thumb64Path := baseWithoutExt + "_64" + ext
-	_ = s.storage.Delete(ctx, thumb64Path) // Don't fail if thumbnail doesn't exist
-
 	metrics.GetAppMetrics().ServiceMetric(
 		float64(time.Since(startTime).Milliseconds()),
 		"image",
@@ -316,4 +371,78 @@ func (s *ImageService) DeleteProfileImage(ctx context.Context, imagePath string)
 	)
 
 	return nil
-}
\ No newline at end of file
+}
+
+// releaseImage drops one reference to a content-addressed image, deleting it from storage with the last one
+func (s *ImageService) releaseImage(ctx context.Context, hash string) error {
+	err := s.imagesRepository.Release(ctx, hash, func(ctx context.Context, img *models.Image) error {
+		return s.deleteVariants(ctx, img.Path)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to release image: %w", err)
+	}
+
+	return nil
+}
+
+// deleteVariants removes the image at imagePath together with every thumbnail generated from it
+func (s *ImageService) deleteVariants(ctx context.Context, imagePath string) error {
+	ext := filepath.Ext(imagePath)
+	baseWithoutExt := strings.TrimSuffix(imagePath, ext)
+
+	objects, err := storage.ListAll(ctx, s.storage, baseWithoutExt)
+	if err != nil {
+		return fmt.Errorf("failed to list image variants: %w", err)
+	}
+
+	for _, object := range objects {
+		if !isImageVariant(object.Path, baseWithoutExt) {
+			continue
+		}
+
+		err = s.storage.Delete(ctx, object.Path)
+		if err != nil {
+			return fmt.Errorf("failed to delete image from storage: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// contentHash extracts the SHA-256 from a content-addressed image path
+func contentHash(imagePath string) (string, bool) {
+	name, found := strings.CutPrefix(imagePath, contentAddressedPrefix)
+	if !found {
+		return "", false
+	}
+
+	hash := strings.TrimSuffix(name, filepath.Ext(name))
+	if len(hash) != sha256.Size*2 {
+		return "", false
+	}
+	if _, err := hex.DecodeString(hash); err != nil {
+		return "", false
+	}
+
+	return hash, true
+}
+
+// isImageVariant reports whether path is the image at base or one of its _<size> thumbnails
+func isImageVariant(path string, base string) bool {
+	stem := strings.TrimSuffix(path, filepath.Ext(path))
+	if stem == base {
+		return true
+	}
+
+	size, found := strings.CutPrefix(stem, base+"_")
+	if !found || size == "" {
+		return false
+	}
+	for _, r := range size {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+
+	return true
+}
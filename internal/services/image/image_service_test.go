@@ -3,8 +3,15 @@ package image
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
 	"io"
 	"strings"
 	"testing"
@@ -12,6 +19,9 @@ import (
 	"github.com/99designs/gqlgen/graphql"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/weeb-vip/user-service/internal/services/image/models"
+	"github.com/weeb-vip/user-service/internal/services/image/repositories"
+	"github.com/weeb-vip/user-service/internal/storage"
 	"github.com/weeb-vip/user-service/mocks"
 	"go.uber.org/mock/gomock"
 )
@@ -21,19 +31,25 @@ func TestNewImageService(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockStorage := mocks.NewMockStorage(ctrl)
-	service := NewImageService(mockStorage)
+	mockRepository := mocks.NewMockImagesRepository(ctrl)
+	service := NewImageService(mockStorage, mockRepository)
 
 	assert.NotNil(t, service)
 	assert.Equal(t, mockStorage, service.storage)
+	assert.Equal(t, mockRepository, service.imagesRepository)
 }
 
 func TestImageService_UploadProfileImage(t *testing.T) {
+	pngContent := encodeTestImage(t, "png", 100, 100)
+	jpegContent := encodeTestImage(t, "jpeg", 100, 100)
+	gifContent := encodeTestImage(t, "gif", 100, 100)
+
 	tests := []struct {
 		name          string
 		userID        string
 		filename      string
-		fileContent   string
-		setupMock     func(*mocks.MockStorage)
+		fileContent   []byte
+		setupMock     func(*mocks.MockStorage, *mocks.MockImagesRepository)
 		expectedError string
 		validatePath  func(t *testing.T, path string)
 	}{
@@ -41,142 +57,165 @@ func TestImageService_UploadProfileImage(t *testing.T) {
 			name:        "successful upload with jpg",
 			userID:      "user123",
 			filename:    "profile.jpg",
-			fileContent: "fake image content",
-			setupMock: func(ms *mocks.MockStorage) {
-				ms.EXPECT().
-					Put(gomock.Any(), []byte("fake image content"), gomock.Any()).
-					DoAndReturn(func(ctx context.Context, data []byte, path string) error {
-						// Validate the path format
-						assert.Contains(t, path, "profiles/user123/")
-						assert.True(t, strings.HasSuffix(path, ".jpg"))
-						return nil
-					})
+			fileContent: jpegContent,
+			setupMock: func(ms *mocks.MockStorage, mr *mocks.MockImagesRepository) {
+				hash := contentHashOf(jpegContent)
+				mr.EXPECT().Acquire(gomock.Any(), hash, "images/"+hash+".jpg", int64(len(jpegContent))).Return(true, nil)
+				ms.EXPECT().Put(gomock.Any(), jpegContent, "images/"+hash+".jpg", gomock.Any()).Return(nil)
+				ms.EXPECT().Put(gomock.Any(), gomock.Any(), "images/"+hash+"_32.jpg", gomock.Any()).Return(nil)
+				ms.EXPECT().Put(gomock.Any(), gomock.Any(), "images/"+hash+"_64.jpg", gomock.Any()).Return(nil)
 			},
 			validatePath: func(t *testing.T, path string) {
-				assert.Contains(t, path, "profiles/user123/")
-				assert.True(t, strings.HasSuffix(path, ".jpg"))
+				assert.Equal(t, "images/"+contentHashOf(jpegContent)+".jpg", path)
 			},
 		},
 		{
 			name:        "successful upload with png",
 			userID:      "user456",
 			filename:    "avatar.png",
-			fileContent: "fake png content",
-			setupMock: func(ms *mocks.MockStorage) {
-				ms.EXPECT().
-					Put(gomock.Any(), []byte("fake png content"), gomock.Any()).
-					Return(nil)
+			fileContent: pngContent,
+			setupMock: func(ms *mocks.MockStorage, mr *mocks.MockImagesRepository) {
+				mr.EXPECT().Acquire(gomock.Any(), contentHashOf(pngContent), gomock.Any(), gomock.Any()).Return(true, nil)
+				ms.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(3).Return(nil)
 			},
 			validatePath: func(t *testing.T, path string) {
-				assert.Contains(t, path, "profiles/user456/")
-				assert.True(t, strings.HasSuffix(path, ".png"))
+				assert.Equal(t, "images/"+contentHashOf(pngContent)+".png", path)
 			},
 		},
 		{
-			name:        "successful upload with uppercase extension",
+			name:        "uppercase extension is normalized",
 			userID:      "user789",
 			filename:    "photo.PNG",
-			fileContent: "fake png content",
-			setupMock: func(ms *mocks.MockStorage) {
-				ms.EXPECT().
-					Put(gomock.Any(), []byte("fake png content"), gomock.Any()).
-					Return(nil)
+			fileContent: pngContent,
+			setupMock: func(ms *mocks.MockStorage, mr *mocks.MockImagesRepository) {
+				mr.EXPECT().Acquire(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(true, nil)
+				ms.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(3).Return(nil)
 			},
 			validatePath: func(t *testing.T, path string) {
-				assert.Contains(t, path, "profiles/user789/")
-				assert.True(t, strings.HasSuffix(path, ".PNG"))
+				assert.True(t, strings.HasSuffix(path, ".png"))
 			},
 		},
 		{
-			name:        "successful upload with gif",
-			userID:      "user111",
-			filename:    "animated.gif",
-			fileContent: "fake gif content",
-			setupMock: func(ms *mocks.MockStorage) {
-				ms.EXPECT().
-					Put(gomock.Any(), []byte("fake gif content"), gomock.Any()).
-					Return(nil)
+			name:        "jpeg extension is folded into jpg",
+			userID:      "user789",
+			filename:    "photo.jpeg",
+			fileContent: jpegContent,
+			setupMock: func(ms *mocks.MockStorage, mr *mocks.MockImagesRepository) {
+				mr.EXPECT().Acquire(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(true, nil)
+				ms.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(3).Return(nil)
 			},
 			validatePath: func(t *testing.T, path string) {
-				assert.Contains(t, path, "profiles/user111/")
-				assert.True(t, strings.HasSuffix(path, ".gif"))
+				assert.Equal(t, "images/"+contentHashOf(jpegContent)+".jpg", path)
 			},
 		},
 		{
-			name:        "successful upload with webp",
-			userID:      "user222",
-			filename:    "modern.webp",
-			fileContent: "fake webp content",
-			setupMock: func(ms *mocks.MockStorage) {
-				ms.EXPECT().
-					Put(gomock.Any(), []byte("fake webp content"), gomock.Any()).
-					Return(nil)
+			name:        "gif is stored as a still png",
+			userID:      "user111",
+			filename:    "animated.gif",
+			fileContent: gifContent,
+			setupMock: func(ms *mocks.MockStorage, mr *mocks.MockImagesRepository) {
+				mr.EXPECT().Acquire(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(true, nil)
+				ms.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(3).Return(nil)
 			},
 			validatePath: func(t *testing.T, path string) {
-				assert.Contains(t, path, "profiles/user222/")
-				assert.True(t, strings.HasSuffix(path, ".webp"))
+				assert.True(t, strings.HasPrefix(path, "images/"))
+				assert.True(t, strings.HasSuffix(path, ".png"))
 			},
 		},
 		{
 			name:        "file without extension defaults to jpg",
 			userID:      "user333",
 			filename:    "noextension",
-			fileContent: "fake content",
-			setupMock: func(ms *mocks.MockStorage) {
-				ms.EXPECT().
-					Put(gomock.Any(), []byte("fake content"), gomock.Any()).
-					Return(nil)
+			fileContent: jpegContent,
+			setupMock: func(ms *mocks.MockStorage, mr *mocks.MockImagesRepository) {
+				mr.EXPECT().Acquire(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(true, nil)
+				ms.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(3).Return(nil)
 			},
 			validatePath: func(t *testing.T, path string) {
-				assert.Contains(t, path, "profiles/user333/")
 				assert.True(t, strings.HasSuffix(path, ".jpg"))
 			},
 		},
+		{
+			name:        "identical image already stored is not uploaded again",
+			userID:      "user444",
+			filename:    "profile.png",
+			fileContent: pngContent,
+			setupMock: func(ms *mocks.MockStorage, mr *mocks.MockImagesRepository) {
+				hash := contentHashOf(pngContent)
+				mr.EXPECT().Acquire(gomock.Any(), hash, gomock.Any(), gomock.Any()).Return(false, nil)
+				ms.EXPECT().Stat(gomock.Any(), "images/"+hash+".png").Return(&storage.ObjectInfo{Path: "images/" + hash + ".png"}, nil)
+			},
+			validatePath: func(t *testing.T, path string) {
+				assert.Equal(t, "images/"+contentHashOf(pngContent)+".png", path)
+			},
+		},
+		{
+			name:        "referenced image missing from storage is uploaded again",
+			userID:      "user444",
+			filename:    "profile.png",
+			fileContent: pngContent,
+			setupMock: func(ms *mocks.MockStorage, mr *mocks.MockImagesRepository) {
+				mr.EXPECT().Acquire(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(false, nil)
+				ms.EXPECT().Stat(gomock.Any(), gomock.Any()).Return(nil, storage.ErrObjectNotFound)
+				ms.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(3).Return(nil)
+			},
+		},
 		{
 			name:          "invalid file extension",
 			userID:        "user444",
 			filename:      "document.pdf",
-			fileContent:   "fake pdf content",
-			setupMock:     func(ms *mocks.MockStorage) {},
+			fileContent:   []byte("fake pdf content"),
+			setupMock:     func(ms *mocks.MockStorage, mr *mocks.MockImagesRepository) {},
 			expectedError: "invalid file extension: .pdf",
 		},
 		{
 			name:          "invalid executable extension",
 			userID:        "user555",
 			filename:      "malware.exe",
-			fileContent:   "fake exe content",
-			setupMock:     func(ms *mocks.MockStorage) {},
+			fileContent:   []byte("fake exe content"),
+			setupMock:     func(ms *mocks.MockStorage, mr *mocks.MockImagesRepository) {},
 			expectedError: "invalid file extension: .exe",
 		},
 		{
-			name:        "storage upload fails",
+			name:        "storage upload fails releases the reference",
 			userID:      "user666",
-			filename:    "profile.jpg",
-			fileContent: "fake content",
-			setupMock: func(ms *mocks.MockStorage) {
+			filename:    "profile.png",
+			fileContent: pngContent,
+			setupMock: func(ms *mocks.MockStorage, mr *mocks.MockImagesRepository) {
+				hash := contentHashOf(pngContent)
+				mr.EXPECT().Acquire(gomock.Any(), hash, gomock.Any(), gomock.Any()).Return(true, nil)
 				ms.EXPECT().
-					Put(gomock.Any(), gomock.Any(), gomock.Any()).
+					Put(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
 					Return(errors.New("storage error"))
+				mr.EXPECT().Release(gomock.Any(), hash, gomock.Any()).Return(nil)
 			},
-			expectedError: "failed to upload to storage: storage error",
+			expectedError: "failed to upload original image to storage: storage error",
 		},
 		{
-			name:        "empty file content",
-			userID:      "user777",
-			filename:    "empty.jpg",
-			fileContent: "",
-			setupMock: func(ms *mocks.MockStorage) {
-				ms.EXPECT().
-					Put(gomock.Any(), gomock.Any(), gomock.Any()).
-					DoAndReturn(func(ctx context.Context, data []byte, path string) error {
-						// Validate empty content
-						assert.Empty(t, data)
-						return nil
-					})
-			},
-			validatePath: func(t *testing.T, path string) {
-				assert.Contains(t, path, "profiles/user777/")
+			name:        "reference fails",
+			userID:      "user666",
+			filename:    "profile.png",
+			fileContent: pngContent,
+			setupMock: func(ms *mocks.MockStorage, mr *mocks.MockImagesRepository) {
+				mr.EXPECT().Acquire(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(false, errors.New("db error"))
 			},
+			expectedError: "failed to reference image: db error",
+		},
+		{
+			name:          "empty file content",
+			userID:        "user777",
+			filename:      "empty.jpg",
+			fileContent:   []byte{},
+			setupMock:     func(ms *mocks.MockStorage, mr *mocks.MockImagesRepository) {},
+			expectedError: "failed to generate thumbnails",
+		},
+		{
+			name:          "undecodable image content",
+			userID:        "user777",
+			filename:      "fake.jpg",
+			fileContent:   []byte("fake image content"),
+			setupMock:     func(ms *mocks.MockStorage, mr *mocks.MockImagesRepository) {},
+			expectedError: "failed to generate thumbnails",
 		},
 	}
 
@@ -186,77 +225,181 @@ func TestImageService_UploadProfileImage(t *testing.T) {
 			defer ctrl.Finish()
 
 			mockStorage := mocks.NewMockStorage(ctrl)
-			tt.setupMock(mockStorage)
+			mockRepository := mocks.NewMockImagesRepository(ctrl)
+			tt.setupMock(mockStorage, mockRepository)
 
-			service := NewImageService(mockStorage)
+			service := NewImageService(mockStorage, mockRepository)
 
 			// Create a mock upload
 			upload := graphql.Upload{
-				File:     strings.NewReader(tt.fileContent),
+				File:     bytes.NewReader(tt.fileContent),
 				Filename: tt.filename,
 			}
 
 			ctx := context.Background()
-			path, err := service.UploadProfileImage(ctx, tt.userID, upload)
+			uploaded, err := service.UploadProfileImage(ctx, tt.userID, upload)
 
 			if tt.expectedError != "" {
 				require.Error(t, err)
 				assert.Contains(t, err.Error(), tt.expectedError)
-				assert.Empty(t, path)
+				assert.Nil(t, uploaded)
 			} else {
 				require.NoError(t, err)
-				assert.NotEmpty(t, path)
+				require.NotNil(t, uploaded)
+				assert.NotEmpty(t, uploaded.Path)
 				if tt.validatePath != nil {
-					tt.validatePath(t, path)
+					tt.validatePath(t, uploaded.Path)
 				}
 			}
 		})
 	}
 }
 
+func TestImageService_UploadProfileImage_CacheControl(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := mocks.NewMockStorage(ctrl)
+	mockRepository := mocks.NewMockImagesRepository(ctrl)
+
+	mockRepository.EXPECT().Acquire(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(true, nil)
+	mockStorage.EXPECT().
+		Put(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Times(3).
+		DoAndReturn(func(ctx context.Context, data []byte, path string, opts ...storage.PutOption) error {
+			options := storage.PutOptions{}
+			for _, opt := range opts {
+				opt(&options)
+			}
+			assert.Equal(t, immutableCacheControl, options.CacheControl)
+			return nil
+		})
+
+	service := NewImageService(mockStorage, mockRepository)
+
+	upload := graphql.Upload{
+		File:     bytes.NewReader(encodeTestImage(t, "png", 10, 10)),
+		Filename: "cache.png",
+	}
+
+	_, err := service.UploadProfileImage(context.Background(), "user", upload)
+	require.NoError(t, err)
+}
+
 func TestImageService_DeleteProfileImage(t *testing.T) {
+	listing := func(ms *mocks.MockStorage, ctrl *gomock.Controller, prefix string, paths ...string) {
+		objects := make([]storage.ObjectInfo, 0, len(paths))
+		for _, path := range paths {
+			objects = append(objects, storage.ObjectInfo{Path: path})
+		}
+
+		iterator := mocks.NewMockObjectIterator(ctrl)
+		gomock.InOrder(
+			iterator.EXPECT().Next(gomock.Any()).Return(objects, nil),
+			iterator.EXPECT().Next(gomock.Any()).Return(nil, storage.ErrIteratorDone),
+		)
+		ms.EXPECT().List(gomock.Any(), prefix).Return(iterator)
+	}
+
 	tests := []struct {
 		name          string
 		imagePath     string
-		setupMock     func(*mocks.MockStorage)
+		setupMock     func(*mocks.MockStorage, *mocks.MockImagesRepository, *gomock.Controller)
 		expectedError string
 	}{
 		{
 			name:      "successful deletion",
 			imagePath: "profiles/user123/20240101120000_abc123.jpg",
-			setupMock: func(ms *mocks.MockStorage) {
-				ms.EXPECT().
-					Delete(gomock.Any(), "profiles/user123/20240101120000_abc123.jpg").
-					Return(nil)
+			setupMock: func(ms *mocks.MockStorage, mr *mocks.MockImagesRepository, ctrl *gomock.Controller) {
+				listing(ms, ctrl, "profiles/user123/20240101120000_abc123",
+					"profiles/user123/20240101120000_abc123.jpg",
+					"profiles/user123/20240101120000_abc123_32.jpg",
+					"profiles/user123/20240101120000_abc123_64.jpg",
+				)
+				ms.EXPECT().Delete(gomock.Any(), "profiles/user123/20240101120000_abc123.jpg").Return(nil)
+				ms.EXPECT().Delete(gomock.Any(), "profiles/user123/20240101120000_abc123_32.jpg").Return(nil)
+				ms.EXPECT().Delete(gomock.Any(), "profiles/user123/20240101120000_abc123_64.jpg").Return(nil)
+			},
+		},
+		{
+			name:      "only deletes variants that exist",
+			imagePath: "profiles/user123/profile_1.png",
+			setupMock: func(ms *mocks.MockStorage, mr *mocks.MockImagesRepository, ctrl *gomock.Controller) {
+				listing(ms, ctrl, "profiles/user123/profile_1", "profiles/user123/profile_1.png")
+				ms.EXPECT().Delete(gomock.Any(), "profiles/user123/profile_1.png").Return(nil)
+			},
+		},
+		{
+			name:      "ignores unrelated objects sharing the prefix",
+			imagePath: "profiles/user123/profile_1.png",
+			setupMock: func(ms *mocks.MockStorage, mr *mocks.MockImagesRepository, ctrl *gomock.Controller) {
+				listing(ms, ctrl, "profiles/user123/profile_1",
+					"profiles/user123/profile_1.png",
+					"profiles/user123/profile_12.png",
+					"profiles/user123/profile_1_backup.png",
+				)
+				ms.EXPECT().Delete(gomock.Any(), "profiles/user123/profile_1.png").Return(nil)
+			},
+		},
+		{
+			name:      "content-addressed image still referenced elsewhere is kept",
+			imagePath: "images/" + strings.Repeat("ab", 32) + ".png",
+			setupMock: func(ms *mocks.MockStorage, mr *mocks.MockImagesRepository, ctrl *gomock.Controller) {
+				mr.EXPECT().Release(gomock.Any(), strings.Repeat("ab", 32), gomock.Any()).Return(nil)
+			},
+		},
+		{
+			name:      "content-addressed image deleted with its last reference",
+			imagePath: "images/" + strings.Repeat("ab", 32) + ".png",
+			setupMock: func(ms *mocks.MockStorage, mr *mocks.MockImagesRepository, ctrl *gomock.Controller) {
+				base := "images/" + strings.Repeat("ab", 32)
+				mr.EXPECT().
+					Release(gomock.Any(), strings.Repeat("ab", 32), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, hash string, onLast repositories.OnLastReference) error {
+						return onLast(ctx, &models.Image{Hash: hash, Path: base + ".png"})
+					})
+				listing(ms, ctrl, base, base+".png", base+"_32.png", base+"_64.png")
+				ms.EXPECT().Delete(gomock.Any(), base+".png").Return(nil)
+				ms.EXPECT().Delete(gomock.Any(), base+"_32.png").Return(nil)
+				ms.EXPECT().Delete(gomock.Any(), base+"_64.png").Return(nil)
 			},
 		},
+		{
+			name:      "content-addressed release fails",
+			imagePath: "images/" + strings.Repeat("ab", 32) + ".png",
+			setupMock: func(ms *mocks.MockStorage, mr *mocks.MockImagesRepository, ctrl *gomock.Controller) {
+				mr.EXPECT().Release(gomock.Any(), gomock.Any(), gomock.Any()).Return(errors.New("db error"))
+			},
+			expectedError: "failed to release image: db error",
+		},
 		{
 			name:      "empty path - no deletion",
 			imagePath: "",
-			setupMock: func(ms *mocks.MockStorage) {
+			setupMock: func(ms *mocks.MockStorage, mr *mocks.MockImagesRepository, ctrl *gomock.Controller) {
 				// No expectations - Delete should not be called
 			},
 		},
+		{
+			name:      "listing fails",
+			imagePath: "profiles/user456/image.png",
+			setupMock: func(ms *mocks.MockStorage, mr *mocks.MockImagesRepository, ctrl *gomock.Controller) {
+				iterator := mocks.NewMockObjectIterator(ctrl)
+				iterator.EXPECT().Next(gomock.Any()).Return(nil, errors.New("storage error"))
+				ms.EXPECT().List(gomock.Any(), "profiles/user456/image").Return(iterator)
+			},
+			expectedError: "failed to list image variants: storage error",
+		},
 		{
 			name:      "storage deletion fails",
 			imagePath: "profiles/user456/image.png",
-			setupMock: func(ms *mocks.MockStorage) {
+			setupMock: func(ms *mocks.MockStorage, mr *mocks.MockImagesRepository, ctrl *gomock.Controller) {
+				listing(ms, ctrl, "profiles/user456/image", "profiles/user456/image.png")
 				ms.EXPECT().
 					Delete(gomock.Any(), "profiles/user456/image.png").
 					Return(errors.New("storage error"))
 			},
 			expectedError: "failed to delete image from storage: storage error",
 		},
-		{
-			name:      "deletion with not found error",
-			imagePath: "profiles/nonexistent/image.jpg",
-			setupMock: func(ms *mocks.MockStorage) {
-				ms.EXPECT().
-					Delete(gomock.Any(), "profiles/nonexistent/image.jpg").
-					Return(errors.New("not found"))
-			},
-			expectedError: "failed to delete image from storage: not found",
-		},
 	}
 
 	for _, tt := range tests {
@@ -265,9 +408,10 @@ func TestImageService_DeleteProfileImage(t *testing.T) {
 			defer ctrl.Finish()
 
 			mockStorage := mocks.NewMockStorage(ctrl)
-			tt.setupMock(mockStorage)
+			mockRepository := mocks.NewMockImagesRepository(ctrl)
+			tt.setupMock(mockStorage, mockRepository, ctrl)
 
-			service := NewImageService(mockStorage)
+			service := NewImageService(mockStorage, mockRepository)
 
 			ctx := context.Background()
 			err := service.DeleteProfileImage(ctx, tt.imagePath)
@@ -286,30 +430,34 @@ func TestImageService_UploadProfileImage_LargeFile(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
-	// Create a large file content (1MB)
-	largeContent := make([]byte, 1024*1024)
-	for i := range largeContent {
-		largeContent[i] = byte(i % 256)
-	}
+	largeContent := encodeTestImage(t, "png", 1024, 1024)
 
 	mockStorage := mocks.NewMockStorage(ctrl)
+	mockRepository := mocks.NewMockImagesRepository(ctrl)
+	mockRepository.EXPECT().Acquire(gomock.Any(), gomock.Any(), gomock.Any(), int64(len(largeContent))).Return(true, nil)
+	mockStorage.EXPECT().
+		Put(gomock.Any(), largeContent, gomock.Any(), gomock.Any()).
+		Return(nil)
 	mockStorage.EXPECT().
-		Put(gomock.Any(), largeContent, gomock.Any()).
+		Put(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Times(2).
 		Return(nil)
 
-	service := NewImageService(mockStorage)
+	service := NewImageService(mockStorage, mockRepository)
 
 	upload := graphql.Upload{
 		File:     bytes.NewReader(largeContent),
-		Filename: "large.jpg",
+		Filename: "large.png",
 	}
 
 	ctx := context.Background()
-	path, err := service.UploadProfileImage(ctx, "user999", upload)
+	uploaded, err := service.UploadProfileImage(ctx, "user999", upload)
 
 	require.NoError(t, err)
-	assert.NotEmpty(t, path)
-	assert.Contains(t, path, "profiles/user999/")
+	assert.Contains(t, uploaded.Path, "images/")
+	assert.Equal(t, int64(len(largeContent)), uploaded.Size)
+	assert.Equal(t, 1024, uploaded.Width)
+	assert.Equal(t, 1024, uploaded.Height)
 }
 
 func TestImageService_UploadProfileImage_FileReadError(t *testing.T) {
@@ -317,7 +465,7 @@ func TestImageService_UploadProfileImage_FileReadError(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockStorage := mocks.NewMockStorage(ctrl)
-	service := NewImageService(mockStorage)
+	service := NewImageService(mockStorage, mocks.NewMockImagesRepository(ctrl))
 
 	// Create a reader that will fail
 	failingReader := &failingReadCloser{
@@ -330,51 +478,45 @@ func TestImageService_UploadProfileImage_FileReadError(t *testing.T) {
 	}
 
 	ctx := context.Background()
-	path, err := service.UploadProfileImage(ctx, "user000", upload)
+	uploaded, err := service.UploadProfileImage(ctx, "user000", upload)
 
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "failed to read file")
-	assert.Empty(t, path)
+	assert.Nil(t, uploaded)
 }
 
-func TestImageService_UploadProfileImage_PathUniqueness(t *testing.T) {
+func TestImageService_UploadProfileImage_SameContentSamePath(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockStorage := mocks.NewMockStorage(ctrl)
-	
-	paths := make(map[string]bool)
-	
-	// Expect 3 different uploads with unique paths
-	mockStorage.EXPECT().
-		Put(gomock.Any(), gomock.Any(), gomock.Any()).
-		Times(3).
-		DoAndReturn(func(ctx context.Context, data []byte, path string) error {
-			// Check that each path is unique
-			if paths[path] {
-				t.Errorf("Duplicate path generated: %s", path)
-			}
-			paths[path] = true
-			return nil
-		})
-
-	service := NewImageService(mockStorage)
+	mockRepository := mocks.NewMockImagesRepository(ctrl)
+	content := encodeTestImage(t, "png", 20, 20)
+
+	// Only the first upload creates the image, the rest reuse it
+	gomock.InOrder(
+		mockRepository.EXPECT().Acquire(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(true, nil),
+		mockRepository.EXPECT().Acquire(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(2).Return(false, nil),
+	)
+	mockStorage.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(3).Return(nil)
+	mockStorage.EXPECT().Stat(gomock.Any(), gomock.Any()).Times(2).Return(&storage.ObjectInfo{}, nil)
+
+	service := NewImageService(mockStorage, mockRepository)
 	ctx := context.Background()
 
-	// Upload the same file 3 times
+	paths := make(map[string]bool)
 	for i := 0; i < 3; i++ {
 		upload := graphql.Upload{
-			File:     strings.NewReader("content"),
-			Filename: "test.jpg",
+			File:     bytes.NewReader(content),
+			Filename: "test.png",
 		}
-		
-		path, err := service.UploadProfileImage(ctx, "user123", upload)
+
+		uploaded, err := service.UploadProfileImage(ctx, fmt.Sprintf("user%d", i), upload)
 		require.NoError(t, err)
-		assert.NotEmpty(t, path)
+		paths[uploaded.Path] = true
 	}
 
-	// Verify we got 3 unique paths
-	assert.Len(t, paths, 3)
+	assert.Len(t, paths, 1)
 }
 
 func TestImageService_ValidateExtensions(t *testing.T) {
@@ -393,29 +535,33 @@ func TestImageService_ValidateExtensions(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockStorage := mocks.NewMockStorage(ctrl)
-	
-	// For valid extensions, expect Put to be called
-	for range validExtensions {
-		mockStorage.EXPECT().
-			Put(gomock.Any(), gomock.Any(), gomock.Any()).
-			Return(nil).
-			AnyTimes()
-	}
+	mockRepository := mocks.NewMockImagesRepository(ctrl)
 
-	service := NewImageService(mockStorage)
+	// For valid extensions, expect the image to be stored
+	mockRepository.EXPECT().
+		Acquire(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(true, nil).
+		AnyTimes()
+	mockStorage.EXPECT().
+		Put(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(nil).
+		AnyTimes()
+
+	service := NewImageService(mockStorage, mockRepository)
 	ctx := context.Background()
+	content := encodeTestImage(t, "gif", 10, 10)
 
 	// Test valid extensions
 	for _, ext := range validExtensions {
 		t.Run(fmt.Sprintf("valid_extension_%s", ext), func(t *testing.T) {
 			upload := graphql.Upload{
-				File:     strings.NewReader("content"),
+				File:     bytes.NewReader(content),
 				Filename: "file" + ext,
 			}
-			
-			path, err := service.UploadProfileImage(ctx, "user", upload)
+
+			uploaded, err := service.UploadProfileImage(ctx, "user", upload)
 			require.NoError(t, err)
-			assert.NotEmpty(t, path)
+			assert.NotEmpty(t, uploaded.Path)
 		})
 	}
 
@@ -426,11 +572,11 @@ func TestImageService_ValidateExtensions(t *testing.T) {
 				File:     strings.NewReader("content"),
 				Filename: "file" + ext,
 			}
-			
-			path, err := service.UploadProfileImage(ctx, "user", upload)
+
+			uploaded, err := service.UploadProfileImage(ctx, "user", upload)
 			require.Error(t, err)
 			assert.Contains(t, err.Error(), "invalid file extension")
-			assert.Empty(t, path)
+			assert.Nil(t, uploaded)
 		})
 	}
 }
@@ -460,4 +606,37 @@ func newReadSeeker(content string) io.ReadSeeker {
 // Helper to create a ReadSeeker from bytes
 func newByteReadSeeker(content []byte) io.ReadSeeker {
 	return bytes.NewReader(content)
-}
\ No newline at end of file
+}
+
+// encodeTestImage renders a solid image of the given size in the requested format
+func encodeTestImage(t *testing.T, format string, width, height int) []byte {
+	t.Helper()
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for x := 0; x < width; x++ {
+		for y := 0; y < height; y++ {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 128, A: 255})
+		}
+	}
+
+	var buf bytes.Buffer
+	var err error
+	switch format {
+	case "jpeg":
+		err = jpeg.Encode(&buf, img, nil)
+	case "gif":
+		err = gif.Encode(&buf, img, nil)
+	default:
+		err = png.Encode(&buf, img)
+	}
+	require.NoError(t, err)
+
+	return buf.Bytes()
+}
+
+// contentHashOf returns the hex SHA-256 used as the storage key for data
+func contentHashOf(data []byte) string {
+	sum := sha256.Sum256(data)
+
+	return hex.EncodeToString(sum[:])
+}
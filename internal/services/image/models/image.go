@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// Image is a content-addressed object in storage, shared by every user whose profile points at it.
+type Image struct {
+	Hash      string    `json:"hash" gorm:"primaryKey"`
+	Path      string    `json:"path"`
+	Size      int64     `json:"size"`
+	RefCount  int       `json:"ref_count"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
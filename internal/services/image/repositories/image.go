@@ -0,0 +1,186 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/weeb-vip/user-service/internal/db"
+	"github.com/weeb-vip/user-service/internal/services/image/models"
+	"github.com/weeb-vip/user-service/metrics"
+	"github.com/weeb-vip/user-service/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// OnLastReference is invoked while the image row is still locked, so storage can be cleaned up
+// before a concurrent upload of the same content is able to claim the hash again.
+type OnLastReference func(ctx context.Context, image *models.Image) error
+
+type ImagesRepository interface {
+	// Acquire adds a reference to the image, creating the row when it does not exist yet.
+	// created reports whether this call inserted the row.
+	Acquire(ctx context.Context, hash string, path string, size int64) (created bool, err error)
+	// Release drops a reference to the image and calls onLast when none remain.
+	Release(ctx context.Context, hash string, onLast OnLastReference) error
+	GetImageByHash(ctx context.Context, hash string) (*models.Image, error)
+}
+
+type imageRepository struct {
+	DBService db.DB
+}
+
+var imageRepositorySingleton ImagesRepository // nolint
+
+func NewImagesRepository() ImagesRepository {
+	dbService := db.GetDBService()
+
+	return &imageRepository{
+		DBService: dbService,
+	}
+}
+
+func (repository *imageRepository) Acquire(ctx context.Context, hash string, path string, size int64) (bool, error) {
+	tracer := tracing.GetTracer(ctx)
+	ctx, span := tracer.Start(ctx, "repository.AcquireImage",
+		trace.WithAttributes(
+			attribute.String("image.hash", hash),
+			attribute.String("table", "images"),
+			attribute.String("operation", "upsert"),
+		),
+		tracing.GetEnvironmentAttribute(),
+	)
+	defer span.End()
+
+	start := time.Now()
+	database := repository.DBService.GetDB()
+
+	image := models.Image{
+		Hash:     hash,
+		Path:     path,
+		Size:     size,
+		RefCount: 1,
+	}
+	result := database.WithContext(ctx).Clauses(clause.OnConflict{
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"ref_count":  gorm.Expr("ref_count + 1"),
+			"updated_at": time.Now(),
+		}),
+	}).Create(&image)
+	err := result.Error
+
+	// Record database metrics
+	duration := float64(time.Since(start).Nanoseconds()) / float64(time.Millisecond)
+	appMetrics := metrics.GetAppMetrics()
+	metricResult := metrics.Success
+	if err != nil {
+		metricResult = metrics.Error
+	}
+	appMetrics.DatabaseMetric(duration, "images", "upsert", metricResult)
+
+	if err != nil {
+		return false, err
+	}
+
+	// MySQL reports one affected row for an insert and two for ON DUPLICATE KEY UPDATE.
+	return result.RowsAffected == 1, nil
+}
+
+func (repository *imageRepository) Release(ctx context.Context, hash string, onLast OnLastReference) error {
+	tracer := tracing.GetTracer(ctx)
+	ctx, span := tracer.Start(ctx, "repository.ReleaseImage",
+		trace.WithAttributes(
+			attribute.String("image.hash", hash),
+			attribute.String("table", "images"),
+			attribute.String("operation", "update"),
+		),
+		tracing.GetEnvironmentAttribute(),
+	)
+	defer span.End()
+
+	start := time.Now()
+	database := repository.DBService.GetDB()
+
+	err := database.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var image models.Image
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("hash = ?", hash).First(&image).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if image.RefCount > 1 {
+			return tx.Model(&image).Update("ref_count", gorm.Expr("ref_count - 1")).Error
+		}
+
+		if onLast != nil {
+			if err := onLast(ctx, &image); err != nil {
+				return err
+			}
+		}
+
+		return tx.Delete(&image).Error
+	})
+
+	// Record database metrics
+	duration := float64(time.Since(start).Nanoseconds()) / float64(time.Millisecond)
+	appMetrics := metrics.GetAppMetrics()
+	result := metrics.Success
+	if err != nil {
+		result = metrics.Error
+	}
+	appMetrics.DatabaseMetric(duration, "images", "update", result)
+
+	return err
+}
+
+func (repository *imageRepository) GetImageByHash(ctx context.Context, hash string) (*models.Image, error) {
+	tracer := tracing.GetTracer(ctx)
+	ctx, span := tracer.Start(ctx, "repository.GetImageByHash",
+		trace.WithAttributes(
+			attribute.String("image.hash", hash),
+			attribute.String("table", "images"),
+			attribute.String("operation", "select"),
+		),
+		tracing.GetEnvironmentAttribute(),
+	)
+	defer span.End()
+
+	start := time.Now()
+	database := repository.DBService.GetDB()
+
+	var image models.Image
+
+	err := database.WithContext(ctx).Where("hash = ?", hash).First(&image).Error
+
+	// Record database metrics
+	duration := float64(time.Since(start).Nanoseconds()) / float64(time.Millisecond)
+	appMetrics := metrics.GetAppMetrics()
+	result := metrics.Success
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		result = metrics.Error
+	}
+	appMetrics.DatabaseMetric(duration, "images", "select", result)
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &image, nil
+}
+
+func GetImagesRepository() ImagesRepository {
+	if imageRepositorySingleton == nil {
+		imageRepositorySingleton = NewImagesRepository()
+	}
+
+	return imageRepositorySingleton
+}
@@ -0,0 +1,22 @@
+package moderation
+
+const (
+	ModerationErrorInternalError     ErrorCode = "INTERNAL_ERROR"            // nolint
+	ModerationErrorNotFound          ErrorCode = "PROFILE_IMAGE_NOT_FOUND"   // nolint
+	ModerationErrorInvalidTransition ErrorCode = "INVALID_STATUS_TRANSITION" // nolint
+)
+
+type ErrorCode string
+
+type Error struct {
+	Code    ErrorCode
+	Message string
+}
+
+func (c ErrorCode) String() string {
+	return string(c)
+}
+
+func (e Error) Error() string {
+	return e.Message
+}
@@ -0,0 +1,36 @@
+package moderation
+
+import (
+	"context"
+
+	"github.com/weeb-vip/user-service/internal/services/image"
+	"github.com/weeb-vip/user-service/internal/services/moderation/models"
+)
+
+type Moderation interface {
+	Submit(ctx context.Context, userID string, uploaded *image.UploadedImage) (*models.ProfileImage, error)
+	Approve(ctx context.Context, id string, reviewerID string) (*models.ProfileImage, error)
+	Reject(ctx context.Context, id string, reviewerID string, reason *string) (*models.ProfileImage, error)
+	ListPending(ctx context.Context, limit int, offset int) ([]*models.ProfileImage, error)
+	GetPendingForUser(ctx context.Context, userID string) (*models.ProfileImage, error)
+}
+
+// Moderator decides what happens to a freshly uploaded avatar.
+type Moderator interface {
+	Review(ctx context.Context, submission Submission) (Verdict, error)
+}
+
+type Submission struct {
+	UserID string
+	Path   string
+	Hash   string
+	Size   int64
+	Width  int
+	Height int
+}
+
+// Verdict is a moderator's decision. A pending status leaves the image in the queue for an admin.
+type Verdict struct {
+	Status models.Status
+	Reason string
+}
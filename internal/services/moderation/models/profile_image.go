@@ -0,0 +1,47 @@
+package models
+
+import (
+	"time"
+
+	"github.com/weeb-vip/user-service/internal/db"
+)
+
+const (
+	StatusPending  Status = "pending"
+	StatusApproved Status = "approved"
+	StatusRejected Status = "rejected"
+)
+
+type Status string
+
+// transitions lists the statuses each status may move to. Rejected is terminal; approved images
+// can still be taken down.
+var transitions = map[Status][]Status{ // nolint
+	StatusPending:  {StatusApproved, StatusRejected},
+	StatusApproved: {StatusRejected},
+}
+
+func (s Status) String() string {
+	return string(s)
+}
+
+func (s Status) CanTransitionTo(next Status) bool {
+	for _, allowed := range transitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ProfileImage is an avatar submitted by a user, waiting for or past moderation.
+type ProfileImage struct {
+	db.BaseModel
+	UserID     string     `json:"user_id"`
+	Path       string     `json:"path"`
+	Status     Status     `json:"status"`
+	Reason     *string    `json:"reason"`
+	ReviewedBy *string    `json:"reviewed_by"`
+	ReviewedAt *time.Time `json:"reviewed_at"`
+}
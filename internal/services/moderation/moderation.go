@@ -0,0 +1,369 @@
+package moderation
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/weeb-vip/user-service/internal/logger"
+	"github.com/weeb-vip/user-service/internal/services/image"
+	"github.com/weeb-vip/user-service/internal/services/moderation/models"
+	"github.com/weeb-vip/user-service/internal/services/moderation/repositories"
+	"github.com/weeb-vip/user-service/internal/services/users"
+	"github.com/weeb-vip/user-service/metrics"
+	"github.com/weeb-vip/user-service/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+	supersededNote  = "superseded by a newer upload"
+)
+
+type moderationService struct {
+	profileImagesRepository repositories.ProfileImagesRepository
+	userService             users.User
+	imageService            *image.ImageService
+	moderator               Moderator
+}
+
+func NewModerationService(
+	profileImagesRepository repositories.ProfileImagesRepository,
+	userService users.User,
+	imageService *image.ImageService,
+	moderator Moderator,
+) Moderation {
+	return &moderationService{
+		profileImagesRepository: profileImagesRepository,
+		userService:             userService,
+		imageService:            imageService,
+		moderator:               moderator,
+	}
+}
+
+func (service *moderationService) Submit(
+	ctx context.Context,
+	userID string,
+	uploaded *image.UploadedImage,
+) (*models.ProfileImage, error) {
+	tracer := tracing.GetTracer(ctx)
+	ctx, span := tracer.Start(ctx, "service.SubmitProfileImage",
+		trace.WithAttributes(
+			attribute.String("user.id", userID),
+			attribute.String("image.path", uploaded.Path),
+			attribute.String("service", "moderation"),
+			attribute.String("method", "Submit"),
+		),
+		tracing.GetEnvironmentAttribute(),
+	)
+	defer span.End()
+
+	startTime := time.Now()
+	log := logger.FromCtx(ctx)
+
+	// A newer upload replaces whatever the user still has waiting in the queue
+	pending, err := service.profileImagesRepository.ListUserProfileImagesByStatus(ctx, userID, models.StatusPending)
+	if err != nil {
+		service.recordMetric(startTime, "Submit", metrics.Error)
+		return nil, &Error{Code: ModerationErrorInternalError, Message: "database error"}
+	}
+	for _, previous := range pending {
+		reason := supersededNote
+		_, err = service.transition(ctx, previous, models.StatusRejected, nil, &reason)
+		if err != nil {
+			log.Warn().Err(err).Str("profile_image.id", previous.ID).Msg("failed to supersede pending profile image")
+		}
+	}
+
+	profileImage, err := service.profileImagesRepository.AddProfileImage(ctx, userID, uploaded.Path)
+	if err != nil {
+		service.recordMetric(startTime, "Submit", metrics.Error)
+		return nil, &Error{Code: ModerationErrorInternalError, Message: "database error"}
+	}
+
+	verdict, err := service.moderator.Review(ctx, Submission{
+		UserID: userID,
+		Path:   uploaded.Path,
+		Hash:   uploaded.Hash,
+		Size:   uploaded.Size,
+		Width:  uploaded.Width,
+		Height: uploaded.Height,
+	})
+	if err != nil {
+		// Leave it for an admin rather than failing the upload
+		log.Error().Err(err).Str("profile_image.id", profileImage.ID).Msg("moderator failed, image left pending")
+		service.recordMetric(startTime, "Submit", metrics.Success)
+		return profileImage, nil
+	}
+
+	span.SetAttributes(attribute.String("moderation.verdict", verdict.Status.String()))
+
+	var reason *string
+	if verdict.Reason != "" {
+		reason = &verdict.Reason
+	}
+
+	service.recordMetric(startTime, "Submit", metrics.Success)
+
+	if verdict.Status == models.StatusPending {
+		return profileImage, nil
+	}
+
+	result, err := service.transition(ctx, profileImage, verdict.Status, nil, reason)
+	if err != nil {
+		// The submission owns the uploaded image now, so keep it queued instead of failing the upload
+		log.Error().Err(err).Str("profile_image.id", profileImage.ID).Msg("failed to apply moderation verdict, image left pending")
+		return profileImage, nil
+	}
+
+	return result, nil
+}
+
+func (service *moderationService) Approve(ctx context.Context, id string, reviewerID string) (*models.ProfileImage, error) {
+	return service.review(ctx, "Approve", id, models.StatusApproved, reviewerID, nil)
+}
+
+func (service *moderationService) Reject(
+	ctx context.Context,
+	id string,
+	reviewerID string,
+	reason *string,
+) (*models.ProfileImage, error) {
+	return service.review(ctx, "Reject", id, models.StatusRejected, reviewerID, reason)
+}
+
+func (service *moderationService) ListPending(ctx context.Context, limit int, offset int) ([]*models.ProfileImage, error) {
+	tracer := tracing.GetTracer(ctx)
+	ctx, span := tracer.Start(ctx, "service.ListPendingProfileImages",
+		trace.WithAttributes(
+			attribute.String("service", "moderation"),
+			attribute.String("method", "ListPending"),
+		),
+		tracing.GetEnvironmentAttribute(),
+	)
+	defer span.End()
+
+	startTime := time.Now()
+
+	if limit <= 0 {
+		limit = defaultPageSize
+	}
+	if limit > maxPageSize {
+		limit = maxPageSize
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	profileImages, err := service.profileImagesRepository.ListProfileImagesByStatus(ctx, models.StatusPending, limit, offset)
+	if err != nil {
+		service.recordMetric(startTime, "ListPending", metrics.Error)
+		return nil, &Error{Code: ModerationErrorInternalError, Message: "database error"}
+	}
+
+	service.recordMetric(startTime, "ListPending", metrics.Success)
+
+	return profileImages, nil
+}
+
+func (service *moderationService) GetPendingForUser(ctx context.Context, userID string) (*models.ProfileImage, error) {
+	tracer := tracing.GetTracer(ctx)
+	ctx, span := tracer.Start(ctx, "service.GetPendingProfileImage",
+		trace.WithAttributes(
+			attribute.String("user.id", userID),
+			attribute.String("service", "moderation"),
+			attribute.String("method", "GetPendingForUser"),
+		),
+		tracing.GetEnvironmentAttribute(),
+	)
+	defer span.End()
+
+	startTime := time.Now()
+
+	pending, err := service.profileImagesRepository.ListUserProfileImagesByStatus(ctx, userID, models.StatusPending)
+	if err != nil {
+		service.recordMetric(startTime, "GetPendingForUser", metrics.Error)
+		return nil, &Error{Code: ModerationErrorInternalError, Message: "database error"}
+	}
+
+	service.recordMetric(startTime, "GetPendingForUser", metrics.Success)
+
+	if len(pending) == 0 {
+		return nil, nil
+	}
+
+	return pending[0], nil
+}
+
+func (service *moderationService) review(
+	ctx context.Context,
+	method string,
+	id string,
+	to models.Status,
+	reviewerID string,
+	reason *string,
+) (*models.ProfileImage, error) {
+	tracer := tracing.GetTracer(ctx)
+	ctx, span := tracer.Start(ctx, "service."+method+"ProfileImage",
+		trace.WithAttributes(
+			attribute.String("profile_image.id", id),
+			attribute.String("reviewer.id", reviewerID),
+			attribute.String("service", "moderation"),
+			attribute.String("method", method),
+		),
+		tracing.GetEnvironmentAttribute(),
+	)
+	defer span.End()
+
+	startTime := time.Now()
+
+	profileImage, err := service.profileImagesRepository.GetProfileImageByID(ctx, id)
+	if err != nil {
+		service.recordMetric(startTime, method, metrics.Error)
+		return nil, &Error{Code: ModerationErrorInternalError, Message: "database error"}
+	}
+	if profileImage == nil {
+		service.recordMetric(startTime, method, metrics.Error)
+		return nil, &Error{Code: ModerationErrorNotFound, Message: "profile image not found"}
+	}
+
+	result, err := service.transition(ctx, profileImage, to, &reviewerID, reason)
+
+	metricResult := metrics.Success
+	if err != nil {
+		metricResult = metrics.Error
+	}
+	service.recordMetric(startTime, method, metricResult)
+
+	return result, err
+}
+
+// transition moves a profile image through the state machine and applies the side effects of
+// the new status to the user's avatar and to storage.
+func (service *moderationService) transition(
+	ctx context.Context,
+	profileImage *models.ProfileImage,
+	to models.Status,
+	reviewerID *string,
+	reason *string,
+) (*models.ProfileImage, error) {
+	if !profileImage.Status.CanTransitionTo(to) {
+		return nil, &Error{
+			Code:    ModerationErrorInvalidTransition,
+			Message: "cannot move profile image from " + profileImage.Status.String() + " to " + to.String(),
+		}
+	}
+
+	// Find out whether this image is the live avatar before its status changes
+	wasCurrent := false
+	if profileImage.Status == models.StatusApproved {
+		current, err := service.isCurrentAvatar(ctx, profileImage)
+		if err != nil {
+			return nil, &Error{Code: ModerationErrorInternalError, Message: "database error"}
+		}
+		wasCurrent = current
+	}
+
+	updated, err := service.profileImagesRepository.UpdateStatus(ctx, profileImage.ID, profileImage.Status, to, reviewerID, reason)
+	if errors.Is(err, repositories.ErrStatusChanged) {
+		return nil, &Error{Code: ModerationErrorInvalidTransition, Message: "profile image was already reviewed"}
+	}
+	if err != nil {
+		return nil, &Error{Code: ModerationErrorInternalError, Message: "database error"}
+	}
+
+	switch to {
+	case models.StatusApproved:
+		err = service.applyAvatar(ctx, updated)
+		if err != nil {
+			// Put it back in the queue so the approval can be retried
+			_, _ = service.profileImagesRepository.UpdateStatus(ctx, updated.ID, models.StatusApproved, models.StatusPending, nil, nil)
+			return nil, err
+		}
+	case models.StatusRejected:
+		err = service.withdraw(ctx, profileImage, wasCurrent)
+		if err != nil {
+			return nil, err
+		}
+	case models.StatusPending:
+	}
+
+	return updated, nil
+}
+
+// applyAvatar makes the approved image the user's public avatar and releases the one it replaces.
+func (service *moderationService) applyAvatar(ctx context.Context, profileImage *models.ProfileImage) error {
+	log := logger.FromCtx(ctx)
+
+	user, err := service.userService.GetUserDetails(ctx, profileImage.UserID)
+	if err != nil {
+		return err
+	}
+
+	_, err = service.userService.UpdateProfileImageURL(ctx, profileImage.UserID, profileImage.Path)
+	if err != nil {
+		return err
+	}
+
+	// Every submission holds its own reference, so the old one is released even if it has the same content
+	if user.ProfileImageURL != nil && *user.ProfileImageURL != "" {
+		err = service.imageService.DeleteProfileImage(ctx, *user.ProfileImageURL)
+		if err != nil {
+			log.Warn().Err(err).Str("image.path", *user.ProfileImageURL).Msg("failed to release replaced profile image")
+		}
+	}
+
+	return nil
+}
+
+// withdraw releases a rejected image, resetting the avatar to the default if it was live.
+func (service *moderationService) withdraw(ctx context.Context, profileImage *models.ProfileImage, wasCurrent bool) error {
+	log := logger.FromCtx(ctx)
+
+	// Approved images that were since replaced have already been released
+	if profileImage.Status == models.StatusApproved && !wasCurrent {
+		return nil
+	}
+
+	if wasCurrent {
+		_, err := service.userService.UpdateProfileImageURL(ctx, profileImage.UserID, "")
+		if err != nil {
+			return err
+		}
+	}
+
+	err := service.imageService.DeleteProfileImage(ctx, profileImage.Path)
+	if err != nil {
+		log.Warn().Err(err).Str("image.path", profileImage.Path).Msg("failed to release rejected profile image")
+	}
+
+	return nil
+}
+
+func (service *moderationService) isCurrentAvatar(ctx context.Context, profileImage *models.ProfileImage) (bool, error) {
+	approved, err := service.profileImagesRepository.ListUserProfileImagesByStatus(ctx, profileImage.UserID, models.StatusApproved)
+	if err != nil {
+		return false, err
+	}
+	if len(approved) == 0 || approved[0].ID != profileImage.ID {
+		return false, nil
+	}
+
+	user, err := service.userService.GetUserDetails(ctx, profileImage.UserID)
+	if err != nil {
+		return false, err
+	}
+
+	return user.ProfileImageURL != nil && *user.ProfileImageURL == profileImage.Path, nil
+}
+
+func (service *moderationService) recordMetric(startTime time.Time, method string, result string) {
+	metrics.GetAppMetrics().ServiceMetric(
+		float64(time.Since(startTime).Milliseconds()),
+		"moderation",
+		method,
+		result,
+	)
+}
@@ -0,0 +1,234 @@
+package moderation_test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/weeb-vip/user-service/internal/db"
+	"github.com/weeb-vip/user-service/internal/services/image"
+	"github.com/weeb-vip/user-service/internal/services/moderation"
+	"github.com/weeb-vip/user-service/internal/services/moderation/models"
+	"github.com/weeb-vip/user-service/internal/services/moderation/repositories"
+	"github.com/weeb-vip/user-service/internal/services/users"
+	usersModels "github.com/weeb-vip/user-service/internal/services/users/models"
+	"github.com/weeb-vip/user-service/internal/storage"
+	"github.com/weeb-vip/user-service/mocks"
+	"go.uber.org/mock/gomock"
+)
+
+const (
+	userID     = "user_1"
+	reviewerID = "user_admin"
+)
+
+// fakeUsers stands in for the users service, which is mocked with a different gomock.
+type fakeUsers struct {
+	users.User
+	user      usersModels.User
+	imageURLs []string
+}
+
+func (f *fakeUsers) GetUserDetails(_ context.Context, _ string) (*usersModels.User, error) {
+	user := f.user
+
+	return &user, nil
+}
+
+func (f *fakeUsers) UpdateProfileImageURL(_ context.Context, _ string, profileImageURL string) (*usersModels.User, error) {
+	f.imageURLs = append(f.imageURLs, profileImageURL)
+	f.user.ProfileImageURL = &profileImageURL
+	user := f.user
+
+	return &user, nil
+}
+
+type stubModerator struct {
+	verdict moderation.Verdict
+	err     error
+}
+
+func (m stubModerator) Review(_ context.Context, _ moderation.Submission) (moderation.Verdict, error) {
+	return m.verdict, m.err
+}
+
+type moderationFixture struct {
+	ctrl       *gomock.Controller
+	repository *mocks.MockProfileImagesRepository
+	store      *mocks.MockStorage
+	users      *fakeUsers
+}
+
+func newModerationService(t *testing.T, moderator moderation.Moderator) (moderation.Moderation, *moderationFixture) {
+	ctrl := gomock.NewController(t)
+	fixture := &moderationFixture{
+		ctrl:       ctrl,
+		repository: mocks.NewMockProfileImagesRepository(ctrl),
+		store:      mocks.NewMockStorage(ctrl),
+		users:      &fakeUsers{user: usersModels.User{BaseModel: db.BaseModel{ID: userID}}},
+	}
+	imageService := image.NewImageService(fixture.store, mocks.NewMockImagesRepository(ctrl))
+
+	return moderation.NewModerationService(fixture.repository, fixture.users, imageService, moderator), fixture
+}
+
+// expectReleased expects the image at path to be deleted from storage.
+func (f *moderationFixture) expectReleased(path string) {
+	iterator := mocks.NewMockObjectIterator(f.ctrl)
+	f.store.EXPECT().List(gomock.Any(), strings.TrimSuffix(path, filepath.Ext(path))).Return(iterator)
+	gomock.InOrder(
+		iterator.EXPECT().Next(gomock.Any()).Return([]storage.ObjectInfo{{Path: path}}, nil),
+		iterator.EXPECT().Next(gomock.Any()).Return(nil, storage.ErrIteratorDone),
+	)
+	f.store.EXPECT().Delete(gomock.Any(), path).Return(nil)
+}
+
+func profileImage(id string, path string, status models.Status) *models.ProfileImage {
+	return &models.ProfileImage{BaseModel: db.BaseModel{ID: id}, UserID: userID, Path: path, Status: status}
+}
+
+func withStatus(img *models.ProfileImage, status models.Status) *models.ProfileImage {
+	updated := *img
+	updated.Status = status
+
+	return &updated
+}
+
+func TestModerationService_Submit(t *testing.T) {
+	uploaded := &image.UploadedImage{Path: "profile_images/user_1/new.png", Width: 64, Height: 64}
+
+	t.Run("approved upload replaces the avatar", func(t *testing.T) {
+		service, fixture := newModerationService(t, moderation.NewAllowAllModerator())
+		oldPath := "profile_images/user_1/old.png"
+		fixture.users.user.ProfileImageURL = &oldPath
+		submitted := profileImage("img_2", uploaded.Path, models.StatusPending)
+
+		fixture.repository.EXPECT().ListUserProfileImagesByStatus(gomock.Any(), userID, models.StatusPending).Return(nil, nil)
+		fixture.repository.EXPECT().AddProfileImage(gomock.Any(), userID, uploaded.Path).Return(submitted, nil)
+		fixture.repository.EXPECT().UpdateStatus(gomock.Any(), "img_2", models.StatusPending, models.StatusApproved, nil, nil).
+			Return(withStatus(submitted, models.StatusApproved), nil)
+		fixture.expectReleased(oldPath)
+
+		result, err := service.Submit(context.Background(), userID, uploaded)
+		require.NoError(t, err)
+		assert.Equal(t, models.StatusApproved, result.Status)
+		assert.Equal(t, []string{uploaded.Path}, fixture.users.imageURLs)
+	})
+
+	t.Run("supersedes the pending upload", func(t *testing.T) {
+		held := moderation.Verdict{Status: models.StatusPending}
+		service, fixture := newModerationService(t, stubModerator{verdict: held})
+		previous := profileImage("img_1", "profile_images/user_1/previous.png", models.StatusPending)
+		submitted := profileImage("img_2", uploaded.Path, models.StatusPending)
+
+		fixture.repository.EXPECT().ListUserProfileImagesByStatus(gomock.Any(), userID, models.StatusPending).
+			Return([]*models.ProfileImage{previous}, nil)
+		fixture.repository.EXPECT().
+			UpdateStatus(gomock.Any(), "img_1", models.StatusPending, models.StatusRejected, nil, gomock.Any()).
+			Return(withStatus(previous, models.StatusRejected), nil)
+		fixture.expectReleased(previous.Path)
+		fixture.repository.EXPECT().AddProfileImage(gomock.Any(), userID, uploaded.Path).Return(submitted, nil)
+
+		result, err := service.Submit(context.Background(), userID, uploaded)
+		require.NoError(t, err)
+		assert.Equal(t, models.StatusPending, result.Status)
+		assert.Empty(t, fixture.users.imageURLs)
+	})
+
+	t.Run("moderator failure leaves the upload pending", func(t *testing.T) {
+		service, fixture := newModerationService(t, stubModerator{err: errors.New("classifier down")})
+		submitted := profileImage("img_2", uploaded.Path, models.StatusPending)
+
+		fixture.repository.EXPECT().ListUserProfileImagesByStatus(gomock.Any(), userID, models.StatusPending).Return(nil, nil)
+		fixture.repository.EXPECT().AddProfileImage(gomock.Any(), userID, uploaded.Path).Return(submitted, nil)
+
+		result, err := service.Submit(context.Background(), userID, uploaded)
+		require.NoError(t, err)
+		assert.Equal(t, submitted, result)
+	})
+}
+
+func TestModerationService_Approve(t *testing.T) {
+	t.Run("unknown image", func(t *testing.T) {
+		service, fixture := newModerationService(t, moderation.NewAllowAllModerator())
+		fixture.repository.EXPECT().GetProfileImageByID(gomock.Any(), "img_1").Return(nil, nil)
+
+		_, err := service.Approve(context.Background(), "img_1", reviewerID)
+		assertErrorCode(t, err, moderation.ModerationErrorNotFound)
+	})
+
+	t.Run("rejected images stay rejected", func(t *testing.T) {
+		service, fixture := newModerationService(t, moderation.NewAllowAllModerator())
+		fixture.repository.EXPECT().GetProfileImageByID(gomock.Any(), "img_1").
+			Return(profileImage("img_1", "profile_images/user_1/a.png", models.StatusRejected), nil)
+
+		_, err := service.Approve(context.Background(), "img_1", reviewerID)
+		assertErrorCode(t, err, moderation.ModerationErrorInvalidTransition)
+	})
+
+	t.Run("image reviewed concurrently", func(t *testing.T) {
+		service, fixture := newModerationService(t, moderation.NewAllowAllModerator())
+		fixture.repository.EXPECT().GetProfileImageByID(gomock.Any(), "img_1").
+			Return(profileImage("img_1", "profile_images/user_1/a.png", models.StatusPending), nil)
+		fixture.repository.EXPECT().
+			UpdateStatus(gomock.Any(), "img_1", models.StatusPending, models.StatusApproved, gomock.Any(), nil).
+			Return(nil, repositories.ErrStatusChanged)
+
+		_, err := service.Approve(context.Background(), "img_1", reviewerID)
+		assertErrorCode(t, err, moderation.ModerationErrorInvalidTransition)
+		assert.Empty(t, fixture.users.imageURLs)
+	})
+}
+
+func TestModerationService_Reject(t *testing.T) {
+	reason := "not appropriate"
+
+	t.Run("taking down the live avatar resets it", func(t *testing.T) {
+		service, fixture := newModerationService(t, moderation.NewAllowAllModerator())
+		live := profileImage("img_1", "profile_images/user_1/a.png", models.StatusApproved)
+		fixture.users.user.ProfileImageURL = &live.Path
+
+		fixture.repository.EXPECT().GetProfileImageByID(gomock.Any(), "img_1").Return(live, nil)
+		fixture.repository.EXPECT().ListUserProfileImagesByStatus(gomock.Any(), userID, models.StatusApproved).
+			Return([]*models.ProfileImage{live}, nil)
+		fixture.repository.EXPECT().
+			UpdateStatus(gomock.Any(), "img_1", models.StatusApproved, models.StatusRejected, gomock.Any(), &reason).
+			Return(withStatus(live, models.StatusRejected), nil)
+		fixture.expectReleased(live.Path)
+
+		result, err := service.Reject(context.Background(), "img_1", reviewerID, &reason)
+		require.NoError(t, err)
+		assert.Equal(t, models.StatusRejected, result.Status)
+		assert.Equal(t, []string{""}, fixture.users.imageURLs)
+	})
+
+	t.Run("taking down a replaced avatar leaves the current one", func(t *testing.T) {
+		service, fixture := newModerationService(t, moderation.NewAllowAllModerator())
+		replaced := profileImage("img_1", "profile_images/user_1/a.png", models.StatusApproved)
+		current := profileImage("img_2", "profile_images/user_1/b.png", models.StatusApproved)
+		fixture.users.user.ProfileImageURL = &current.Path
+
+		fixture.repository.EXPECT().GetProfileImageByID(gomock.Any(), "img_1").Return(replaced, nil)
+		fixture.repository.EXPECT().ListUserProfileImagesByStatus(gomock.Any(), userID, models.StatusApproved).
+			Return([]*models.ProfileImage{current, replaced}, nil)
+		fixture.repository.EXPECT().
+			UpdateStatus(gomock.Any(), "img_1", models.StatusApproved, models.StatusRejected, gomock.Any(), &reason).
+			Return(withStatus(replaced, models.StatusRejected), nil)
+
+		_, err := service.Reject(context.Background(), "img_1", reviewerID, &reason)
+		require.NoError(t, err)
+		assert.Empty(t, fixture.users.imageURLs)
+	})
+}
+
+func assertErrorCode(t *testing.T, err error, code moderation.ErrorCode) {
+	t.Helper()
+
+	var moderationErr *moderation.Error
+	require.True(t, errors.As(err, &moderationErr), "expected moderation error, got %v", err)
+	assert.Equal(t, code, moderationErr.Code)
+}
@@ -0,0 +1,97 @@
+package moderation
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/weeb-vip/user-service/config"
+	"github.com/weeb-vip/user-service/internal/services/moderation/models"
+)
+
+const (
+	ModeratorAllowAll = "allow-all"
+	ModeratorRules    = "rules"
+)
+
+type allowAllModerator struct{}
+
+// NewAllowAllModerator approves every image as soon as it is uploaded.
+func NewAllowAllModerator() Moderator {
+	return allowAllModerator{}
+}
+
+func (allowAllModerator) Review(_ context.Context, _ Submission) (Verdict, error) {
+	return Verdict{Status: models.StatusApproved}, nil
+}
+
+type rulesModerator struct {
+	requireReview bool
+	maxBytes      int64
+	minDimension  int
+	maxAspect     float64
+	blockedHashes map[string]bool
+}
+
+// NewRulesModerator rejects images breaking any of the configured rules. Images that pass are
+// approved, or held for an admin when RequireReview is set.
+func NewRulesModerator(cfg config.ModerationConfig) Moderator {
+	blockedHashes := map[string]bool{}
+	for _, hash := range strings.Split(cfg.BlockedHashes, ",") {
+		hash = strings.ToLower(strings.TrimSpace(hash))
+		if hash != "" {
+			blockedHashes[hash] = true
+		}
+	}
+
+	return rulesModerator{
+		requireReview: cfg.RequireReview,
+		maxBytes:      cfg.MaxBytes,
+		minDimension:  cfg.MinDimension,
+		maxAspect:     cfg.MaxAspect,
+		blockedHashes: blockedHashes,
+	}
+}
+
+func (m rulesModerator) Review(_ context.Context, submission Submission) (Verdict, error) {
+	if m.blockedHashes[strings.ToLower(submission.Hash)] {
+		return rejected("image is blocked"), nil
+	}
+
+	if m.maxBytes > 0 && submission.Size > m.maxBytes {
+		return rejected(fmt.Sprintf("image is larger than %d bytes", m.maxBytes)), nil
+	}
+
+	if submission.Width < m.minDimension || submission.Height < m.minDimension {
+		return rejected(fmt.Sprintf("image is smaller than %dx%d", m.minDimension, m.minDimension)), nil
+	}
+
+	if m.maxAspect > 0 {
+		longSide, shortSide := float64(submission.Width), float64(submission.Height)
+		if shortSide > longSide {
+			longSide, shortSide = shortSide, longSide
+		}
+		if longSide/shortSide > m.maxAspect {
+			return rejected(fmt.Sprintf("image aspect ratio is wider than %.1f:1", m.maxAspect)), nil
+		}
+	}
+
+	if m.requireReview {
+		return Verdict{Status: models.StatusPending}, nil
+	}
+
+	return Verdict{Status: models.StatusApproved}, nil
+}
+
+// NewModerator returns the moderator selected in config, defaulting to allow-all.
+func NewModerator(cfg config.ModerationConfig) Moderator {
+	if cfg.Moderator == ModeratorRules {
+		return NewRulesModerator(cfg)
+	}
+
+	return NewAllowAllModerator()
+}
+
+func rejected(reason string) Verdict {
+	return Verdict{Status: models.StatusRejected, Reason: reason}
+}
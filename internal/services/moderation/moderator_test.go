@@ -0,0 +1,111 @@
+package moderation_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/weeb-vip/user-service/config"
+	"github.com/weeb-vip/user-service/internal/services/moderation"
+	"github.com/weeb-vip/user-service/internal/services/moderation/models"
+)
+
+func TestNewModerator(t *testing.T) {
+	t.Run("defaults to allow all", func(t *testing.T) {
+		moderator := moderation.NewModerator(config.ModerationConfig{})
+		verdict, err := moderator.Review(context.TODO(), moderation.Submission{})
+		assert.NoError(t, err)
+		assert.Equal(t, models.StatusApproved, verdict.Status)
+	})
+	t.Run("selects the rules moderator", func(t *testing.T) {
+		moderator := moderation.NewModerator(config.ModerationConfig{Moderator: moderation.ModeratorRules, MinDimension: 32})
+		verdict, err := moderator.Review(context.TODO(), moderation.Submission{Width: 1, Height: 1})
+		assert.NoError(t, err)
+		assert.Equal(t, models.StatusRejected, verdict.Status)
+	})
+}
+
+func TestRulesModerator_Review(t *testing.T) {
+	blocked := strings.Repeat("ab", 32)
+	cfg := config.ModerationConfig{
+		MaxBytes:      1000,
+		MinDimension:  32,
+		MaxAspect:     3,
+		BlockedHashes: " " + strings.ToUpper(blocked) + ",,other",
+	}
+	valid := moderation.Submission{Hash: strings.Repeat("cd", 32), Size: 500, Width: 64, Height: 64}
+
+	tests := []struct {
+		name       string
+		cfg        config.ModerationConfig
+		submission func(moderation.Submission) moderation.Submission
+		status     models.Status
+		reason     string
+	}{
+		{
+			name:       "approves images within the rules",
+			cfg:        cfg,
+			submission: func(s moderation.Submission) moderation.Submission { return s },
+			status:     models.StatusApproved,
+		},
+		{
+			name: "holds images for review when required",
+			cfg: func() config.ModerationConfig {
+				c := cfg
+				c.RequireReview = true
+				return c
+			}(),
+			submission: func(s moderation.Submission) moderation.Submission { return s },
+			status:     models.StatusPending,
+		},
+		{
+			name:       "rejects blocked hashes regardless of case",
+			cfg:        cfg,
+			submission: func(s moderation.Submission) moderation.Submission { s.Hash = blocked; return s },
+			status:     models.StatusRejected,
+			reason:     "image is blocked",
+		},
+		{
+			name:       "rejects oversized images",
+			cfg:        cfg,
+			submission: func(s moderation.Submission) moderation.Submission { s.Size = 1001; return s },
+			status:     models.StatusRejected,
+			reason:     "image is larger than 1000 bytes",
+		},
+		{
+			name:       "rejects tiny images",
+			cfg:        cfg,
+			submission: func(s moderation.Submission) moderation.Submission { s.Height = 31; return s },
+			status:     models.StatusRejected,
+			reason:     "image is smaller than 32x32",
+		},
+		{
+			name:       "rejects very tall images",
+			cfg:        cfg,
+			submission: func(s moderation.Submission) moderation.Submission { s.Height = 200; return s },
+			status:     models.StatusRejected,
+			reason:     "image aspect ratio is wider than 3.0:1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verdict, err := moderation.NewRulesModerator(tt.cfg).Review(context.TODO(), tt.submission(valid))
+			assert.NoError(t, err)
+			assert.Equal(t, tt.status, verdict.Status)
+			assert.Equal(t, tt.reason, verdict.Reason)
+		})
+	}
+}
+
+func TestStatus_CanTransitionTo(t *testing.T) {
+	assert.True(t, models.StatusPending.CanTransitionTo(models.StatusApproved))
+	assert.True(t, models.StatusPending.CanTransitionTo(models.StatusRejected))
+	assert.True(t, models.StatusApproved.CanTransitionTo(models.StatusRejected))
+	assert.False(t, models.StatusApproved.CanTransitionTo(models.StatusPending))
+	assert.False(t, models.StatusRejected.CanTransitionTo(models.StatusApproved))
+	assert.False(t, models.StatusRejected.CanTransitionTo(models.StatusPending))
+	assert.False(t, models.StatusPending.CanTransitionTo(models.StatusPending))
+}
@@ -0,0 +1,273 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/weeb-vip/user-service/internal/db"
+	"github.com/weeb-vip/user-service/internal/services/moderation/models"
+	"github.com/weeb-vip/user-service/metrics"
+	"github.com/weeb-vip/user-service/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ErrStatusChanged is returned by UpdateStatus when the image is no longer in the expected status.
+var ErrStatusChanged = errors.New("profile image status changed concurrently")
+
+type ProfileImagesRepository interface {
+	AddProfileImage(ctx context.Context, userID string, path string) (*models.ProfileImage, error)
+	GetProfileImageByID(ctx context.Context, id string) (*models.ProfileImage, error)
+	ListProfileImagesByStatus(ctx context.Context, status models.Status, limit int, offset int) ([]*models.ProfileImage, error)
+	ListUserProfileImagesByStatus(ctx context.Context, userID string, status models.Status) ([]*models.ProfileImage, error)
+	// UpdateStatus moves the image from one status to another, failing with ErrStatusChanged if
+	// another reviewer got there first.
+	UpdateStatus(ctx context.Context, id string, from models.Status, to models.Status, reviewerID *string, reason *string) (*models.ProfileImage, error)
+}
+
+type profileImageRepository struct {
+	DBService db.DB
+}
+
+var profileImageRepositorySingleton ProfileImagesRepository // nolint
+
+func NewProfileImagesRepository() ProfileImagesRepository {
+	dbService := db.GetDBService()
+
+	return &profileImageRepository{
+		DBService: dbService,
+	}
+}
+
+func (repository *profileImageRepository) AddProfileImage(ctx context.Context, userID string, path string) (*models.ProfileImage, error) {
+	tracer := tracing.GetTracer(ctx)
+	ctx, span := tracer.Start(ctx, "repository.AddProfileImage",
+		trace.WithAttributes(
+			attribute.String("user.id", userID),
+			attribute.String("table", "profile_images"),
+			attribute.String("operation", "create"),
+		),
+		tracing.GetEnvironmentAttribute(),
+	)
+	defer span.End()
+
+	start := time.Now()
+	database := repository.DBService.GetDB()
+
+	profileImage := models.ProfileImage{
+		UserID: userID,
+		Path:   path,
+		Status: models.StatusPending,
+	}
+	err := database.WithContext(ctx).Create(&profileImage).Error
+
+	// Record database metrics
+	duration := float64(time.Since(start).Nanoseconds()) / float64(time.Millisecond)
+	appMetrics := metrics.GetAppMetrics()
+	result := metrics.Success
+	if err != nil {
+		result = metrics.Error
+	}
+	appMetrics.DatabaseMetric(duration, "profile_images", "create", result)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &profileImage, nil
+}
+
+func (repository *profileImageRepository) GetProfileImageByID(ctx context.Context, id string) (*models.ProfileImage, error) {
+	tracer := tracing.GetTracer(ctx)
+	ctx, span := tracer.Start(ctx, "repository.GetProfileImageByID",
+		trace.WithAttributes(
+			attribute.String("profile_image.id", id),
+			attribute.String("table", "profile_images"),
+			attribute.String("operation", "select"),
+		),
+		tracing.GetEnvironmentAttribute(),
+	)
+	defer span.End()
+
+	start := time.Now()
+	database := repository.DBService.GetDB()
+
+	var profileImage models.ProfileImage
+
+	err := database.WithContext(ctx).Where("id = ?", id).First(&profileImage).Error
+
+	// Record database metrics
+	duration := float64(time.Since(start).Nanoseconds()) / float64(time.Millisecond)
+	appMetrics := metrics.GetAppMetrics()
+	result := metrics.Success
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		result = metrics.Error
+	}
+	appMetrics.DatabaseMetric(duration, "profile_images", "select", result)
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &profileImage, nil
+}
+
+func (repository *profileImageRepository) ListProfileImagesByStatus(
+	ctx context.Context,
+	status models.Status,
+	limit int,
+	offset int,
+) ([]*models.ProfileImage, error) {
+	tracer := tracing.GetTracer(ctx)
+	ctx, span := tracer.Start(ctx, "repository.ListProfileImagesByStatus",
+		trace.WithAttributes(
+			attribute.String("profile_image.status", status.String()),
+			attribute.String("table", "profile_images"),
+			attribute.String("operation", "select"),
+		),
+		tracing.GetEnvironmentAttribute(),
+	)
+	defer span.End()
+
+	start := time.Now()
+	database := repository.DBService.GetDB()
+
+	var profileImages []*models.ProfileImage
+
+	// Oldest first, so the queue is worked through in submission order
+	err := database.WithContext(ctx).
+		Where("status = ?", status).
+		Order("created_at ASC").
+		Limit(limit).
+		Offset(offset).
+		Find(&profileImages).Error
+
+	// Record database metrics
+	duration := float64(time.Since(start).Nanoseconds()) / float64(time.Millisecond)
+	appMetrics := metrics.GetAppMetrics()
+	result := metrics.Success
+	if err != nil {
+		result = metrics.Error
+	}
+	appMetrics.DatabaseMetric(duration, "profile_images", "select", result)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return profileImages, nil
+}
+
+func (repository *profileImageRepository) ListUserProfileImagesByStatus(
+	ctx context.Context,
+	userID string,
+	status models.Status,
+) ([]*models.ProfileImage, error) {
+	tracer := tracing.GetTracer(ctx)
+	ctx, span := tracer.Start(ctx, "repository.ListUserProfileImagesByStatus",
+		trace.WithAttributes(
+			attribute.String("user.id", userID),
+			attribute.String("profile_image.status", status.String()),
+			attribute.String("table", "profile_images"),
+			attribute.String("operation", "select"),
+		),
+		tracing.GetEnvironmentAttribute(),
+	)
+	defer span.End()
+
+	start := time.Now()
+	database := repository.DBService.GetDB()
+
+	var profileImages []*models.ProfileImage
+
+	// Newest first, so callers can take the latest submission
+	err := database.WithContext(ctx).
+		Where("user_id = ? AND status = ?", userID, status).
+		Order("created_at DESC").
+		Find(&profileImages).Error
+
+	// Record database metrics
+	duration := float64(time.Since(start).Nanoseconds()) / float64(time.Millisecond)
+	appMetrics := metrics.GetAppMetrics()
+	result := metrics.Success
+	if err != nil {
+		result = metrics.Error
+	}
+	appMetrics.DatabaseMetric(duration, "profile_images", "select", result)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return profileImages, nil
+}
+
+func (repository *profileImageRepository) UpdateStatus(
+	ctx context.Context,
+	id string,
+	from models.Status,
+	to models.Status,
+	reviewerID *string,
+	reason *string,
+) (*models.ProfileImage, error) {
+	tracer := tracing.GetTracer(ctx)
+	ctx, span := tracer.Start(ctx, "repository.UpdateProfileImageStatus",
+		trace.WithAttributes(
+			attribute.String("profile_image.id", id),
+			attribute.String("profile_image.from", from.String()),
+			attribute.String("profile_image.to", to.String()),
+			attribute.String("table", "profile_images"),
+			attribute.String("operation", "update"),
+		),
+		tracing.GetEnvironmentAttribute(),
+	)
+	defer span.End()
+
+	start := time.Now()
+	database := repository.DBService.GetDB()
+
+	now := time.Now()
+	// Conditioning on the current status makes the transition a compare-and-swap
+	result := database.WithContext(ctx).
+		Model(&models.ProfileImage{}).
+		Where("id = ? AND status = ?", id, from).
+		Updates(map[string]interface{}{
+			"status":      to,
+			"reason":      reason,
+			"reviewed_by": reviewerID,
+			"reviewed_at": &now,
+		})
+	err := result.Error
+
+	// Record database metrics
+	duration := float64(time.Since(start).Nanoseconds()) / float64(time.Millisecond)
+	appMetrics := metrics.GetAppMetrics()
+	metricResult := metrics.Success
+	if err != nil {
+		metricResult = metrics.Error
+	}
+	appMetrics.DatabaseMetric(duration, "profile_images", "update", metricResult)
+
+	if err != nil {
+		return nil, err
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrStatusChanged
+	}
+
+	return repository.GetProfileImageByID(ctx, id)
+}
+
+func GetProfileImagesRepository() ProfileImagesRepository {
+	if profileImageRepositorySingleton == nil {
+		profileImageRepositorySingleton = NewProfileImagesRepository()
+	}
+
+	return profileImageRepositorySingleton
+}
@@ -0,0 +1,23 @@
+package roles
+
+const (
+	RolesErrorInternalError   ErrorCode = "INTERNAL_ERROR"         // nolint
+	RolesErrorInvalidRole     ErrorCode = "ROLE_INVALID"           // nolint
+	RolesErrorSelfRevoke      ErrorCode = "ROLE_SELF_REVOKE"       // nolint
+	RolesErrorManagedByConfig ErrorCode = "ROLE_MANAGED_BY_CONFIG" // nolint
+)
+
+type ErrorCode string
+
+type Error struct {
+	Code    ErrorCode
+	Message string
+}
+
+func (c ErrorCode) String() string {
+	return string(c)
+}
+
+func (e Error) Error() string {
+	return e.Message
+}
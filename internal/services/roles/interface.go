@@ -0,0 +1,27 @@
+package roles
+
+import (
+	"context"
+)
+
+// UserRoles is what a user is allowed to do.
+type UserRoles struct {
+	UserID string
+	Roles  []Role
+	Scopes []string
+}
+
+type Roles interface {
+	// RolesFor returns the user's roles and the scopes they grant. Every user has the user role.
+	RolesFor(ctx context.Context, userID string) (*UserRoles, error)
+	// HasRole reports whether the user holds role or a role that includes it.
+	HasRole(ctx context.Context, userID string, role Role) (bool, error)
+	// HasScope reports whether any of the user's roles grants scope.
+	HasScope(ctx context.Context, userID string, scope string) (bool, error)
+	// AssignRole grants role to userID on behalf of actorID. Assigning a role the user already has
+	// is a no-op and is not recorded.
+	AssignRole(ctx context.Context, actorID string, userID string, role Role, reason *string) (*UserRoles, error)
+	// RevokeRole removes role from userID on behalf of actorID. Revoking a role the user does not
+	// have is a no-op and is not recorded.
+	RevokeRole(ctx context.Context, actorID string, userID string, role Role, reason *string) (*UserRoles, error)
+}
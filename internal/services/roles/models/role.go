@@ -0,0 +1,38 @@
+package models
+
+import (
+	"time"
+
+	"github.com/weeb-vip/user-service/internal/db"
+)
+
+const (
+	RoleChangeAssign = "assign"
+	RoleChangeRevoke = "revoke"
+)
+
+// UserRole is a role granted to a user on top of the implicit user role.
+type UserRole struct {
+	UserID    string    `json:"user_id" gorm:"primaryKey"`
+	Role      string    `json:"role" gorm:"primaryKey"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (UserRole) TableName() string {
+	return "user_roles"
+}
+
+// RoleChange is an audit entry for a role being assigned to or revoked from a user. Entries are
+// never updated or deleted.
+type RoleChange struct {
+	db.BaseModel
+	UserID  string  `json:"user_id"`
+	Role    string  `json:"role"`
+	Action  string  `json:"action"`
+	ActorID string  `json:"actor_id"`
+	Reason  *string `json:"reason"`
+}
+
+func (RoleChange) TableName() string {
+	return "role_changes"
+}
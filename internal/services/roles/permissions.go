@@ -0,0 +1,70 @@
+package roles
+
+// Role is a level of access. Each role includes every capability of the roles below it.
+type Role string
+
+const (
+	RoleUser      Role = "user"
+	RoleModerator Role = "moderator"
+	RoleAdmin     Role = "admin"
+)
+
+const (
+	ScopeProfileRead           = "profile:read"
+	ScopeProfileWrite          = "profile:write"
+	ScopeSettingsWrite         = "settings:write"
+	ScopeProfileImagesModerate = "profile_images:moderate"
+	ScopeUsersReadAny          = "users:read_any"
+	ScopeRolesManage           = "roles:manage"
+	ScopeAuditLogReadAny       = "audit_log:read_any"
+)
+
+// hierarchy lists the roles from least to most privileged.
+var hierarchy = []Role{RoleUser, RoleModerator, RoleAdmin}
+
+// grants holds the scopes each role adds on top of the roles below it.
+var grants = map[Role][]string{
+	RoleUser:      {ScopeProfileRead, ScopeProfileWrite, ScopeSettingsWrite},
+	RoleModerator: {ScopeProfileImagesModerate},
+	RoleAdmin:     {ScopeUsersReadAny, ScopeRolesManage, ScopeAuditLogReadAny},
+}
+
+func (r Role) IsValid() bool {
+	return r.rank() >= 0
+}
+
+func (r Role) String() string {
+	return string(r)
+}
+
+// Includes reports whether r carries every capability of other.
+func (r Role) Includes(other Role) bool {
+	return other.IsValid() && r.rank() >= other.rank()
+}
+
+func (r Role) rank() int {
+	for i, role := range hierarchy {
+		if role == r {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// ScopesFor returns the scopes granted by roles, in hierarchy order and without duplicates.
+func ScopesFor(roles []Role) []string {
+	highest := -1
+	for _, role := range roles {
+		if rank := role.rank(); rank > highest {
+			highest = rank
+		}
+	}
+
+	var scopes []string
+	for _, role := range hierarchy[:highest+1] {
+		scopes = append(scopes, grants[role]...)
+	}
+
+	return scopes
+}
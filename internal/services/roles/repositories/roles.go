@@ -0,0 +1,183 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/weeb-vip/user-service/internal/db"
+	"github.com/weeb-vip/user-service/internal/services/roles/models"
+	"github.com/weeb-vip/user-service/metrics"
+	"github.com/weeb-vip/user-service/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type RolesRepository interface {
+	// GetRoles returns the roles stored for the user, which does not include the implicit user role.
+	GetRoles(ctx context.Context, userID string) ([]string, error)
+	// AssignRole stores change.Role for change.UserID and records change in the same transaction.
+	// changed is false, and nothing is recorded, when the user already had the role.
+	AssignRole(ctx context.Context, change *models.RoleChange) (changed bool, err error)
+	// RevokeRole removes change.Role from change.UserID and records change in the same transaction.
+	// changed is false, and nothing is recorded, when the user did not have the role.
+	RevokeRole(ctx context.Context, change *models.RoleChange) (changed bool, err error)
+}
+
+type rolesRepository struct {
+	DBService db.DB
+}
+
+var rolesRepositorySingleton RolesRepository // nolint
+
+func NewRolesRepository() RolesRepository {
+	dbService := db.GetDBService()
+
+	return &rolesRepository{
+		DBService: dbService,
+	}
+}
+
+func (repository *rolesRepository) GetRoles(ctx context.Context, userID string) ([]string, error) {
+	tracer := tracing.GetTracer(ctx)
+	ctx, span := tracer.Start(ctx, "repository.GetRoles",
+		trace.WithAttributes(
+			attribute.String("user.id", userID),
+			attribute.String("table", "user_roles"),
+			attribute.String("operation", "select"),
+		),
+		tracing.GetEnvironmentAttribute(),
+	)
+	defer span.End()
+
+	start := time.Now()
+	database := repository.DBService.GetDB()
+
+	var roles []string
+	err := database.WithContext(ctx).Model(&models.UserRole{}).
+		Where("user_id = ?", userID).
+		Order("role").
+		Pluck("role", &roles).Error
+
+	// Record database metrics
+	duration := float64(time.Since(start).Nanoseconds()) / float64(time.Millisecond)
+	appMetrics := metrics.GetAppMetrics()
+	result := metrics.Success
+	if err != nil {
+		result = metrics.Error
+	}
+	appMetrics.DatabaseMetric(duration, "user_roles", "select", result)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return roles, nil
+}
+
+func (repository *rolesRepository) AssignRole(ctx context.Context, change *models.RoleChange) (bool, error) {
+	tracer := tracing.GetTracer(ctx)
+	ctx, span := tracer.Start(ctx, "repository.AssignRole",
+		trace.WithAttributes(
+			attribute.String("user.id", change.UserID),
+			attribute.String("role", change.Role),
+			attribute.String("table", "user_roles"),
+			attribute.String("operation", "insert"),
+		),
+		tracing.GetEnvironmentAttribute(),
+	)
+	defer span.End()
+
+	start := time.Now()
+	database := repository.DBService.GetDB()
+
+	changed := false
+	err := database.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		userRole := models.UserRole{UserID: change.UserID, Role: change.Role}
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&userRole)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+
+		changed = true
+		change.Action = models.RoleChangeAssign
+
+		return tx.Create(change).Error
+	})
+
+	// Record database metrics
+	duration := float64(time.Since(start).Nanoseconds()) / float64(time.Millisecond)
+	appMetrics := metrics.GetAppMetrics()
+	result := metrics.Success
+	if err != nil {
+		result = metrics.Error
+	}
+	appMetrics.DatabaseMetric(duration, "user_roles", "insert", result)
+
+	if err != nil {
+		return false, err
+	}
+
+	return changed, nil
+}
+
+func (repository *rolesRepository) RevokeRole(ctx context.Context, change *models.RoleChange) (bool, error) {
+	tracer := tracing.GetTracer(ctx)
+	ctx, span := tracer.Start(ctx, "repository.RevokeRole",
+		trace.WithAttributes(
+			attribute.String("user.id", change.UserID),
+			attribute.String("role", change.Role),
+			attribute.String("table", "user_roles"),
+			attribute.String("operation", "delete"),
+		),
+		tracing.GetEnvironmentAttribute(),
+	)
+	defer span.End()
+
+	start := time.Now()
+	database := repository.DBService.GetDB()
+
+	changed := false
+	err := database.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("user_id = ? AND role = ?", change.UserID, change.Role).Delete(&models.UserRole{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+
+		changed = true
+		change.Action = models.RoleChangeRevoke
+
+		return tx.Create(change).Error
+	})
+
+	// Record database metrics
+	duration := float64(time.Since(start).Nanoseconds()) / float64(time.Millisecond)
+	appMetrics := metrics.GetAppMetrics()
+	result := metrics.Success
+	if err != nil {
+		result = metrics.Error
+	}
+	appMetrics.DatabaseMetric(duration, "user_roles", "delete", result)
+
+	if err != nil {
+		return false, err
+	}
+
+	return changed, nil
+}
+
+func GetRolesRepository() RolesRepository {
+	if rolesRepositorySingleton == nil {
+		rolesRepositorySingleton = NewRolesRepository()
+	}
+
+	return rolesRepositorySingleton
+}
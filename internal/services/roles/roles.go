@@ -0,0 +1,275 @@
+package roles
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/weeb-vip/user-service/internal/services/roles/models"
+	"github.com/weeb-vip/user-service/internal/services/roles/repositories"
+	"github.com/weeb-vip/user-service/metrics"
+	"github.com/weeb-vip/user-service/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type rolesService struct {
+	rolesRepository repositories.RolesRepository
+	// configAdmins are admins listed in the config. They are admins regardless of the stored
+	// roles, so there is always someone who can assign the first roles.
+	configAdmins map[string]bool
+}
+
+func NewRolesService(rolesRepository repositories.RolesRepository, configAdmins map[string]bool) Roles {
+	return &rolesService{
+		rolesRepository: rolesRepository,
+		configAdmins:    configAdmins,
+	}
+}
+
+func (service *rolesService) RolesFor(ctx context.Context, userID string) (*UserRoles, error) {
+	tracer := tracing.GetTracer(ctx)
+	ctx, span := tracer.Start(ctx, "service.RolesFor",
+		trace.WithAttributes(
+			attribute.String("user.id", userID),
+			attribute.String("service", "roles"),
+			attribute.String("method", "RolesFor"),
+		),
+		tracing.GetEnvironmentAttribute(),
+	)
+	defer span.End()
+
+	startTime := time.Now()
+
+	userRoles, err := service.rolesFor(ctx, userID)
+	if err != nil {
+		service.recordMetric(startTime, "RolesFor", metrics.Error)
+		return nil, err
+	}
+
+	service.recordMetric(startTime, "RolesFor", metrics.Success)
+
+	return userRoles, nil
+}
+
+func (service *rolesService) HasRole(ctx context.Context, userID string, role Role) (bool, error) {
+	tracer := tracing.GetTracer(ctx)
+	ctx, span := tracer.Start(ctx, "service.HasRole",
+		trace.WithAttributes(
+			attribute.String("user.id", userID),
+			attribute.String("role", role.String()),
+			attribute.String("service", "roles"),
+			attribute.String("method", "HasRole"),
+		),
+		tracing.GetEnvironmentAttribute(),
+	)
+	defer span.End()
+
+	startTime := time.Now()
+
+	userRoles, err := service.rolesFor(ctx, userID)
+	if err != nil {
+		service.recordMetric(startTime, "HasRole", metrics.Error)
+		return false, err
+	}
+
+	service.recordMetric(startTime, "HasRole", metrics.Success)
+
+	for _, held := range userRoles.Roles {
+		if held.Includes(role) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func (service *rolesService) HasScope(ctx context.Context, userID string, scope string) (bool, error) {
+	tracer := tracing.GetTracer(ctx)
+	ctx, span := tracer.Start(ctx, "service.HasScope",
+		trace.WithAttributes(
+			attribute.String("user.id", userID),
+			attribute.String("scope", scope),
+			attribute.String("service", "roles"),
+			attribute.String("method", "HasScope"),
+		),
+		tracing.GetEnvironmentAttribute(),
+	)
+	defer span.End()
+
+	startTime := time.Now()
+
+	userRoles, err := service.rolesFor(ctx, userID)
+	if err != nil {
+		service.recordMetric(startTime, "HasScope", metrics.Error)
+		return false, err
+	}
+
+	service.recordMetric(startTime, "HasScope", metrics.Success)
+
+	for _, granted := range userRoles.Scopes {
+		if granted == scope {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func (service *rolesService) AssignRole(
+	ctx context.Context,
+	actorID string,
+	userID string,
+	role Role,
+	reason *string,
+) (*UserRoles, error) {
+	tracer := tracing.GetTracer(ctx)
+	ctx, span := tracer.Start(ctx, "service.AssignRole",
+		trace.WithAttributes(
+			attribute.String("user.id", userID),
+			attribute.String("actor.id", actorID),
+			attribute.String("role", role.String()),
+			attribute.String("service", "roles"),
+			attribute.String("method", "AssignRole"),
+		),
+		tracing.GetEnvironmentAttribute(),
+	)
+	defer span.End()
+
+	startTime := time.Now()
+
+	if err := checkAssignable(role); err != nil {
+		service.recordMetric(startTime, "AssignRole", metrics.Error)
+		return nil, err
+	}
+
+	_, err := service.rolesRepository.AssignRole(ctx, &models.RoleChange{
+		UserID:  userID,
+		Role:    role.String(),
+		ActorID: actorID,
+		Reason:  reason,
+	})
+	if err != nil {
+		service.recordMetric(startTime, "AssignRole", metrics.Error)
+		return nil, &Error{Code: RolesErrorInternalError, Message: "database error"}
+	}
+
+	userRoles, err := service.rolesFor(ctx, userID)
+	if err != nil {
+		service.recordMetric(startTime, "AssignRole", metrics.Error)
+		return nil, err
+	}
+
+	service.recordMetric(startTime, "AssignRole", metrics.Success)
+
+	return userRoles, nil
+}
+
+func (service *rolesService) RevokeRole(
+	ctx context.Context,
+	actorID string,
+	userID string,
+	role Role,
+	reason *string,
+) (*UserRoles, error) {
+	tracer := tracing.GetTracer(ctx)
+	ctx, span := tracer.Start(ctx, "service.RevokeRole",
+		trace.WithAttributes(
+			attribute.String("user.id", userID),
+			attribute.String("actor.id", actorID),
+			attribute.String("role", role.String()),
+			attribute.String("service", "roles"),
+			attribute.String("method", "RevokeRole"),
+		),
+		tracing.GetEnvironmentAttribute(),
+	)
+	defer span.End()
+
+	startTime := time.Now()
+
+	if err := checkAssignable(role); err != nil {
+		service.recordMetric(startTime, "RevokeRole", metrics.Error)
+		return nil, err
+	}
+
+	if role == RoleAdmin && service.configAdmins[userID] {
+		service.recordMetric(startTime, "RevokeRole", metrics.Error)
+		return nil, &Error{
+			Code:    RolesErrorManagedByConfig,
+			Message: "this user is an admin through the service configuration",
+		}
+	}
+
+	// Stops the last admin from locking everyone out by accident
+	if role == RoleAdmin && actorID == userID {
+		service.recordMetric(startTime, "RevokeRole", metrics.Error)
+		return nil, &Error{Code: RolesErrorSelfRevoke, Message: "admins cannot revoke their own admin role"}
+	}
+
+	_, err := service.rolesRepository.RevokeRole(ctx, &models.RoleChange{
+		UserID:  userID,
+		Role:    role.String(),
+		ActorID: actorID,
+		Reason:  reason,
+	})
+	if err != nil {
+		service.recordMetric(startTime, "RevokeRole", metrics.Error)
+		return nil, &Error{Code: RolesErrorInternalError, Message: "database error"}
+	}
+
+	userRoles, err := service.rolesFor(ctx, userID)
+	if err != nil {
+		service.recordMetric(startTime, "RevokeRole", metrics.Error)
+		return nil, err
+	}
+
+	service.recordMetric(startTime, "RevokeRole", metrics.Success)
+
+	return userRoles, nil
+}
+
+func (service *rolesService) rolesFor(ctx context.Context, userID string) (*UserRoles, error) {
+	stored, err := service.rolesRepository.GetRoles(ctx, userID)
+	if err != nil {
+		return nil, &Error{Code: RolesErrorInternalError, Message: "database error"}
+	}
+
+	held := map[Role]bool{RoleUser: true}
+	for _, role := range stored {
+		// Ignore roles this build does not know about rather than failing every check
+		if Role(role).IsValid() {
+			held[Role(role)] = true
+		}
+	}
+	if service.configAdmins[userID] {
+		held[RoleAdmin] = true
+	}
+
+	userRoles := &UserRoles{UserID: userID}
+	for _, role := range hierarchy {
+		if held[role] {
+			userRoles.Roles = append(userRoles.Roles, role)
+		}
+	}
+	userRoles.Scopes = ScopesFor(userRoles.Roles)
+
+	return userRoles, nil
+}
+
+func (service *rolesService) recordMetric(startTime time.Time, method string, result string) {
+	metrics.GetAppMetrics().ServiceMetric(
+		float64(time.Since(startTime).Milliseconds()),
+		"roles",
+		method,
+		result,
+	)
+}
+
+// checkAssignable rejects the user role, which everyone has and cannot lose, and unknown roles.
+func checkAssignable(role Role) error {
+	if role == RoleUser || !role.IsValid() {
+		return &Error{Code: RolesErrorInvalidRole, Message: fmt.Sprintf("role %q cannot be assigned or revoked", role)}
+	}
+
+	return nil
+}
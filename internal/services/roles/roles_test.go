@@ -0,0 +1,183 @@
+package roles
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/weeb-vip/user-service/internal/services/roles/models"
+	"github.com/weeb-vip/user-service/mocks"
+	"go.uber.org/mock/gomock"
+)
+
+func TestRole_Includes(t *testing.T) {
+	assert.True(t, RoleAdmin.Includes(RoleModerator))
+	assert.True(t, RoleAdmin.Includes(RoleUser))
+	assert.True(t, RoleModerator.Includes(RoleModerator))
+	assert.False(t, RoleModerator.Includes(RoleAdmin))
+	assert.False(t, RoleUser.Includes(RoleModerator))
+	assert.False(t, RoleAdmin.Includes(Role("superuser")))
+}
+
+func TestScopesFor(t *testing.T) {
+	assert.Equal(t,
+		[]string{ScopeProfileRead, ScopeProfileWrite, ScopeSettingsWrite},
+		ScopesFor([]Role{RoleUser}),
+	)
+	assert.Equal(t,
+		[]string{ScopeProfileRead, ScopeProfileWrite, ScopeSettingsWrite, ScopeProfileImagesModerate},
+		ScopesFor([]Role{RoleUser, RoleModerator}),
+	)
+	// A higher role includes everything below it even when the lower roles are not listed
+	assert.Contains(t, ScopesFor([]Role{RoleAdmin}), ScopeProfileImagesModerate)
+	assert.Contains(t, ScopesFor([]Role{RoleAdmin}), ScopeRolesManage)
+	assert.Empty(t, ScopesFor(nil))
+}
+
+func TestRolesService_RolesFor(t *testing.T) {
+	t.Run("every user has the user role", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		repository := mocks.NewMockRolesRepository(ctrl)
+		repository.EXPECT().GetRoles(gomock.Any(), "user1").Return(nil, nil)
+
+		result, err := NewRolesService(repository, nil).RolesFor(context.Background(), "user1")
+		require.NoError(t, err)
+		assert.Equal(t, []Role{RoleUser}, result.Roles)
+		assert.NotContains(t, result.Scopes, ScopeProfileImagesModerate)
+	})
+
+	t.Run("ignores unknown stored roles", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		repository := mocks.NewMockRolesRepository(ctrl)
+		repository.EXPECT().GetRoles(gomock.Any(), "user1").Return([]string{"moderator", "superuser"}, nil)
+
+		result, err := NewRolesService(repository, nil).RolesFor(context.Background(), "user1")
+		require.NoError(t, err)
+		assert.Equal(t, []Role{RoleUser, RoleModerator}, result.Roles)
+		assert.Contains(t, result.Scopes, ScopeProfileImagesModerate)
+	})
+
+	t.Run("config admins are admins without a stored role", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		repository := mocks.NewMockRolesRepository(ctrl)
+		repository.EXPECT().GetRoles(gomock.Any(), "user1").Return(nil, nil)
+
+		result, err := NewRolesService(repository, map[string]bool{"user1": true}).RolesFor(context.Background(), "user1")
+		require.NoError(t, err)
+		assert.Equal(t, []Role{RoleUser, RoleAdmin}, result.Roles)
+		assert.Contains(t, result.Scopes, ScopeRolesManage)
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		repository := mocks.NewMockRolesRepository(ctrl)
+		repository.EXPECT().GetRoles(gomock.Any(), "user1").Return(nil, errors.New("connection refused"))
+
+		_, err := NewRolesService(repository, nil).RolesFor(context.Background(), "user1")
+		assertErrorCode(t, err, RolesErrorInternalError)
+	})
+}
+
+func TestRolesService_HasRoleAndScope(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repository := mocks.NewMockRolesRepository(ctrl)
+	repository.EXPECT().GetRoles(gomock.Any(), "mod1").Return([]string{"moderator"}, nil).AnyTimes()
+	service := NewRolesService(repository, nil)
+
+	tests := []struct {
+		role Role
+		want bool
+	}{
+		{role: RoleUser, want: true},
+		{role: RoleModerator, want: true},
+		{role: RoleAdmin, want: false},
+	}
+	for _, tt := range tests {
+		got, err := service.HasRole(context.Background(), "mod1", tt.role)
+		require.NoError(t, err)
+		assert.Equal(t, tt.want, got, tt.role)
+	}
+
+	got, err := service.HasScope(context.Background(), "mod1", ScopeProfileImagesModerate)
+	require.NoError(t, err)
+	assert.True(t, got)
+
+	got, err = service.HasScope(context.Background(), "mod1", ScopeRolesManage)
+	require.NoError(t, err)
+	assert.False(t, got)
+}
+
+func TestRolesService_AssignRole(t *testing.T) {
+	t.Run("records the change", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		repository := mocks.NewMockRolesRepository(ctrl)
+		reason := "community moderator"
+		repository.EXPECT().AssignRole(gomock.Any(), &models.RoleChange{
+			UserID:  "user1",
+			Role:    "moderator",
+			ActorID: "admin1",
+			Reason:  &reason,
+		}).Return(true, nil)
+		repository.EXPECT().GetRoles(gomock.Any(), "user1").Return([]string{"moderator"}, nil)
+
+		result, err := NewRolesService(repository, nil).AssignRole(context.Background(), "admin1", "user1", RoleModerator, &reason)
+		require.NoError(t, err)
+		assert.Equal(t, []Role{RoleUser, RoleModerator}, result.Roles)
+	})
+
+	t.Run("rejects the implicit and unknown roles", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		repository := mocks.NewMockRolesRepository(ctrl)
+		service := NewRolesService(repository, nil)
+
+		_, err := service.AssignRole(context.Background(), "admin1", "user1", RoleUser, nil)
+		assertErrorCode(t, err, RolesErrorInvalidRole)
+
+		_, err = service.AssignRole(context.Background(), "admin1", "user1", Role("superuser"), nil)
+		assertErrorCode(t, err, RolesErrorInvalidRole)
+	})
+}
+
+func TestRolesService_RevokeRole(t *testing.T) {
+	t.Run("records the change", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		repository := mocks.NewMockRolesRepository(ctrl)
+		repository.EXPECT().RevokeRole(gomock.Any(), &models.RoleChange{
+			UserID:  "user1",
+			Role:    "admin",
+			ActorID: "admin1",
+		}).Return(true, nil)
+		repository.EXPECT().GetRoles(gomock.Any(), "user1").Return(nil, nil)
+
+		result, err := NewRolesService(repository, nil).RevokeRole(context.Background(), "admin1", "user1", RoleAdmin, nil)
+		require.NoError(t, err)
+		assert.Equal(t, []Role{RoleUser}, result.Roles)
+	})
+
+	t.Run("admins cannot revoke their own admin role", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		repository := mocks.NewMockRolesRepository(ctrl)
+
+		_, err := NewRolesService(repository, nil).RevokeRole(context.Background(), "admin1", "admin1", RoleAdmin, nil)
+		assertErrorCode(t, err, RolesErrorSelfRevoke)
+	})
+
+	t.Run("config admins keep their admin role", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		repository := mocks.NewMockRolesRepository(ctrl)
+
+		_, err := NewRolesService(repository, map[string]bool{"user1": true}).
+			RevokeRole(context.Background(), "admin1", "user1", RoleAdmin, nil)
+		assertErrorCode(t, err, RolesErrorManagedByConfig)
+	})
+}
+
+func assertErrorCode(t *testing.T, err error, code ErrorCode) {
+	t.Helper()
+
+	var rolesErr *Error
+	require.True(t, errors.As(err, &rolesErr), "expected roles error, got %v", err)
+	assert.Equal(t, code, rolesErr.Code)
+}
@@ -0,0 +1,23 @@
+package servicetokens
+
+const (
+	ServiceTokenErrorInternalError      ErrorCode = "INTERNAL_ERROR"                     // nolint
+	ServiceTokenErrorAudienceNotAllowed ErrorCode = "SERVICE_TOKEN_AUDIENCE_NOT_ALLOWED" // nolint
+	ServiceTokenErrorScopeInvalid       ErrorCode = "SERVICE_TOKEN_SCOPE_INVALID"        // nolint
+	ServiceTokenErrorTTLInvalid         ErrorCode = "SERVICE_TOKEN_TTL_INVALID"          // nolint
+)
+
+type ErrorCode string
+
+type Error struct {
+	Code    ErrorCode
+	Message string
+}
+
+func (c ErrorCode) String() string {
+	return string(c)
+}
+
+func (e Error) Error() string {
+	return e.Message
+}
@@ -0,0 +1,19 @@
+package servicetokens
+
+import (
+	"context"
+	"time"
+)
+
+type ServiceToken struct {
+	Token     string
+	Audience  string
+	Scopes    []string
+	ExpiresAt time.Time
+}
+
+type ServiceTokens interface {
+	// IssueServiceToken mints a token for subject that only audience should accept, limited to
+	// scopes. A nil ttl uses the configured default.
+	IssueServiceToken(ctx context.Context, subject string, audience string, scopes []string, ttl *time.Duration) (*ServiceToken, error)
+}
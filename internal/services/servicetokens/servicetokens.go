@@ -0,0 +1,148 @@
+package servicetokens
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/weeb-vip/user-service/config"
+	"github.com/weeb-vip/user-service/internal/jwt"
+	"github.com/weeb-vip/user-service/metrics"
+	"github.com/weeb-vip/user-service/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// maxScopes keeps tokens small, a caller needing more is asking for too much.
+const maxScopes = 20
+
+var scopePattern = regexp.MustCompile(`^[a-z0-9_.:-]+$`)
+
+type serviceTokens struct {
+	tokenizer  jwt.Tokenizer
+	audiences  map[string]bool
+	defaultTTL time.Duration
+	maxTTL     time.Duration
+}
+
+func NewServiceTokens(tokenizer jwt.Tokenizer, cfg config.ServiceTokenConfig) ServiceTokens {
+	audiences := map[string]bool{}
+	for _, audience := range config.SplitList(cfg.Audiences) {
+		audiences[audience] = true
+	}
+
+	return &serviceTokens{
+		tokenizer:  tokenizer,
+		audiences:  audiences,
+		defaultTTL: time.Duration(cfg.DefaultTTLSeconds) * time.Second,
+		maxTTL:     time.Duration(cfg.MaxTTLSeconds) * time.Second,
+	}
+}
+
+func (service *serviceTokens) IssueServiceToken(
+	ctx context.Context,
+	subject string,
+	audience string,
+	scopes []string,
+	ttl *time.Duration,
+) (*ServiceToken, error) {
+	tracer := tracing.GetTracer(ctx)
+	_, span := tracer.Start(ctx, "service.IssueServiceToken",
+		trace.WithAttributes(
+			attribute.String("user.id", subject),
+			attribute.String("token.audience", audience),
+			attribute.StringSlice("token.scopes", scopes),
+			attribute.String("service", "servicetokens"),
+			attribute.String("method", "IssueServiceToken"),
+		),
+		tracing.GetEnvironmentAttribute(),
+	)
+	defer span.End()
+
+	startTime := time.Now()
+
+	if !service.audiences[audience] {
+		service.recordMetric(startTime, "IssueServiceToken", metrics.Error)
+		return nil, &Error{Code: ServiceTokenErrorAudienceNotAllowed, Message: fmt.Sprintf("tokens can't be issued for %q", audience)}
+	}
+
+	scopes, err := normalizeScopes(scopes)
+	if err != nil {
+		service.recordMetric(startTime, "IssueServiceToken", metrics.Error)
+		return nil, err
+	}
+
+	lifetime := service.defaultTTL
+	if ttl != nil {
+		lifetime = *ttl
+	}
+	if lifetime <= 0 || lifetime > service.maxTTL {
+		service.recordMetric(startTime, "IssueServiceToken", metrics.Error)
+		return nil, &Error{
+			Code:    ServiceTokenErrorTTLInvalid,
+			Message: fmt.Sprintf("ttl must be between 1 and %d seconds", int(service.maxTTL.Seconds())),
+		}
+	}
+
+	purpose := jwt.PurposeService
+	token, err := service.tokenizer.Tokenize(jwt.Claims{
+		Subject:  &subject,
+		TTL:      &lifetime,
+		Purpose:  &purpose,
+		Audience: &audience,
+		Scopes:   scopes,
+	})
+	if err != nil {
+		service.recordMetric(startTime, "IssueServiceToken", metrics.Error)
+		return nil, &Error{Code: ServiceTokenErrorInternalError, Message: "failed to sign token"}
+	}
+
+	claims, err := jwt.ParseUnverified(token)
+	if err != nil {
+		service.recordMetric(startTime, "IssueServiceToken", metrics.Error)
+		return nil, &Error{Code: ServiceTokenErrorInternalError, Message: "failed to sign token"}
+	}
+
+	service.recordMetric(startTime, "IssueServiceToken", metrics.Success)
+
+	return &ServiceToken{
+		Token:     token,
+		Audience:  audience,
+		Scopes:    scopes,
+		ExpiresAt: claims.ExpiresAt,
+	}, nil
+}
+
+// normalizeScopes drops duplicates, keeping the order scopes were asked for in.
+func normalizeScopes(scopes []string) ([]string, error) {
+	seen := map[string]bool{}
+	normalized := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		if !scopePattern.MatchString(scope) {
+			return nil, &Error{Code: ServiceTokenErrorScopeInvalid, Message: fmt.Sprintf("invalid scope %q", scope)}
+		}
+		if !seen[scope] {
+			seen[scope] = true
+			normalized = append(normalized, scope)
+		}
+	}
+
+	if len(normalized) == 0 {
+		return nil, &Error{Code: ServiceTokenErrorScopeInvalid, Message: "at least one scope is required"}
+	}
+	if len(normalized) > maxScopes {
+		return nil, &Error{Code: ServiceTokenErrorScopeInvalid, Message: fmt.Sprintf("at most %d scopes can be requested", maxScopes)}
+	}
+
+	return normalized, nil
+}
+
+func (service *serviceTokens) recordMetric(startTime time.Time, method string, result string) {
+	metrics.GetAppMetrics().ServiceMetric(
+		float64(time.Since(startTime).Milliseconds()),
+		"servicetokens",
+		method,
+		result,
+	)
+}
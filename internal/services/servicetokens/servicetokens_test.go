@@ -0,0 +1,76 @@
+package servicetokens_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/weeb-vip/user-service/config"
+	"github.com/weeb-vip/user-service/internal/jwt"
+	"github.com/weeb-vip/user-service/internal/keypair"
+	"github.com/weeb-vip/user-service/internal/services/servicetokens"
+)
+
+func newService(t *testing.T) (servicetokens.ServiceTokens, jwt.KeySet) {
+	count := 0
+	rotator, err := keypair.NewSigningKeyRotator(context.Background(), func(context.Context, string, keypair.Algorithm) (string, error) {
+		count++
+		return fmt.Sprintf("key_%d", count), nil
+	}, keypair.WithAlgorithm(keypair.AlgorithmEdDSA))
+	require.NoError(t, err)
+
+	service := servicetokens.NewServiceTokens(jwt.New(rotator), config.ServiceTokenConfig{
+		Audiences:         "anime-service, list-service",
+		DefaultTTLSeconds: 60,
+		MaxTTLSeconds:     300,
+	})
+
+	return service, jwt.NewKeySet(rotator)
+}
+
+func TestServiceTokens_IssueServiceToken(t *testing.T) {
+	service, keys := newService(t)
+
+	t.Run("mints a token only the audience accepts", func(t *testing.T) {
+		token, err := service.IssueServiceToken(context.Background(), "user_1", "anime-service", []string{"anime:read", "list:write", "anime:read"}, nil)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"anime:read", "list:write"}, token.Scopes)
+		assert.WithinDuration(t, time.Now().Add(time.Minute), token.ExpiresAt, 2*time.Second)
+
+		claims, err := jwt.NewVerifier(keys, jwt.WithAudience("anime-service")).Verify(context.Background(), token.Token)
+		require.NoError(t, err)
+		assert.Equal(t, "user_1", claims.Subject)
+		assert.Equal(t, jwt.PurposeService, claims.Purpose)
+		assert.Equal(t, []string{"anime:read", "list:write"}, claims.Scopes)
+
+		_, err = jwt.NewVerifier(keys).Verify(context.Background(), token.Token)
+		assert.ErrorIs(t, err, jwt.ErrInvalidAudience, "service tokens are not accepted as user tokens")
+	})
+
+	tests := []struct {
+		name     string
+		audience string
+		scopes   []string
+		ttl      time.Duration
+		code     servicetokens.ErrorCode
+	}{
+		{name: "unknown audience", audience: "billing-service", scopes: []string{"read"}, ttl: time.Minute, code: servicetokens.ServiceTokenErrorAudienceNotAllowed},
+		{name: "no scopes", audience: "anime-service", ttl: time.Minute, code: servicetokens.ServiceTokenErrorScopeInvalid},
+		{name: "malformed scope", audience: "anime-service", scopes: []string{"read write"}, ttl: time.Minute, code: servicetokens.ServiceTokenErrorScopeInvalid},
+		{name: "ttl over the maximum", audience: "anime-service", scopes: []string{"read"}, ttl: time.Hour, code: servicetokens.ServiceTokenErrorTTLInvalid},
+		{name: "negative ttl", audience: "anime-service", scopes: []string{"read"}, ttl: -time.Second, code: servicetokens.ServiceTokenErrorTTLInvalid},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.IssueServiceToken(context.Background(), "user_1", tt.audience, tt.scopes, &tt.ttl)
+
+			var serviceErr *servicetokens.Error
+			require.True(t, errors.As(err, &serviceErr))
+			assert.Equal(t, tt.code, serviceErr.Code)
+		})
+	}
+}
@@ -0,0 +1,22 @@
+package settings
+
+const (
+	SettingsErrorInternalError   ErrorCode = "INTERNAL_ERROR"            // nolint
+	SettingsErrorInvalid         ErrorCode = "SETTINGS_INVALID"          // nolint
+	SettingsErrorVersionConflict ErrorCode = "SETTINGS_VERSION_CONFLICT" // nolint
+)
+
+type ErrorCode string
+
+type Error struct {
+	Code    ErrorCode
+	Message string
+}
+
+func (c ErrorCode) String() string {
+	return string(c)
+}
+
+func (e Error) Error() string {
+	return e.Message
+}
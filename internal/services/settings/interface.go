@@ -0,0 +1,15 @@
+package settings
+
+import (
+	"context"
+
+	"github.com/weeb-vip/user-service/internal/services/settings/models"
+)
+
+type Settings interface {
+	// GetSettings returns the user's settings with defaults filled in for every key they have not set.
+	GetSettings(ctx context.Context, userID string) (*models.UserSettings, error)
+	// UpdateSettings applies changes on top of the stored settings, leaving keys that are not in
+	// changes untouched. When expectedVersion is set the update fails if the settings have moved on.
+	UpdateSettings(ctx context.Context, userID string, changes map[string]any, expectedVersion *int) (*models.UserSettings, error)
+}
@@ -0,0 +1,69 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Values holds setting values keyed by setting key, stored as a JSON document.
+type Values map[string]any
+
+func (v Values) Value() (driver.Value, error) {
+	if v == nil {
+		return "{}", nil
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	return string(data), nil
+}
+
+func (v *Values) Scan(src any) error {
+	var data []byte
+	switch value := src.(type) {
+	case nil:
+		*v = Values{}
+		return nil
+	case []byte:
+		data = value
+	case string:
+		data = []byte(value)
+	default:
+		return fmt.Errorf("unsupported settings column type %T", src)
+	}
+
+	values := Values{}
+	if err := json.Unmarshal(data, &values); err != nil {
+		return err
+	}
+	*v = values
+
+	return nil
+}
+
+// UserSettings is a user's settings document. Version is bumped on every write so clients can
+// detect that someone else changed the settings since they last read them.
+type UserSettings struct {
+	UserID    string    `json:"user_id" gorm:"primaryKey"`
+	Version   int       `json:"version"`
+	Values    Values    `json:"settings" gorm:"column:settings"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (s *UserSettings) Bool(key string) bool {
+	value, _ := s.Values[key].(bool)
+
+	return value
+}
+
+func (s *UserSettings) String(key string) string {
+	value, _ := s.Values[key].(string)
+
+	return value
+}
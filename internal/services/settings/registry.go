@@ -0,0 +1,159 @@
+package settings
+
+import (
+	"fmt"
+	"sort"
+	"time"
+	_ "time/tzdata" // time zones are validated against the embedded database, not the host's
+)
+
+const (
+	KeyTitleLanguage      = "title_language"
+	KeyShowSpoilers       = "show_spoilers"
+	KeyShowNSFW           = "show_nsfw"
+	KeyTheme              = "theme"
+	KeyTimeZone           = "time_zone"
+	KeyEmailNewsletter    = "email_newsletter"
+	KeyEmailNotifications = "email_notifications"
+)
+
+const (
+	KindBool   Kind = "bool"
+	KindString Kind = "string"
+)
+
+type Kind string
+
+// Definition describes a single setting: its type, default value and what values it accepts.
+type Definition struct {
+	Key     string
+	Kind    Kind
+	Default any
+	// Allowed restricts a string setting to a fixed set of values.
+	Allowed []string
+	// Validate checks free-form string values.
+	Validate func(value string) error
+}
+
+var registry = map[string]Definition{ // nolint
+	KeyTitleLanguage: {
+		Key:     KeyTitleLanguage,
+		Kind:    KindString,
+		Default: "romaji",
+		Allowed: []string{"romaji", "english", "native"},
+	},
+	KeyShowSpoilers: {
+		Key:     KeyShowSpoilers,
+		Kind:    KindBool,
+		Default: false,
+	},
+	KeyShowNSFW: {
+		Key:     KeyShowNSFW,
+		Kind:    KindBool,
+		Default: false,
+	},
+	KeyTheme: {
+		Key:     KeyTheme,
+		Kind:    KindString,
+		Default: "system",
+		Allowed: []string{"system", "light", "dark"},
+	},
+	KeyTimeZone: {
+		Key:      KeyTimeZone,
+		Kind:     KindString,
+		Default:  "UTC",
+		Validate: validateTimeZone,
+	},
+	KeyEmailNewsletter: {
+		Key:     KeyEmailNewsletter,
+		Kind:    KindBool,
+		Default: false,
+	},
+	KeyEmailNotifications: {
+		Key:     KeyEmailNotifications,
+		Kind:    KindBool,
+		Default: true,
+	},
+}
+
+// Lookup returns the definition registered for key.
+func Lookup(key string) (Definition, bool) {
+	definition, ok := registry[key]
+
+	return definition, ok
+}
+
+// Keys returns every registered setting key in sorted order.
+func Keys() []string {
+	keys := make([]string, 0, len(registry))
+	for key := range registry {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// Validate checks every change against its registered definition.
+func Validate(changes map[string]any) error {
+	for key, value := range changes {
+		definition, ok := Lookup(key)
+		if !ok {
+			return &Error{Code: SettingsErrorInvalid, Message: fmt.Sprintf("unknown setting %q", key)}
+		}
+		if err := definition.Check(value); err != nil {
+			return &Error{Code: SettingsErrorInvalid, Message: err.Error()}
+		}
+	}
+
+	return nil
+}
+
+// Check reports whether value is acceptable for the setting.
+func (d Definition) Check(value any) error {
+	switch d.Kind {
+	case KindBool:
+		if _, ok := value.(bool); !ok {
+			return fmt.Errorf("%s must be a boolean", d.Key)
+		}
+	case KindString:
+		str, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("%s must be a string", d.Key)
+		}
+		if len(d.Allowed) > 0 && !contains(d.Allowed, str) {
+			return fmt.Errorf("%s must be one of %v", d.Key, d.Allowed)
+		}
+		if d.Validate != nil {
+			if err := d.Validate(str); err != nil {
+				return fmt.Errorf("%s: %w", d.Key, err)
+			}
+		}
+	default:
+		return fmt.Errorf("%s has unknown kind %q", d.Key, d.Kind)
+	}
+
+	return nil
+}
+
+func validateTimeZone(value string) error {
+	// LoadLocation treats "" and "Local" as the server's zone, which means nothing to the client
+	if value == "" || value == "Local" {
+		return fmt.Errorf("invalid time zone %q", value)
+	}
+	if _, err := time.LoadLocation(value); err != nil {
+		return fmt.Errorf("invalid time zone %q", value)
+	}
+
+	return nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,151 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/weeb-vip/user-service/internal/db"
+	"github.com/weeb-vip/user-service/internal/services/settings/models"
+	"github.com/weeb-vip/user-service/metrics"
+	"github.com/weeb-vip/user-service/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ErrVersionConflict is returned by SaveUserSettings when the stored version no longer matches.
+var ErrVersionConflict = errors.New("user settings version changed concurrently")
+
+type UserSettingsRepository interface {
+	// GetUserSettings returns nil when the user has never saved any settings.
+	GetUserSettings(ctx context.Context, userID string) (*models.UserSettings, error)
+	// SaveUserSettings replaces the settings document if it is still at version, bumping the
+	// version by one. Version 0 means no document has been stored yet.
+	SaveUserSettings(ctx context.Context, userID string, version int, values models.Values) (*models.UserSettings, error)
+}
+
+type userSettingsRepository struct {
+	DBService db.DB
+}
+
+var userSettingsRepositorySingleton UserSettingsRepository // nolint
+
+func NewUserSettingsRepository() UserSettingsRepository {
+	dbService := db.GetDBService()
+
+	return &userSettingsRepository{
+		DBService: dbService,
+	}
+}
+
+func (repository *userSettingsRepository) GetUserSettings(ctx context.Context, userID string) (*models.UserSettings, error) {
+	tracer := tracing.GetTracer(ctx)
+	ctx, span := tracer.Start(ctx, "repository.GetUserSettings",
+		trace.WithAttributes(
+			attribute.String("user.id", userID),
+			attribute.String("table", "user_settings"),
+			attribute.String("operation", "select"),
+		),
+		tracing.GetEnvironmentAttribute(),
+	)
+	defer span.End()
+
+	start := time.Now()
+	database := repository.DBService.GetDB()
+
+	var settings models.UserSettings
+	err := database.WithContext(ctx).Where("user_id = ?", userID).First(&settings).Error
+
+	// Record database metrics
+	duration := float64(time.Since(start).Nanoseconds()) / float64(time.Millisecond)
+	appMetrics := metrics.GetAppMetrics()
+	result := metrics.Success
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		result = metrics.Error
+	}
+	appMetrics.DatabaseMetric(duration, "user_settings", "select", result)
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &settings, nil
+}
+
+func (repository *userSettingsRepository) SaveUserSettings(
+	ctx context.Context,
+	userID string,
+	version int,
+	values models.Values,
+) (*models.UserSettings, error) {
+	tracer := tracing.GetTracer(ctx)
+	ctx, span := tracer.Start(ctx, "repository.SaveUserSettings",
+		trace.WithAttributes(
+			attribute.String("user.id", userID),
+			attribute.Int("settings.version", version),
+			attribute.String("table", "user_settings"),
+			attribute.String("operation", "upsert"),
+		),
+		tracing.GetEnvironmentAttribute(),
+	)
+	defer span.End()
+
+	start := time.Now()
+	database := repository.DBService.GetDB()
+
+	now := time.Now()
+	settings := models.UserSettings{
+		UserID:    userID,
+		Version:   version + 1,
+		Values:    values,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	var result *gorm.DB
+	if version == 0 {
+		result = database.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&settings)
+	} else {
+		result = database.WithContext(ctx).Model(&models.UserSettings{}).
+			Where("user_id = ? AND version = ?", userID, version).
+			Updates(map[string]interface{}{
+				"settings":   values,
+				"version":    version + 1,
+				"updated_at": now,
+			})
+	}
+	err := result.Error
+
+	// Record database metrics
+	duration := float64(time.Since(start).Nanoseconds()) / float64(time.Millisecond)
+	appMetrics := metrics.GetAppMetrics()
+	metricResult := metrics.Success
+	if err != nil {
+		metricResult = metrics.Error
+	}
+	appMetrics.DatabaseMetric(duration, "user_settings", "upsert", metricResult)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if result.RowsAffected == 0 {
+		return nil, ErrVersionConflict
+	}
+
+	return repository.GetUserSettings(ctx, userID)
+}
+
+func GetUserSettingsRepository() UserSettingsRepository {
+	if userSettingsRepositorySingleton == nil {
+		userSettingsRepositorySingleton = NewUserSettingsRepository()
+	}
+
+	return userSettingsRepositorySingleton
+}
@@ -0,0 +1,170 @@
+package settings
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/weeb-vip/user-service/internal/services/settings/models"
+	"github.com/weeb-vip/user-service/internal/services/settings/repositories"
+	"github.com/weeb-vip/user-service/metrics"
+	"github.com/weeb-vip/user-service/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// maxSaveAttempts bounds how often an unversioned update is retried when another write lands first.
+const maxSaveAttempts = 3
+
+type settingsService struct {
+	userSettingsRepository repositories.UserSettingsRepository
+}
+
+func NewSettingsService(userSettingsRepository repositories.UserSettingsRepository) Settings {
+	return &settingsService{
+		userSettingsRepository: userSettingsRepository,
+	}
+}
+
+func (service *settingsService) GetSettings(ctx context.Context, userID string) (*models.UserSettings, error) {
+	tracer := tracing.GetTracer(ctx)
+	ctx, span := tracer.Start(ctx, "service.GetSettings",
+		trace.WithAttributes(
+			attribute.String("user.id", userID),
+			attribute.String("service", "settings"),
+			attribute.String("method", "GetSettings"),
+		),
+		tracing.GetEnvironmentAttribute(),
+	)
+	defer span.End()
+
+	startTime := time.Now()
+
+	stored, err := service.userSettingsRepository.GetUserSettings(ctx, userID)
+	if err != nil {
+		service.recordMetric(startTime, "GetSettings", metrics.Error)
+		return nil, &Error{Code: SettingsErrorInternalError, Message: "database error"}
+	}
+
+	service.recordMetric(startTime, "GetSettings", metrics.Success)
+
+	return WithDefaults(userID, stored), nil
+}
+
+func (service *settingsService) UpdateSettings(
+	ctx context.Context,
+	userID string,
+	changes map[string]any,
+	expectedVersion *int,
+) (*models.UserSettings, error) {
+	tracer := tracing.GetTracer(ctx)
+	ctx, span := tracer.Start(ctx, "service.UpdateSettings",
+		trace.WithAttributes(
+			attribute.String("user.id", userID),
+			attribute.Int("settings.changes", len(changes)),
+			attribute.String("service", "settings"),
+			attribute.String("method", "UpdateSettings"),
+		),
+		tracing.GetEnvironmentAttribute(),
+	)
+	defer span.End()
+
+	startTime := time.Now()
+
+	if err := Validate(changes); err != nil {
+		service.recordMetric(startTime, "UpdateSettings", metrics.Error)
+		return nil, err
+	}
+
+	for attempt := 0; attempt < maxSaveAttempts; attempt++ {
+		stored, err := service.userSettingsRepository.GetUserSettings(ctx, userID)
+		if err != nil {
+			service.recordMetric(startTime, "UpdateSettings", metrics.Error)
+			return nil, &Error{Code: SettingsErrorInternalError, Message: "database error"}
+		}
+
+		version := 0
+		values := models.Values{}
+		if stored != nil {
+			version = stored.Version
+			// Keep every stored key, including ones this build does not know about yet
+			for key, value := range stored.Values {
+				values[key] = value
+			}
+		}
+
+		if expectedVersion != nil && *expectedVersion != version {
+			service.recordMetric(startTime, "UpdateSettings", metrics.Error)
+			return nil, versionConflict()
+		}
+
+		if len(changes) == 0 {
+			service.recordMetric(startTime, "UpdateSettings", metrics.Success)
+			return WithDefaults(userID, stored), nil
+		}
+
+		for key, value := range changes {
+			values[key] = value
+		}
+
+		saved, err := service.userSettingsRepository.SaveUserSettings(ctx, userID, version, values)
+		if errors.Is(err, repositories.ErrVersionConflict) {
+			if expectedVersion != nil {
+				service.recordMetric(startTime, "UpdateSettings", metrics.Error)
+				return nil, versionConflict()
+			}
+			continue
+		}
+		if err != nil {
+			service.recordMetric(startTime, "UpdateSettings", metrics.Error)
+			return nil, &Error{Code: SettingsErrorInternalError, Message: "database error"}
+		}
+
+		service.recordMetric(startTime, "UpdateSettings", metrics.Success)
+
+		return WithDefaults(userID, saved), nil
+	}
+
+	service.recordMetric(startTime, "UpdateSettings", metrics.Error)
+
+	return nil, versionConflict()
+}
+
+func (service *settingsService) recordMetric(startTime time.Time, method string, result string) {
+	metrics.GetAppMetrics().ServiceMetric(
+		float64(time.Since(startTime).Milliseconds()),
+		"settings",
+		method,
+		result,
+	)
+}
+
+// WithDefaults returns a copy of stored with every registered key that the user has not set
+// filled in with its default.
+func WithDefaults(userID string, stored *models.UserSettings) *models.UserSettings {
+	result := &models.UserSettings{UserID: userID, Values: models.Values{}}
+	if stored != nil {
+		result.Version = stored.Version
+		result.CreatedAt = stored.CreatedAt
+		result.UpdatedAt = stored.UpdatedAt
+		for key, value := range stored.Values {
+			result.Values[key] = value
+		}
+	}
+
+	for _, key := range Keys() {
+		definition := registry[key]
+		if value, ok := result.Values[key]; !ok || definition.Check(value) != nil {
+			result.Values[key] = definition.Default
+		}
+	}
+
+	return result
+}
+
+func versionConflict() error {
+	return &Error{
+		Code:    SettingsErrorVersionConflict,
+		Message: "settings were changed by another client, reload and try again",
+	}
+}
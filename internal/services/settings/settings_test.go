@@ -0,0 +1,184 @@
+package settings
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/weeb-vip/user-service/internal/services/settings/models"
+	"github.com/weeb-vip/user-service/internal/services/settings/repositories"
+	"github.com/weeb-vip/user-service/mocks"
+	"go.uber.org/mock/gomock"
+)
+
+func TestDefinition_Check(t *testing.T) {
+	tests := []struct {
+		name    string
+		key     string
+		value   any
+		wantErr bool
+	}{
+		{name: "bool accepts bool", key: KeyShowSpoilers, value: true},
+		{name: "bool rejects string", key: KeyShowSpoilers, value: "true", wantErr: true},
+		{name: "enum accepts allowed value", key: KeyTitleLanguage, value: "native"},
+		{name: "enum rejects unknown value", key: KeyTitleLanguage, value: "klingon", wantErr: true},
+		{name: "enum rejects wrong type", key: KeyTheme, value: 1, wantErr: true},
+		{name: "time zone accepts IANA name", key: KeyTimeZone, value: "Asia/Tokyo"},
+		{name: "time zone rejects unknown name", key: KeyTimeZone, value: "Mars/Olympus", wantErr: true},
+		{name: "time zone rejects server local zone", key: KeyTimeZone, value: "Local", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			definition, ok := Lookup(tt.key)
+			require.True(t, ok)
+
+			err := definition.Check(tt.value)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestRegistryDefaultsAreValid(t *testing.T) {
+	for _, key := range Keys() {
+		definition, _ := Lookup(key)
+		assert.NoError(t, definition.Check(definition.Default), key)
+	}
+}
+
+func TestSettingsService_GetSettings(t *testing.T) {
+	t.Run("fills defaults when nothing is stored", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		repository := mocks.NewMockUserSettingsRepository(ctrl)
+		repository.EXPECT().GetUserSettings(gomock.Any(), "user1").Return(nil, nil)
+
+		result, err := NewSettingsService(repository).GetSettings(context.Background(), "user1")
+		require.NoError(t, err)
+		assert.Equal(t, 0, result.Version)
+		assert.Equal(t, "romaji", result.String(KeyTitleLanguage))
+		assert.Equal(t, "UTC", result.String(KeyTimeZone))
+		assert.True(t, result.Bool(KeyEmailNotifications))
+	})
+
+	t.Run("replaces stored values that are no longer valid", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		repository := mocks.NewMockUserSettingsRepository(ctrl)
+		repository.EXPECT().GetUserSettings(gomock.Any(), "user1").Return(&models.UserSettings{
+			UserID:  "user1",
+			Version: 4,
+			Values:  models.Values{KeyTheme: "sepia", KeyShowNSFW: true},
+		}, nil)
+
+		result, err := NewSettingsService(repository).GetSettings(context.Background(), "user1")
+		require.NoError(t, err)
+		assert.Equal(t, 4, result.Version)
+		assert.Equal(t, "system", result.String(KeyTheme))
+		assert.True(t, result.Bool(KeyShowNSFW))
+	})
+}
+
+func TestSettingsService_UpdateSettings(t *testing.T) {
+	version := func(v int) *int { return &v }
+
+	t.Run("merges changes over stored values, keeping keys it does not know", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		repository := mocks.NewMockUserSettingsRepository(ctrl)
+		repository.EXPECT().GetUserSettings(gomock.Any(), "user1").Return(&models.UserSettings{
+			UserID:  "user1",
+			Version: 2,
+			Values:  models.Values{KeyTheme: "dark", "future_key": "kept"},
+		}, nil)
+		repository.EXPECT().SaveUserSettings(gomock.Any(), "user1", 2, models.Values{
+			KeyTheme:        "dark",
+			KeyShowSpoilers: true,
+			"future_key":    "kept",
+		}).DoAndReturn(func(_ context.Context, userID string, v int, values models.Values) (*models.UserSettings, error) {
+			return &models.UserSettings{UserID: userID, Version: v + 1, Values: values}, nil
+		})
+
+		result, err := NewSettingsService(repository).UpdateSettings(context.Background(), "user1", map[string]any{KeyShowSpoilers: true}, version(2))
+		require.NoError(t, err)
+		assert.Equal(t, 3, result.Version)
+		assert.True(t, result.Bool(KeyShowSpoilers))
+		assert.Equal(t, "dark", result.String(KeyTheme))
+	})
+
+	t.Run("rejects unknown keys", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		repository := mocks.NewMockUserSettingsRepository(ctrl)
+
+		_, err := NewSettingsService(repository).UpdateSettings(context.Background(), "user1", map[string]any{"volume": 11}, nil)
+		assertErrorCode(t, err, SettingsErrorInvalid)
+	})
+
+	t.Run("rejects invalid values", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		repository := mocks.NewMockUserSettingsRepository(ctrl)
+
+		_, err := NewSettingsService(repository).UpdateSettings(context.Background(), "user1", map[string]any{KeyTheme: "neon"}, nil)
+		assertErrorCode(t, err, SettingsErrorInvalid)
+	})
+
+	t.Run("fails when the expected version is stale", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		repository := mocks.NewMockUserSettingsRepository(ctrl)
+		repository.EXPECT().GetUserSettings(gomock.Any(), "user1").Return(&models.UserSettings{UserID: "user1", Version: 5}, nil)
+
+		_, err := NewSettingsService(repository).UpdateSettings(context.Background(), "user1", map[string]any{KeyTheme: "light"}, version(4))
+		assertErrorCode(t, err, SettingsErrorVersionConflict)
+	})
+
+	t.Run("fails when a concurrent write wins and a version was expected", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		repository := mocks.NewMockUserSettingsRepository(ctrl)
+		repository.EXPECT().GetUserSettings(gomock.Any(), "user1").Return(nil, nil)
+		repository.EXPECT().SaveUserSettings(gomock.Any(), "user1", 0, gomock.Any()).Return(nil, repositories.ErrVersionConflict)
+
+		_, err := NewSettingsService(repository).UpdateSettings(context.Background(), "user1", map[string]any{KeyTheme: "light"}, version(0))
+		assertErrorCode(t, err, SettingsErrorVersionConflict)
+	})
+
+	t.Run("retries an unversioned update on top of the concurrent write", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		repository := mocks.NewMockUserSettingsRepository(ctrl)
+		gomock.InOrder(
+			repository.EXPECT().GetUserSettings(gomock.Any(), "user1").Return(nil, nil),
+			repository.EXPECT().SaveUserSettings(gomock.Any(), "user1", 0, gomock.Any()).Return(nil, repositories.ErrVersionConflict),
+			repository.EXPECT().GetUserSettings(gomock.Any(), "user1").Return(&models.UserSettings{
+				UserID:  "user1",
+				Version: 1,
+				Values:  models.Values{KeyShowNSFW: true},
+			}, nil),
+			repository.EXPECT().SaveUserSettings(gomock.Any(), "user1", 1, models.Values{KeyShowNSFW: true, KeyTheme: "light"}).
+				Return(&models.UserSettings{UserID: "user1", Version: 2, Values: models.Values{KeyShowNSFW: true, KeyTheme: "light"}}, nil),
+		)
+
+		result, err := NewSettingsService(repository).UpdateSettings(context.Background(), "user1", map[string]any{KeyTheme: "light"}, nil)
+		require.NoError(t, err)
+		assert.Equal(t, 2, result.Version)
+		assert.True(t, result.Bool(KeyShowNSFW))
+	})
+
+	t.Run("surfaces repository errors as internal errors", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		repository := mocks.NewMockUserSettingsRepository(ctrl)
+		repository.EXPECT().GetUserSettings(gomock.Any(), "user1").Return(nil, errors.New("connection refused"))
+
+		_, err := NewSettingsService(repository).UpdateSettings(context.Background(), "user1", map[string]any{KeyTheme: "light"}, nil)
+		assertErrorCode(t, err, SettingsErrorInternalError)
+	})
+}
+
+func assertErrorCode(t *testing.T, err error, code ErrorCode) {
+	t.Helper()
+
+	var settingsErr *Error
+	require.True(t, errors.As(err, &settingsErr), "expected settings error, got %v", err)
+	assert.Equal(t, code, settingsErr.Code)
+}
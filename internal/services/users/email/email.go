@@ -0,0 +1,99 @@
+package email
+
+import (
+	"net/mail"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+const (
+	// MaxLength is the longest address that fits in an SMTP forward path.
+	MaxLength      = 254
+	maxLocalLength = 64
+	maxLabelLength = 63
+)
+
+// InvalidError explains why an email address was rejected.
+type InvalidError struct {
+	Reason string
+}
+
+func (e *InvalidError) Error() string {
+	return "invalid email: " + e.Reason
+}
+
+// Validate checks that value is a plain addr-spec such as "name@example.com" and returns it with
+// surrounding whitespace removed and the domain lowercased. Only the syntax is checked; nothing
+// is looked up in DNS, so whether the domain accepts mail is left to verification.
+func Validate(value string) (string, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return "", &InvalidError{Reason: "must not be empty"}
+	}
+	if len(value) > MaxLength {
+		return "", &InvalidError{Reason: "is too long"}
+	}
+
+	// ParseAddress also accepts display names and comments, so insist that nothing but the
+	// address itself was given.
+	address, err := mail.ParseAddress(value)
+	if err != nil || address.Name != "" || address.Address != value {
+		return "", &InvalidError{Reason: "is not a valid address"}
+	}
+
+	at := strings.LastIndexByte(value, '@')
+	local, domain := value[:at], value[at+1:]
+	if strings.HasPrefix(local, `"`) {
+		return "", &InvalidError{Reason: "quoted local parts are not supported"}
+	}
+	if len(local) > maxLocalLength {
+		return "", &InvalidError{Reason: "local part is too long"}
+	}
+
+	domain = strings.ToLower(domain)
+	if reason := checkDomain(domain); reason != "" {
+		return "", &InvalidError{Reason: reason}
+	}
+
+	return local + "@" + domain, nil
+}
+
+// Normalize returns the key two addresses share when they reach the same mailbox, used to keep
+// emails unique and to look them up regardless of case. The whole address is lowercased because
+// providers treat local parts case-insensitively in practice; provider-specific rules such as
+// ignoring dots or "+tag" are deliberately not applied.
+func Normalize(value string) string {
+	return strings.ToLower(norm.NFC.String(strings.TrimSpace(value)))
+}
+
+// checkDomain returns why domain cannot receive mail on the public internet, or "" if it can.
+func checkDomain(domain string) string {
+	labels := strings.Split(domain, ".")
+	if len(labels) < 2 {
+		return "domain must contain a dot"
+	}
+
+	for _, label := range labels {
+		if label == "" || utf8.RuneCountInString(label) > maxLabelLength {
+			return "domain has an empty or overlong label"
+		}
+		if strings.HasPrefix(label, "-") || strings.HasSuffix(label, "-") {
+			return "domain labels must not start or end with a hyphen"
+		}
+		for _, r := range label {
+			if r != '-' && !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+				return "domain contains an invalid character"
+			}
+		}
+	}
+
+	tld := labels[len(labels)-1]
+	if strings.IndexFunc(tld, func(r rune) bool { return !unicode.IsDigit(r) }) < 0 {
+		return "domain must not be an IP address"
+	}
+
+	return ""
+}
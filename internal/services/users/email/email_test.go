@@ -0,0 +1,56 @@
+package email_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/weeb-vip/user-service/internal/services/users/email"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    string
+		wantErr bool
+	}{
+		{name: "plain address", value: "user@example.com", want: "user@example.com"},
+		{name: "trims and lowercases the domain", value: "  User.Name+tag@Example.COM ", want: "User.Name+tag@example.com"},
+		{name: "subdomain", value: "a@mail.example.co.jp", want: "a@mail.example.co.jp"},
+		{name: "internationalized domain", value: "a@例え.jp", want: "a@例え.jp"},
+		{name: "empty", value: "  ", wantErr: true},
+		{name: "missing at", value: "user.example.com", wantErr: true},
+		{name: "missing local part", value: "@example.com", wantErr: true},
+		{name: "display name", value: "User <user@example.com>", wantErr: true},
+		{name: "quoted local part", value: `"a@b"@example.com`, wantErr: true},
+		{name: "domain without dot", value: "user@localhost", wantErr: true},
+		{name: "ip literal", value: "user@[127.0.0.1]", wantErr: true},
+		{name: "numeric tld", value: "user@127.0.0.1", wantErr: true},
+		{name: "hyphen at label edge", value: "user@-example.com", wantErr: true},
+		{name: "empty label", value: "user@example..com", wantErr: true},
+		{name: "underscore in domain", value: "user@exa_mple.com", wantErr: true},
+		{name: "local part too long", value: strings.Repeat("a", 65) + "@example.com", wantErr: true},
+		{name: "address too long", value: "a@" + strings.Repeat("b", 60) + "." + strings.Repeat("c.", 100) + "com", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := email.Validate(tt.value)
+			if tt.wantErr {
+				var invalid *email.InvalidError
+				assert.ErrorAs(t, err, &invalid)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	assert.Equal(t, "user.name+tag@example.com", email.Normalize(" User.Name+Tag@Example.COM "))
+	assert.Equal(t, email.Normalize("Jose\u0301@example.com"), email.Normalize("jos\u00e9@example.com"))
+	assert.NotEqual(t, email.Normalize("u.ser@example.com"), email.Normalize("user@example.com"))
+}
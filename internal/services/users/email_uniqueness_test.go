@@ -0,0 +1,64 @@
+package users_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/weeb-vip/user-service/internal/db"
+	"github.com/weeb-vip/user-service/internal/services/users"
+	"github.com/weeb-vip/user-service/internal/services/users/models"
+	"github.com/weeb-vip/user-service/internal/services/users/repositories"
+	"github.com/weeb-vip/user-service/mocks"
+	"go.uber.org/mock/gomock"
+)
+
+func TestUsersService_EmailUniqueness(t *testing.T) {
+	newService := func(t *testing.T) (users.User, *mocks.MockUsersRepository) {
+		repository := mocks.NewMockUsersRepository(gomock.NewController(t))
+
+		return users.NewTestUserService(repository, time.Now()), repository
+	}
+	owner := &models.User{BaseModel: db.BaseModel{ID: "1"}}
+
+	t.Run("update to an email held by another user is rejected", func(t *testing.T) {
+		service, repository := newService(t)
+		address := "Taken@Example.com"
+		repository.EXPECT().GetUserByEmail(gomock.Any(), "Taken@example.com").
+			Return(&models.User{BaseModel: db.BaseModel{ID: "2"}}, nil)
+
+		_, err := service.UpdateUser(context.Background(), "1", nil, nil, nil, nil, &address)
+		assertErrorCode(t, err, users.UserErrorEmailTaken)
+	})
+
+	t.Run("update to the user's own email is allowed", func(t *testing.T) {
+		service, repository := newService(t)
+		address := "own@example.com"
+		repository.EXPECT().GetUserByEmail(gomock.Any(), address).Return(owner, nil)
+		repository.EXPECT().UpdateUser(gomock.Any(), "1", nil, nil, nil, nil, &address).Return(owner, nil)
+
+		_, err := service.UpdateUser(context.Background(), "1", nil, nil, nil, nil, &address)
+		assert.NoError(t, err)
+	})
+
+	t.Run("email claimed concurrently is reported as taken", func(t *testing.T) {
+		service, repository := newService(t)
+		address := "raced@example.com"
+		repository.EXPECT().GetUserByEmail(gomock.Any(), address).Return(nil, nil)
+		repository.EXPECT().UpdateUser(gomock.Any(), "1", nil, nil, nil, nil, &address).Return(nil, repositories.ErrEmailTaken)
+
+		_, err := service.UpdateUser(context.Background(), "1", nil, nil, nil, nil, &address)
+		assertErrorCode(t, err, users.UserErrorEmailTaken)
+	})
+
+	t.Run("new user with a taken email is rejected", func(t *testing.T) {
+		service, repository := newService(t)
+		address := "taken@example.com"
+		repository.EXPECT().GetUserByEmail(gomock.Any(), address).
+			Return(&models.User{BaseModel: db.BaseModel{ID: "2"}}, nil)
+
+		_, err := service.AddUser(context.Background(), "1", "new_user", "first", "last", "en", &address)
+		assertErrorCode(t, err, users.UserErrorEmailTaken)
+	})
+}
@@ -0,0 +1,168 @@
+package users_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	jwtlib "github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/weeb-vip/user-service/config"
+	"github.com/weeb-vip/user-service/internal/db"
+	"github.com/weeb-vip/user-service/internal/events"
+	"github.com/weeb-vip/user-service/internal/jwt"
+	"github.com/weeb-vip/user-service/internal/keypair"
+	"github.com/weeb-vip/user-service/internal/services/users"
+	"github.com/weeb-vip/user-service/internal/services/users/models"
+	"github.com/weeb-vip/user-service/mocks"
+	"go.uber.org/mock/gomock"
+)
+
+type staticSigningKey struct {
+	key keypair.SigningKey
+}
+
+func (s staticSigningKey) Rotate() {}
+
+func (s staticSigningKey) RotateInBackground(context.Context, time.Duration) {}
+
+func (s staticSigningKey) GetLatest() keypair.SigningKey {
+	return s.key
+}
+
+func (s staticSigningKey) PublicKeys() []keypair.PublicKey {
+	return nil
+}
+
+type recordingPublisher struct {
+	published []events.Event
+}
+
+func (p *recordingPublisher) Publish(_ context.Context, _ string, event events.Event) error {
+	p.published = append(p.published, event)
+
+	return nil
+}
+
+func newTokenizer(t *testing.T) jwt.Tokenizer {
+	pair, err := keypair.GenerateKeyPair(keypair.AlgorithmRS256)
+	require.NoError(t, err)
+
+	return jwt.New(staticSigningKey{key: keypair.SigningKey{Key: pair.PrivateKey, ID: "test"}})
+}
+
+func newVerificationService(
+	t *testing.T,
+	tokenizer jwt.Tokenizer,
+	now time.Time,
+) (users.User, *mocks.MockUsersRepository, *recordingPublisher) {
+	repository := mocks.NewMockUsersRepository(gomock.NewController(t))
+	publisher := &recordingPublisher{}
+	service := users.NewTestUserService(repository, now,
+		users.WithEmailVerification(tokenizer, publisher, config.EmailConfig{VerificationTTLHours: 24}))
+
+	return service, repository, publisher
+}
+
+func assertErrorCode(t *testing.T, err error, code users.ErrorCode) {
+	var userErr *users.Error
+	require.True(t, errors.As(err, &userErr), "expected *users.Error, got %v", err)
+	assert.Equal(t, code, userErr.Code)
+}
+
+func TestUsersService_EmailVerification(t *testing.T) {
+	address := "user@example.com"
+	user := &models.User{BaseModel: db.BaseModel{ID: "1"}, Email: &address}
+
+	t.Run("issued token verifies the email", func(t *testing.T) {
+		service, repository, publisher := newVerificationService(t, newTokenizer(t), time.Now())
+
+		var storedHash string
+		repository.EXPECT().GetUserById(gomock.Any(), "1").Return(user, nil)
+		repository.EXPECT().SetEmailVerificationToken(gomock.Any(), "1", address, gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ string, _ string, hash string) (bool, error) {
+				storedHash = hash
+				return true, nil
+			})
+
+		require.NoError(t, service.RequestEmailVerification(context.Background(), "1"))
+		require.Len(t, publisher.published, 1)
+		event, ok := publisher.published[0].(events.EmailVerificationRequested)
+		require.True(t, ok)
+		assert.Equal(t, address, event.Email)
+
+		claims := jwtlib.MapClaims{}
+		_, _, err := jwtlib.NewParser().ParseUnverified(event.Token, claims)
+		require.NoError(t, err)
+		assert.Equal(t, jwt.EmailVerificationAudience, claims["aud"])
+
+		verifiedAt := time.Now()
+		repository.EXPECT().MarkEmailVerified(gomock.Any(), "1", storedHash).Return(true, nil)
+		repository.EXPECT().GetUserById(gomock.Any(), "1").
+			Return(&models.User{BaseModel: db.BaseModel{ID: "1"}, Email: &address, EmailVerifiedAt: &verifiedAt}, nil)
+
+		verified, err := service.VerifyEmail(context.Background(), event.Token)
+		require.NoError(t, err)
+		assert.NotNil(t, verified.EmailVerifiedAt)
+	})
+
+	t.Run("already verified email is rejected", func(t *testing.T) {
+		service, repository, publisher := newVerificationService(t, newTokenizer(t), time.Now())
+		verifiedAt := time.Now()
+		repository.EXPECT().GetUserById(gomock.Any(), "1").
+			Return(&models.User{BaseModel: db.BaseModel{ID: "1"}, Email: &address, EmailVerifiedAt: &verifiedAt}, nil)
+
+		assertErrorCode(t, service.RequestEmailVerification(context.Background(), "1"), users.UserErrorEmailAlreadyVerified)
+		assert.Empty(t, publisher.published)
+	})
+
+	t.Run("user without email is rejected", func(t *testing.T) {
+		service, repository, _ := newVerificationService(t, newTokenizer(t), time.Now())
+		repository.EXPECT().GetUserById(gomock.Any(), "1").Return(&models.User{BaseModel: db.BaseModel{ID: "1"}}, nil)
+
+		assertErrorCode(t, service.RequestEmailVerification(context.Background(), "1"), users.UserErrorEmailMissing)
+	})
+
+	t.Run("token for another purpose is rejected", func(t *testing.T) {
+		tokenizer := newTokenizer(t)
+		service, _, _ := newVerificationService(t, tokenizer, time.Now())
+		token, err := tokenizer.Tokenize(jwt.Claims{Subject: &user.ID})
+		require.NoError(t, err)
+
+		_, err = service.VerifyEmail(context.Background(), token)
+		assertErrorCode(t, err, users.UserErrorEmailVerificationInvalid)
+	})
+
+	t.Run("expired token is rejected", func(t *testing.T) {
+		tokenizer := newTokenizer(t)
+		service, _, _ := newVerificationService(t, tokenizer, time.Now().Add(48*time.Hour))
+		purpose := jwt.PurposeEmailVerification
+		token, err := tokenizer.Tokenize(jwt.Claims{Subject: &user.ID, Purpose: &purpose})
+		require.NoError(t, err)
+
+		_, err = service.VerifyEmail(context.Background(), token)
+		assertErrorCode(t, err, users.UserErrorEmailVerificationInvalid)
+	})
+
+	t.Run("token that is no longer outstanding is rejected", func(t *testing.T) {
+		tokenizer := newTokenizer(t)
+		service, repository, _ := newVerificationService(t, tokenizer, time.Now())
+		purpose := jwt.PurposeEmailVerification
+		token, err := tokenizer.Tokenize(jwt.Claims{Subject: &user.ID, Purpose: &purpose})
+		require.NoError(t, err)
+		repository.EXPECT().MarkEmailVerified(gomock.Any(), "1", gomock.Any()).Return(false, nil)
+
+		_, err = service.VerifyEmail(context.Background(), token)
+		assertErrorCode(t, err, users.UserErrorEmailVerificationInvalid)
+	})
+
+	t.Run("invalid email is rejected on update", func(t *testing.T) {
+		service, _, _ := newVerificationService(t, newTokenizer(t), time.Now())
+		invalid := "not-an-email"
+
+		_, err := service.UpdateUser(context.Background(), "1", nil, nil, nil, nil, &invalid)
+		assertErrorCode(t, err, users.UserErrorEmailInvalid)
+	})
+}
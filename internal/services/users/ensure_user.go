@@ -0,0 +1,149 @@
+package users
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/weeb-vip/user-service/internal/locale"
+	"github.com/weeb-vip/user-service/internal/services/users/models"
+	"github.com/weeb-vip/user-service/internal/services/users/repositories"
+	"github.com/weeb-vip/user-service/internal/services/users/username"
+	"github.com/weeb-vip/user-service/metrics"
+	"github.com/weeb-vip/user-service/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// maxPlaceholderAttempts bounds how many placeholder usernames are tried for one account.
+const maxPlaceholderAttempts = 5
+
+func (service *usersService) EnsureUser(
+	ctx context.Context,
+	id string,
+	email *string,
+	language string,
+) (*models.User, bool, error) {
+	tracer := tracing.GetTracer(ctx)
+	ctx, span := tracer.Start(ctx, "service.EnsureUser",
+		trace.WithAttributes(
+			attribute.String("user.id", id),
+			attribute.String("service", "users"),
+			attribute.String("method", "EnsureUser"),
+		),
+		tracing.GetEnvironmentAttribute(),
+	)
+	defer span.End()
+
+	startTime := time.Now()
+
+	user, created, err := service.ensureUser(ctx, id, email, language)
+
+	metricResult := metrics.Success
+	if err != nil {
+		metricResult = metrics.Error
+	}
+	metrics.GetAppMetrics().ServiceMetric(
+		float64(time.Since(startTime).Milliseconds()),
+		"users",
+		"EnsureUser",
+		metricResult,
+	)
+
+	span.SetAttributes(attribute.Bool("user.created", created))
+
+	return user, created, err
+}
+
+func (service *usersService) ensureUser(ctx context.Context, id string, email *string, language string) (*models.User, bool, error) {
+	email, err := validateEmail(email)
+	if err != nil {
+		return nil, false, err
+	}
+
+	existing, err := service.usersRepository.GetUserById(ctx, id)
+	if err != nil {
+		return nil, false, &Error{
+			Code:    UserErrorInternalError,
+			Message: "database error",
+		}
+	}
+	if existing != nil && existing.ID != "" {
+		user, err := service.mergeEmail(ctx, existing, email)
+		return user, false, err
+	}
+
+	// Events may carry any locale, so fall back to the closest supported one rather than failing
+	language = locale.Resolve(language)
+
+	for attempt := 0; attempt < maxPlaceholderAttempts; attempt++ {
+		seed := id
+		if attempt > 0 {
+			seed = fmt.Sprintf("%s#%d", id, attempt)
+		}
+		placeholder := username.Placeholder(seed)
+
+		err := service.checkUsernameClaim(ctx, id, placeholder)
+		var userErr *Error
+		if errors.As(err, &userErr) && userErr.Code == UserErrorUsernameTaken {
+			continue
+		}
+		if err != nil {
+			return nil, false, err
+		}
+
+		// The address can be added later, so an email held by someone else doesn't block the account
+		if service.checkEmailClaim(ctx, id, email) != nil {
+			email = nil
+		}
+
+		user, err := service.usersRepository.AddUser(ctx, placeholder, id, "", "", language, email)
+		switch {
+		case err == nil:
+			return user, true, nil
+		case errors.Is(err, repositories.ErrUserExists):
+			// Another delivery of the same event won the race
+			existing, err := service.usersRepository.GetUserById(ctx, id)
+			if err != nil {
+				return nil, false, translateRepositoryError(err)
+			}
+			user, err := service.mergeEmail(ctx, existing, email)
+			return user, false, err
+		case errors.Is(err, repositories.ErrEmailTaken):
+			// Claimed between the check and the insert, so retry the same placeholder without it
+			email = nil
+			attempt--
+		case errors.Is(err, repositories.ErrUsernameTaken):
+			continue
+		default:
+			return nil, false, translateRepositoryError(err)
+		}
+	}
+
+	return nil, false, &Error{
+		Code:    UserErrorUsernameTaken,
+		Message: "could not allocate a placeholder username",
+	}
+}
+
+// mergeEmail gives user email when they have none yet. An email the user already has is never
+// overwritten, since it may have been changed after the event was produced.
+func (service *usersService) mergeEmail(ctx context.Context, user *models.User, email *string) (*models.User, error) {
+	if email == nil || user.Email != nil {
+		return user, nil
+	}
+	if service.checkEmailClaim(ctx, user.ID, email) != nil {
+		return user, nil
+	}
+
+	updated, err := service.usersRepository.UpdateUser(ctx, user.ID, nil, nil, nil, nil, email)
+	if errors.Is(err, repositories.ErrEmailTaken) {
+		return user, nil
+	}
+	if err != nil {
+		return nil, translateRepositoryError(err)
+	}
+
+	return updated, nil
+}
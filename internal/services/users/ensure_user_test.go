@@ -0,0 +1,251 @@
+package users_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/weeb-vip/user-service/internal/db"
+	"github.com/weeb-vip/user-service/internal/services/users"
+	"github.com/weeb-vip/user-service/internal/services/users/email"
+	"github.com/weeb-vip/user-service/internal/services/users/models"
+	"github.com/weeb-vip/user-service/internal/services/users/repositories"
+	"github.com/weeb-vip/user-service/internal/services/users/username"
+)
+
+// memoryRepository keeps users in memory with the same uniqueness rules as the users table.
+// Methods EnsureUser doesn't need are left to the embedded nil interface and panic if called.
+type memoryRepository struct {
+	repositories.UsersRepository
+	users map[string]*models.User
+}
+
+func newMemoryRepository(existing ...*models.User) *memoryRepository {
+	repository := &memoryRepository{users: map[string]*models.User{}}
+	for _, user := range existing {
+		repository.users[user.ID] = user
+	}
+
+	return repository
+}
+
+func (m *memoryRepository) GetUserById(_ context.Context, id string) (*models.User, error) {
+	if user, ok := m.users[id]; ok {
+		copied := *user
+		return &copied, nil
+	}
+
+	return &models.User{}, nil
+}
+
+func (m *memoryRepository) GetUserByUsername(_ context.Context, value string) (*models.User, error) {
+	for _, user := range m.users {
+		if username.Normalize(user.Username) == username.Normalize(value) {
+			return user, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func (m *memoryRepository) GetUserByEmail(_ context.Context, value string) (*models.User, error) {
+	for _, user := range m.users {
+		if user.Email != nil && email.Normalize(*user.Email) == email.Normalize(value) {
+			return user, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func (m *memoryRepository) GetLatestUsernameRelease(context.Context, string) (*models.UsernameHistory, error) {
+	return nil, nil
+}
+
+func (m *memoryRepository) AddUser(
+	ctx context.Context,
+	name string,
+	id string,
+	firstName string,
+	lastName string,
+	language string,
+	address *string,
+) (*models.User, error) {
+	if _, ok := m.users[id]; ok {
+		return nil, repositories.ErrUserExists
+	}
+	if holder, _ := m.GetUserByUsername(ctx, name); holder != nil {
+		return nil, repositories.ErrUsernameTaken
+	}
+	if address != nil {
+		if holder, _ := m.GetUserByEmail(ctx, *address); holder != nil {
+			return nil, repositories.ErrEmailTaken
+		}
+	}
+
+	m.users[id] = &models.User{
+		BaseModel: db.BaseModel{ID: id},
+		Username:  name,
+		FirstName: firstName,
+		LastName:  lastName,
+		Language:  language,
+		Email:     address,
+	}
+
+	return m.GetUserById(ctx, id)
+}
+
+func (m *memoryRepository) UpdateUser(
+	ctx context.Context,
+	id string,
+	_ *string,
+	_ *string,
+	_ *string,
+	_ *string,
+	address *string,
+) (*models.User, error) {
+	if address != nil {
+		if holder, _ := m.GetUserByEmail(ctx, *address); holder != nil && holder.ID != id {
+			return nil, repositories.ErrEmailTaken
+		}
+		m.users[id].Email = address
+	}
+
+	return m.GetUserById(ctx, id)
+}
+
+type userCreatedEvent struct {
+	id     string
+	email  string
+	locale string
+}
+
+func TestUsersService_EnsureUser(t *testing.T) {
+	strPtr := func(value string) *string { return &value }
+
+	tests := []struct {
+		name         string
+		existing     []*models.User
+		events       []userCreatedEvent
+		wantCreated  []bool
+		wantUsername string
+		wantEmail    *string
+		wantLanguage string
+		wantUsers    int
+	}{
+		{
+			name:         "first event creates the user",
+			events:       []userCreatedEvent{{id: "1", email: "a@example.com", locale: "pt-BR"}},
+			wantCreated:  []bool{true},
+			wantUsername: username.Placeholder("1"),
+			wantEmail:    strPtr("a@example.com"),
+			wantLanguage: "pt-BR",
+			wantUsers:    1,
+		},
+		{
+			name: "duplicate delivery is a no-op",
+			events: []userCreatedEvent{
+				{id: "1", email: "a@example.com", locale: "en"},
+				{id: "1", email: "a@example.com", locale: "en"},
+			},
+			wantCreated:  []bool{true, false},
+			wantUsername: username.Placeholder("1"),
+			wantEmail:    strPtr("a@example.com"),
+			wantLanguage: "en",
+			wantUsers:    1,
+		},
+		{
+			name: "event without email followed by one with email fills it in",
+			events: []userCreatedEvent{
+				{id: "1", locale: "ja"},
+				{id: "1", email: "a@example.com", locale: "ja"},
+			},
+			wantCreated:  []bool{true, false},
+			wantUsername: username.Placeholder("1"),
+			wantEmail:    strPtr("a@example.com"),
+			wantLanguage: "ja",
+			wantUsers:    1,
+		},
+		{
+			name: "user created through the API before the event keeps their profile",
+			existing: []*models.User{
+				{BaseModel: db.BaseModel{ID: "1"}, Username: "chosen", Language: "th"},
+			},
+			events:       []userCreatedEvent{{id: "1", email: "a@example.com", locale: "en"}},
+			wantCreated:  []bool{false},
+			wantUsername: "chosen",
+			wantEmail:    strPtr("a@example.com"),
+			wantLanguage: "th",
+			wantUsers:    1,
+		},
+		{
+			name: "replayed event does not overwrite a changed email",
+			existing: []*models.User{
+				{BaseModel: db.BaseModel{ID: "1"}, Username: "chosen", Email: strPtr("new@example.com"), Language: "en"},
+			},
+			events:       []userCreatedEvent{{id: "1", email: "old@example.com", locale: "en"}},
+			wantCreated:  []bool{false},
+			wantUsername: "chosen",
+			wantEmail:    strPtr("new@example.com"),
+			wantLanguage: "en",
+			wantUsers:    1,
+		},
+		{
+			name: "placeholder held by another user moves to the next one",
+			existing: []*models.User{
+				{BaseModel: db.BaseModel{ID: "2"}, Username: username.Placeholder("1")},
+			},
+			events:       []userCreatedEvent{{id: "1", locale: "en"}},
+			wantCreated:  []bool{true},
+			wantUsername: username.Placeholder("1#1"),
+			wantLanguage: "en",
+			wantUsers:    2,
+		},
+		{
+			name: "email held by another user is left off",
+			existing: []*models.User{
+				{BaseModel: db.BaseModel{ID: "2"}, Username: "other", Email: strPtr("A@example.com")},
+			},
+			events:       []userCreatedEvent{{id: "1", email: "a@example.com", locale: "en"}},
+			wantCreated:  []bool{true},
+			wantUsername: username.Placeholder("1"),
+			wantLanguage: "en",
+			wantUsers:    2,
+		},
+		{
+			name:         "unsupported locale falls back to the default",
+			events:       []userCreatedEvent{{id: "1", locale: "xx-YY"}},
+			wantCreated:  []bool{true},
+			wantUsername: username.Placeholder("1"),
+			wantLanguage: "en",
+			wantUsers:    1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repository := newMemoryRepository(tt.existing...)
+			service := users.NewTestUserService(repository, time.Now())
+
+			for i, event := range tt.events {
+				var address *string
+				if event.email != "" {
+					address = &event.email
+				}
+
+				_, created, err := service.EnsureUser(context.Background(), event.id, address, event.locale)
+				require.NoError(t, err)
+				assert.Equal(t, tt.wantCreated[i], created, "event %d", i)
+			}
+
+			user := repository.users["1"]
+			require.NotNil(t, user)
+			assert.Equal(t, tt.wantUsername, user.Username)
+			assert.Equal(t, tt.wantEmail, user.Email)
+			assert.Equal(t, tt.wantLanguage, user.Language)
+			assert.Len(t, repository.users, tt.wantUsers)
+		})
+	}
+}
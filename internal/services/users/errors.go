@@ -1,9 +1,18 @@
 package users
 
 const (
-	UserErrorInternalError ErrorCode = "INTERNAL_ERROR"      // nolint
-	UserErrorUserExists    ErrorCode = "USER_EXISTS"         // nolint
-	UserErrorInvalidUsers  ErrorCode = "INVALID_CREDENTIALS" // nolint
+	UserErrorInternalError            ErrorCode = "INTERNAL_ERROR"             // nolint
+	UserErrorUserExists               ErrorCode = "USER_EXISTS"                // nolint
+	UserErrorInvalidUsers             ErrorCode = "INVALID_CREDENTIALS"        // nolint
+	UserErrorUnsupportedLanguage      ErrorCode = "UNSUPPORTED_LANGUAGE"       // nolint
+	UserErrorUsernameTaken            ErrorCode = "USERNAME_TAKEN"             // nolint
+	UserErrorUsernameInvalid          ErrorCode = "USERNAME_INVALID"           // nolint
+	UserErrorUsernameChangeTooSoon    ErrorCode = "USERNAME_CHANGE_TOO_SOON"   // nolint
+	UserErrorEmailTaken               ErrorCode = "EMAIL_TAKEN"                // nolint
+	UserErrorEmailInvalid             ErrorCode = "EMAIL_INVALID"              // nolint
+	UserErrorEmailMissing             ErrorCode = "EMAIL_MISSING"              // nolint
+	UserErrorEmailAlreadyVerified     ErrorCode = "EMAIL_ALREADY_VERIFIED"     // nolint
+	UserErrorEmailVerificationInvalid ErrorCode = "EMAIL_VERIFICATION_INVALID" // nolint
 )
 
 type ErrorCode string
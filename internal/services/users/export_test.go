@@ -0,0 +1,25 @@
+package users
+
+import (
+	"time"
+
+	"github.com/weeb-vip/user-service/internal/services/users/repositories"
+	"github.com/weeb-vip/user-service/internal/services/users/username"
+)
+
+// NewTestUserService builds the service around repository with a fixed clock.
+func NewTestUserService(repository repositories.UsersRepository, now time.Time, opts ...Option) User {
+	service := &usersService{
+		usersRepository:      repository,
+		usernamePolicy:       username.DefaultPolicy(),
+		releaseCooldown:      defaultReleaseCooldown,
+		renameInterval:       defaultRenameInterval,
+		emailVerificationTTL: defaultEmailVerificationTTL,
+		now:                  func() time.Time { return now },
+	}
+	for _, opt := range opts {
+		opt(service)
+	}
+
+	return service
+}
@@ -7,8 +7,36 @@ import (
 )
 
 type User interface {
-	AddUser(ctx context.Context, id string, username string, firstName string, lastName string, language string) (*models.User, error)
+	AddUser(ctx context.Context, id string, username string, firstName string, lastName string, language string, email *string) (*models.User, error)
+	// EnsureUser creates the user with a placeholder username unless they already exist, and gives
+	// them email if they have none. Repeating the call is harmless. created reports whether the user is new.
+	EnsureUser(ctx context.Context, id string, email *string, language string) (user *models.User, created bool, err error)
 	GetUserDetails(ctx context.Context, id string) (*models.User, error)
+	// GetUserByUsername finds the user holding username. When nobody does, it falls back to the
+	// user who most recently renamed away from it and reports redirected. It returns nil if neither exists.
+	GetUserByUsername(ctx context.Context, username string) (user *models.User, redirected bool, err error)
+	// GetUserByEmail finds the user holding email, compared case-insensitively, or returns nil.
+	GetUserByEmail(ctx context.Context, email string) (*models.User, error)
 	UpdateUser(ctx context.Context, id string, username *string, firstName *string, lastName *string, language *string, email *string) (*models.User, error)
 	UpdateProfileImageURL(ctx context.Context, id string, profileImageURL string) (*models.User, error)
+	// DeleteUser removes the user. Deleting a user that doesn't exist succeeds.
+	DeleteUser(ctx context.Context, id string) error
+	// RequestEmailVerification issues a token proving ownership of the user's current email and
+	// publishes it for delivery. Any earlier token stops working.
+	RequestEmailVerification(ctx context.Context, id string) error
+	// VerifyEmail marks the email of the token's user as verified and returns the user.
+	VerifyEmail(ctx context.Context, token string) (*models.User, error)
+	// CheckUsername applies the same rules as AddUser to username and, when it cannot be used,
+	// suggests available alternatives built from it and the optional first and last name.
+	CheckUsername(ctx context.Context, username string, firstName string, lastName string) (*UsernameAvailability, error)
+}
+
+type UsernameAvailability struct {
+	// Username is the form that would be stored, or the input as given when it is invalid.
+	Username  string
+	Available bool
+	// Reason is UserErrorUsernameTaken or UserErrorUsernameInvalid when the username is unavailable.
+	Reason      *ErrorCode
+	Message     *string
+	Suggestions []string
 }
@@ -1,15 +1,26 @@
 package models
 
 import (
+	"time"
+
 	"github.com/weeb-vip/user-service/internal/db"
 )
 
 type User struct {
 	db.BaseModel
-	Username       string  `json:"username"`
-	FirstName      string  `json:"first_name"`
-	LastName       string  `json:"last_name"`
-	Language       string  `json:"language"`
-	Email          *string `json:"email"`
-	ProfileImageURL *string `json:"profile_image_url" gorm:"column:profile_image_url"`
+	Username string `json:"username"`
+	// UsernameNormalized is the uniqueness key for Username, see username.Normalize. It is nil
+	// while the user has no username.
+	UsernameNormalized *string `json:"-" gorm:"column:username_normalized"`
+	FirstName          string  `json:"first_name"`
+	LastName           string  `json:"last_name"`
+	Language           string  `json:"language"`
+	Email              *string `json:"email"`
+	// EmailNormalized is the uniqueness key for Email, see email.Normalize.
+	EmailNormalized *string `json:"-" gorm:"column:email_normalized"`
+	// EmailVerifiedAt is when the owner proved they receive mail at Email. Changing Email clears it.
+	EmailVerifiedAt *time.Time `json:"email_verified_at" gorm:"column:email_verified_at"`
+	// EmailVerificationTokenHash is the SHA-256 of the outstanding verification token, if any.
+	EmailVerificationTokenHash *string `json:"-" gorm:"column:email_verification_token_hash"`
+	ProfileImageURL            *string `json:"profile_image_url" gorm:"column:profile_image_url"`
 }
@@ -0,0 +1,17 @@
+package models
+
+import (
+	"github.com/weeb-vip/user-service/internal/db"
+)
+
+// UsernameHistory records a username a user gave up by renaming. CreatedAt is when it was released.
+type UsernameHistory struct {
+	db.BaseModel
+	UserID             string `json:"user_id"`
+	Username           string `json:"username"`
+	UsernameNormalized string `json:"-"`
+}
+
+func (UsernameHistory) TableName() string {
+	return "username_history"
+}
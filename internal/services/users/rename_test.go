@@ -0,0 +1,163 @@
+package users_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/weeb-vip/user-service/internal/db"
+	"github.com/weeb-vip/user-service/internal/services/users"
+	"github.com/weeb-vip/user-service/internal/services/users/models"
+	"github.com/weeb-vip/user-service/mocks"
+	"go.uber.org/mock/gomock"
+)
+
+var testNow = time.Date(2025, 9, 22, 12, 0, 0, 0, time.UTC)
+
+func newTestService(t *testing.T) (users.User, *mocks.MockUsersRepository) {
+	repository := mocks.NewMockUsersRepository(gomock.NewController(t))
+
+	return users.NewTestUserService(repository, testNow), repository
+}
+
+func historyAt(userID string, createdAt time.Time) *models.UsernameHistory {
+	return &models.UsernameHistory{
+		BaseModel: db.BaseModel{CreatedAt: createdAt},
+		UserID:    userID,
+	}
+}
+
+func TestUsersService_UpdateUser_Rename(t *testing.T) {
+	current := &models.User{BaseModel: db.BaseModel{ID: "1"}, Username: "old_name", UsernameNormalized: strPtr("old_name")}
+
+	tests := []struct {
+		name     string
+		display  string
+		setup    func(repository *mocks.MockUsersRepository)
+		wantCode users.ErrorCode
+	}{
+		{
+			name:    "case only change skips the limits",
+			display: "Old_Name",
+			setup:   func(repository *mocks.MockUsersRepository) {},
+		},
+		{
+			name:    "first rename is allowed",
+			display: "new_name",
+			setup: func(repository *mocks.MockUsersRepository) {
+				repository.EXPECT().GetLatestUsernameChange(gomock.Any(), "1").Return(nil, nil)
+				repository.EXPECT().GetUserByUsername(gomock.Any(), "new_name").Return(nil, nil)
+				repository.EXPECT().GetLatestUsernameRelease(gomock.Any(), "new_name").Return(nil, nil)
+			},
+		},
+		{
+			name:    "rename inside the interval is rejected",
+			display: "new_name",
+			setup: func(repository *mocks.MockUsersRepository) {
+				repository.EXPECT().GetLatestUsernameChange(gomock.Any(), "1").Return(historyAt("1", testNow.Add(-24*time.Hour)), nil)
+			},
+			wantCode: users.UserErrorUsernameChangeTooSoon,
+		},
+		{
+			name:    "rename after the interval is allowed",
+			display: "new_name",
+			setup: func(repository *mocks.MockUsersRepository) {
+				repository.EXPECT().GetLatestUsernameChange(gomock.Any(), "1").Return(historyAt("1", testNow.Add(-31*24*time.Hour)), nil)
+				repository.EXPECT().GetUserByUsername(gomock.Any(), "new_name").Return(nil, nil)
+				repository.EXPECT().GetLatestUsernameRelease(gomock.Any(), "new_name").Return(nil, nil)
+			},
+		},
+		{
+			name:    "name released by someone else is in cooldown",
+			display: "new_name",
+			setup: func(repository *mocks.MockUsersRepository) {
+				repository.EXPECT().GetLatestUsernameChange(gomock.Any(), "1").Return(nil, nil)
+				repository.EXPECT().GetUserByUsername(gomock.Any(), "new_name").Return(nil, nil)
+				repository.EXPECT().GetLatestUsernameRelease(gomock.Any(), "new_name").Return(historyAt("2", testNow.Add(-time.Hour)), nil)
+			},
+			wantCode: users.UserErrorUsernameTaken,
+		},
+		{
+			name:    "own released name can be reclaimed during cooldown",
+			display: "new_name",
+			setup: func(repository *mocks.MockUsersRepository) {
+				repository.EXPECT().GetLatestUsernameChange(gomock.Any(), "1").Return(historyAt("1", testNow.Add(-31*24*time.Hour)), nil)
+				repository.EXPECT().GetUserByUsername(gomock.Any(), "new_name").Return(nil, nil)
+				repository.EXPECT().GetLatestUsernameRelease(gomock.Any(), "new_name").Return(historyAt("1", testNow.Add(-31*24*time.Hour)), nil)
+			},
+		},
+		{
+			name:    "name held by someone else is taken",
+			display: "new_name",
+			setup: func(repository *mocks.MockUsersRepository) {
+				repository.EXPECT().GetLatestUsernameChange(gomock.Any(), "1").Return(nil, nil)
+				repository.EXPECT().GetUserByUsername(gomock.Any(), "new_name").Return(&models.User{BaseModel: db.BaseModel{ID: "2"}}, nil)
+			},
+			wantCode: users.UserErrorUsernameTaken,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, repository := newTestService(t)
+			repository.EXPECT().GetUserById(gomock.Any(), "1").Return(current, nil)
+			tt.setup(repository)
+			if tt.wantCode == "" {
+				repository.EXPECT().UpdateUser(gomock.Any(), "1", gomock.Any(), nil, nil, nil, nil).Return(current, nil)
+			}
+
+			display := tt.display
+			_, err := service.UpdateUser(context.Background(), "1", &display, nil, nil, nil, nil)
+			if tt.wantCode == "" {
+				assert.NoError(t, err)
+				return
+			}
+
+			var userErr *users.Error
+			require.True(t, errors.As(err, &userErr))
+			assert.Equal(t, tt.wantCode, userErr.Code)
+		})
+	}
+}
+
+func TestUsersService_GetUserByUsername(t *testing.T) {
+	t.Run("current holder wins", func(t *testing.T) {
+		service, repository := newTestService(t)
+		repository.EXPECT().GetUserByUsername(gomock.Any(), "name").Return(&models.User{BaseModel: db.BaseModel{ID: "1"}}, nil)
+
+		user, redirected, err := service.GetUserByUsername(context.Background(), "name")
+		require.NoError(t, err)
+		assert.Equal(t, "1", user.ID)
+		assert.False(t, redirected)
+	})
+
+	t.Run("released name redirects to its last owner", func(t *testing.T) {
+		service, repository := newTestService(t)
+		repository.EXPECT().GetUserByUsername(gomock.Any(), "name").Return(nil, nil)
+		repository.EXPECT().GetLatestUsernameRelease(gomock.Any(), "name").Return(historyAt("1", testNow), nil)
+		repository.EXPECT().GetUserById(gomock.Any(), "1").Return(&models.User{BaseModel: db.BaseModel{ID: "1"}}, nil)
+
+		user, redirected, err := service.GetUserByUsername(context.Background(), "name")
+		require.NoError(t, err)
+		assert.Equal(t, "1", user.ID)
+		assert.True(t, redirected)
+	})
+
+	t.Run("unknown name returns nil", func(t *testing.T) {
+		service, repository := newTestService(t)
+		repository.EXPECT().GetUserByUsername(gomock.Any(), "name").Return(nil, nil)
+		repository.EXPECT().GetLatestUsernameRelease(gomock.Any(), "name").Return(nil, nil)
+
+		user, redirected, err := service.GetUserByUsername(context.Background(), "name")
+		require.NoError(t, err)
+		assert.Nil(t, user)
+		assert.False(t, redirected)
+	})
+}
+
+func strPtr(value string) *string {
+	return &value
+}
@@ -3,18 +3,40 @@ package repositories
 import (
 	"context"
 	"errors"
+	"strings"
 	"time"
 
+	"github.com/go-sql-driver/mysql"
 	"gorm.io/gorm"
 
 	"github.com/weeb-vip/user-service/internal/db"
+	auditModels "github.com/weeb-vip/user-service/internal/services/audit/models"
+	"github.com/weeb-vip/user-service/internal/services/users/email"
 	"github.com/weeb-vip/user-service/internal/services/users/models"
+	"github.com/weeb-vip/user-service/internal/services/users/username"
 	"github.com/weeb-vip/user-service/metrics"
 	"github.com/weeb-vip/user-service/tracing"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
 )
 
+var (
+	// ErrUsernameTaken is returned when another user already holds an equivalent username.
+	ErrUsernameTaken = errors.New("username taken")
+	// ErrUserExists is returned when a user with the same ID already exists.
+	ErrUserExists = errors.New("user exists")
+	// ErrEmailTaken is returned when another user already holds an equivalent email.
+	ErrEmailTaken = errors.New("email taken")
+)
+
+// usernameIndex is the unique index on users.username_normalized.
+const usernameIndex = "idx_users_username_normalized"
+
+// emailIndex is the unique index on users.email_normalized.
+const emailIndex = "idx_users_email_normalized"
+
+const mysqlDuplicateEntry = 1062
+
 type UsersRepository interface {
 	AddUser(
 		ctx context.Context,
@@ -23,12 +45,40 @@ type UsersRepository interface {
 		firstName string,
 		lastName string,
 		language string,
+		email *string,
 	) (*models.User, error)
+	// GetUserByUsername finds the user holding username or any username equivalent to it.
 	GetUserByUsername(ctx context.Context, username string) (*models.User, error)
 	GetUserById(ctx context.Context, id string) (*models.User, error)
+	// GetUserByEmail finds the user holding email, compared case-insensitively.
+	GetUserByEmail(ctx context.Context, email string) (*models.User, error)
+	// FindTakenUsernames returns the normalized keys of the given usernames that are held, or were
+	// released by a rename after releasedSince.
+	FindTakenUsernames(ctx context.Context, usernames []string, releasedSince time.Time) (map[string]bool, error)
+	// UpdateUser applies the given changes. A username change records the old name in username_history
+	// and an email change clears its verification. Changed fields are recorded in the audit log
+	// against the actor of ctx, see auditModels.WithActor.
 	UpdateUser(ctx context.Context, id string, username *string, firstName *string, lastName *string, language *string, email *string) (*models.User, error)
+	// GetLatestUsernameChange returns the user's most recent rename, or nil if they never renamed.
+	GetLatestUsernameChange(ctx context.Context, userID string) (*models.UsernameHistory, error)
+	// GetLatestUsernameRelease returns the most recent rename away from username or an equivalent
+	// name, or nil if nobody ever held it.
+	GetLatestUsernameRelease(ctx context.Context, username string) (*models.UsernameHistory, error)
+	// SetEmailVerificationToken stores tokenHash as the user's outstanding verification token,
+	// provided their email is still email. It reports whether the user was updated.
+	SetEmailVerificationToken(ctx context.Context, id string, email string, tokenHash string) (bool, error)
+	// MarkEmailVerified verifies the user's email if tokenHash is their outstanding token and
+	// consumes the token. It reports whether the user was updated.
+	MarkEmailVerified(ctx context.Context, id string, tokenHash string) (bool, error)
+	// UpdateProfileImageURL sets the profile image and records the change in the audit log.
 	UpdateProfileImageURL(ctx context.Context, id string, profileImageURL string) (*models.User, error)
 	DeleteUser(ctx context.Context, username string) error
+	DeleteUserByID(ctx context.Context, id string) error
+	// BackfillNormalizedUsernames fills username_normalized for users that predate it. Users
+	// whose username collides with one already normalized are left without a key and returned.
+	BackfillNormalizedUsernames(ctx context.Context) (updated int, conflicts []string, err error)
+	// BackfillNormalizedEmails does the same for email_normalized.
+	BackfillNormalizedEmails(ctx context.Context) (updated int, conflicts []string, err error)
 }
 
 type userRepository struct {
@@ -52,6 +102,7 @@ func (repository *userRepository) AddUser(
 	firstName string,
 	lastName string,
 	language string,
+	email *string,
 ) (*models.User, error) {
 	tracer := tracing.GetTracer(ctx)
 	ctx, span := tracer.Start(ctx, "repository.AddUser",
@@ -69,13 +120,16 @@ func (repository *userRepository) AddUser(
 	database := repository.DBService.GetDB()
 
 	credentials := models.User{
-		BaseModel: db.BaseModel{ID: userID},
-		Username:  username,
-		FirstName: firstName,
-		LastName:  lastName,
-		Language:  language,
+		BaseModel:          db.BaseModel{ID: userID},
+		Username:           username,
+		UsernameNormalized: usernameKey(username),
+		FirstName:          firstName,
+		LastName:           lastName,
+		Language:           language,
+		Email:              email,
+		EmailNormalized:    emailKey(email),
 	}
-	err := database.WithContext(ctx).FirstOrCreate(&credentials, models.User{Username: username}).Error
+	err := translateError(database.WithContext(ctx).Create(&credentials).Error)
 
 	// Record database metrics
 	duration := float64(time.Since(start).Nanoseconds()) / float64(time.Millisecond)
@@ -157,6 +211,35 @@ func (repository *userRepository) DeleteUser(ctx context.Context, username strin
 	return err
 }
 
+func (repository *userRepository) DeleteUserByID(ctx context.Context, id string) error {
+	tracer := tracing.GetTracer(ctx)
+	ctx, span := tracer.Start(ctx, "repository.DeleteUserByID",
+		trace.WithAttributes(
+			attribute.String("user.id", id),
+			attribute.String("table", "users"),
+			attribute.String("operation", "delete"),
+		),
+		tracing.GetEnvironmentAttribute(),
+	)
+	defer span.End()
+
+	start := time.Now()
+	database := repository.DBService.GetDB()
+
+	err := database.WithContext(ctx).Where("id = ?", id).Delete(&models.User{}).Error
+
+	// Record database metrics
+	duration := float64(time.Since(start).Nanoseconds()) / float64(time.Millisecond)
+	appMetrics := metrics.GetAppMetrics()
+	result := metrics.Success
+	if err != nil {
+		result = metrics.Error
+	}
+	appMetrics.DatabaseMetric(duration, "users", "delete", result)
+
+	return err
+}
+
 func (repository *userRepository) GetUserByUsername(ctx context.Context, username string) (*models.User, error) {
 	tracer := tracing.GetTracer(ctx)
 	ctx, span := tracer.Start(ctx, "repository.GetUserByUsername",
@@ -174,7 +257,12 @@ func (repository *userRepository) GetUserByUsername(ctx context.Context, usernam
 
 	var credentials models.User
 
-	err := database.WithContext(ctx).Where("username = ?", username).First(&credentials).Error
+	key := usernameKey(username)
+	if key == nil {
+		return nil, nil
+	}
+
+	err := database.WithContext(ctx).Where("username_normalized = ?", *key).First(&credentials).Error
 
 	// Record database metrics
 	duration := float64(time.Since(start).Nanoseconds()) / float64(time.Millisecond)
@@ -195,6 +283,109 @@ func (repository *userRepository) GetUserByUsername(ctx context.Context, usernam
 	return &credentials, nil
 }
 
+func (repository *userRepository) GetUserByEmail(ctx context.Context, email string) (*models.User, error) {
+	tracer := tracing.GetTracer(ctx)
+	ctx, span := tracer.Start(ctx, "repository.GetUserByEmail",
+		trace.WithAttributes(
+			attribute.String("table", "users"),
+			attribute.String("operation", "select"),
+		),
+		tracing.GetEnvironmentAttribute(),
+	)
+	defer span.End()
+
+	start := time.Now()
+	database := repository.DBService.GetDB()
+
+	var user models.User
+
+	key := emailKey(&email)
+	if key == nil {
+		return nil, nil
+	}
+
+	err := database.WithContext(ctx).Where("email_normalized = ?", *key).First(&user).Error
+
+	// Record database metrics
+	duration := float64(time.Since(start).Nanoseconds()) / float64(time.Millisecond)
+	appMetrics := metrics.GetAppMetrics()
+	result := metrics.Success
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		result = metrics.Error
+	}
+	appMetrics.DatabaseMetric(duration, "users", "select", result)
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+func (repository *userRepository) FindTakenUsernames(
+	ctx context.Context,
+	usernames []string,
+	releasedSince time.Time,
+) (map[string]bool, error) {
+	tracer := tracing.GetTracer(ctx)
+	ctx, span := tracer.Start(ctx, "repository.FindTakenUsernames",
+		trace.WithAttributes(
+			attribute.Int("usernames.count", len(usernames)),
+			attribute.String("table", "users"),
+			attribute.String("operation", "select"),
+		),
+		tracing.GetEnvironmentAttribute(),
+	)
+	defer span.End()
+
+	taken := map[string]bool{}
+
+	keys := make([]string, 0, len(usernames))
+	for _, value := range usernames {
+		if key := usernameKey(value); key != nil {
+			keys = append(keys, *key)
+		}
+	}
+	if len(keys) == 0 {
+		return taken, nil
+	}
+
+	start := time.Now()
+	database := repository.DBService.GetDB()
+
+	var held, released []string
+	err := database.WithContext(ctx).Model(&models.User{}).
+		Where("username_normalized IN ?", keys).
+		Pluck("username_normalized", &held).Error
+	if err == nil {
+		err = database.WithContext(ctx).Model(&models.UsernameHistory{}).
+			Where("username_normalized IN ? AND created_at > ?", keys, releasedSince).
+			Pluck("username_normalized", &released).Error
+	}
+
+	// Record database metrics
+	duration := float64(time.Since(start).Nanoseconds()) / float64(time.Millisecond)
+	appMetrics := metrics.GetAppMetrics()
+	result := metrics.Success
+	if err != nil {
+		result = metrics.Error
+	}
+	appMetrics.DatabaseMetric(duration, "users", "select", result)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, key := range append(held, released...) {
+		taken[key] = true
+	}
+
+	return taken, nil
+}
+
 func (repository *userRepository) UpdateUser(
 	ctx context.Context,
 	id string,
@@ -223,8 +414,20 @@ func (repository *userRepository) UpdateUser(
 		return nil, err
 	}
 
+	changes := profileChanges(user, username, firstName, lastName, language, email)
+
+	var released *models.UsernameHistory
 	if username != nil {
+		newKey := usernameKey(*username)
+		if user.UsernameNormalized != nil && (newKey == nil || *newKey != *user.UsernameNormalized) {
+			released = &models.UsernameHistory{
+				UserID:             user.ID,
+				Username:           user.Username,
+				UsernameNormalized: *user.UsernameNormalized,
+			}
+		}
 		user.Username = *username
+		user.UsernameNormalized = newKey
 	}
 
 	if firstName != nil {
@@ -240,10 +443,31 @@ func (repository *userRepository) UpdateUser(
 	}
 
 	if email != nil {
+		if user.Email == nil || *user.Email != *email {
+			user.EmailVerifiedAt = nil
+			user.EmailVerificationTokenHash = nil
+		}
 		user.Email = email
+		user.EmailNormalized = emailKey(email)
 	}
 
-	err = database.WithContext(ctx).Save(&user).Error
+	err = translateError(database.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if released != nil {
+			if err := tx.Create(released).Error; err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Save(&user).Error; err != nil {
+			return err
+		}
+
+		if len(changes) == 0 {
+			return nil
+		}
+
+		return tx.Create(auditModels.NewEntry(ctx, user.ID, auditModels.ActionUpdateUser, changes)).Error
+	}))
 
 	// Record database metrics
 	duration := float64(time.Since(start).Nanoseconds()) / float64(time.Millisecond)
@@ -262,6 +486,141 @@ func (repository *userRepository) UpdateUser(
 	return repository.GetUserById(ctx, id)
 }
 
+func (repository *userRepository) GetLatestUsernameChange(ctx context.Context, userID string) (*models.UsernameHistory, error) {
+	tracer := tracing.GetTracer(ctx)
+	ctx, span := tracer.Start(ctx, "repository.GetLatestUsernameChange",
+		trace.WithAttributes(
+			attribute.String("user.id", userID),
+			attribute.String("table", "username_history"),
+			attribute.String("operation", "select"),
+		),
+		tracing.GetEnvironmentAttribute(),
+	)
+	defer span.End()
+
+	return repository.latestUsernameHistory(ctx, "user_id = ?", userID)
+}
+
+func (repository *userRepository) GetLatestUsernameRelease(ctx context.Context, username string) (*models.UsernameHistory, error) {
+	tracer := tracing.GetTracer(ctx)
+	ctx, span := tracer.Start(ctx, "repository.GetLatestUsernameRelease",
+		trace.WithAttributes(
+			attribute.String("user.username", username),
+			attribute.String("table", "username_history"),
+			attribute.String("operation", "select"),
+		),
+		tracing.GetEnvironmentAttribute(),
+	)
+	defer span.End()
+
+	key := usernameKey(username)
+	if key == nil {
+		return nil, nil
+	}
+
+	return repository.latestUsernameHistory(ctx, "username_normalized = ?", *key)
+}
+
+func (repository *userRepository) latestUsernameHistory(ctx context.Context, query string, args ...interface{}) (*models.UsernameHistory, error) {
+	start := time.Now()
+	database := repository.DBService.GetDB()
+
+	var history models.UsernameHistory
+	err := database.WithContext(ctx).Where(query, args...).Order("created_at DESC").First(&history).Error
+
+	// Record database metrics
+	duration := float64(time.Since(start).Nanoseconds()) / float64(time.Millisecond)
+	appMetrics := metrics.GetAppMetrics()
+	result := metrics.Success
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		result = metrics.Error
+	}
+	appMetrics.DatabaseMetric(duration, "username_history", "select", result)
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &history, nil
+}
+
+func (repository *userRepository) SetEmailVerificationToken(
+	ctx context.Context,
+	id string,
+	email string,
+	tokenHash string,
+) (bool, error) {
+	tracer := tracing.GetTracer(ctx)
+	ctx, span := tracer.Start(ctx, "repository.SetEmailVerificationToken",
+		trace.WithAttributes(
+			attribute.String("user.id", id),
+			attribute.String("table", "users"),
+			attribute.String("operation", "update"),
+		),
+		tracing.GetEnvironmentAttribute(),
+	)
+	defer span.End()
+
+	return repository.updateEmailVerification(ctx,
+		map[string]interface{}{"email_verification_token_hash": tokenHash},
+		"id = ? AND email = ?", id, email,
+	)
+}
+
+func (repository *userRepository) MarkEmailVerified(ctx context.Context, id string, tokenHash string) (bool, error) {
+	tracer := tracing.GetTracer(ctx)
+	ctx, span := tracer.Start(ctx, "repository.MarkEmailVerified",
+		trace.WithAttributes(
+			attribute.String("user.id", id),
+			attribute.String("table", "users"),
+			attribute.String("operation", "update"),
+		),
+		tracing.GetEnvironmentAttribute(),
+	)
+	defer span.End()
+
+	return repository.updateEmailVerification(ctx,
+		map[string]interface{}{
+			"email_verified_at":             time.Now(),
+			"email_verification_token_hash": nil,
+		},
+		"id = ? AND email_verification_token_hash = ?", id, tokenHash,
+	)
+}
+
+// updateEmailVerification applies changes to the users matching query and reports whether any did.
+func (repository *userRepository) updateEmailVerification(
+	ctx context.Context,
+	changes map[string]interface{},
+	query string,
+	args ...interface{},
+) (bool, error) {
+	start := time.Now()
+	database := repository.DBService.GetDB()
+
+	changes["updated_at"] = time.Now()
+	update := database.WithContext(ctx).Model(&models.User{}).Where(query, args...).Updates(changes)
+	err := update.Error
+
+	// Record database metrics
+	duration := float64(time.Since(start).Nanoseconds()) / float64(time.Millisecond)
+	appMetrics := metrics.GetAppMetrics()
+	result := metrics.Success
+	if err != nil {
+		result = metrics.Error
+	}
+	appMetrics.DatabaseMetric(duration, "users", "update", result)
+
+	if err != nil {
+		return false, err
+	}
+
+	return update.RowsAffected > 0, nil
+}
+
 func (repository *userRepository) UpdateProfileImageURL(
 	ctx context.Context,
 	id string,
@@ -291,9 +650,20 @@ func (repository *userRepository) UpdateProfileImageURL(
 		return nil, errors.New("user not found")
 	}
 
+	changes := auditModels.Diff(nil, auditModels.FieldProfileImageURL, user.ProfileImageURL, &profileImageURL)
 	user.ProfileImageURL = &profileImageURL
 
-	err = database.WithContext(ctx).Save(&user).Error
+	err = database.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&user).Error; err != nil {
+			return err
+		}
+
+		if len(changes) == 0 {
+			return nil
+		}
+
+		return tx.Create(auditModels.NewEntry(ctx, user.ID, auditModels.ActionUpdateProfileImage, changes)).Error
+	})
 
 	// Record database metrics
 	duration := float64(time.Since(start).Nanoseconds()) / float64(time.Millisecond)
@@ -312,6 +682,182 @@ func (repository *userRepository) UpdateProfileImageURL(
 	return repository.GetUserById(ctx, id)
 }
 
+func (repository *userRepository) BackfillNormalizedUsernames(ctx context.Context) (int, []string, error) {
+	tracer := tracing.GetTracer(ctx)
+	ctx, span := tracer.Start(ctx, "repository.BackfillNormalizedUsernames",
+		trace.WithAttributes(
+			attribute.String("table", "users"),
+			attribute.String("operation", "update"),
+		),
+		tracing.GetEnvironmentAttribute(),
+	)
+	defer span.End()
+
+	updated, conflicts, err := repository.backfillNormalized(ctx, "username", "username_normalized", ErrUsernameTaken,
+		func(user models.User) *string { return usernameKey(user.Username) })
+
+	span.SetAttributes(attribute.Int("users.updated", updated), attribute.Int("users.conflicts", len(conflicts)))
+
+	return updated, conflicts, err
+}
+
+func (repository *userRepository) BackfillNormalizedEmails(ctx context.Context) (int, []string, error) {
+	tracer := tracing.GetTracer(ctx)
+	ctx, span := tracer.Start(ctx, "repository.BackfillNormalizedEmails",
+		trace.WithAttributes(
+			attribute.String("table", "users"),
+			attribute.String("operation", "update"),
+		),
+		tracing.GetEnvironmentAttribute(),
+	)
+	defer span.End()
+
+	updated, conflicts, err := repository.backfillNormalized(ctx, "email", "email_normalized", ErrEmailTaken,
+		func(user models.User) *string { return emailKey(user.Email) })
+
+	span.SetAttributes(attribute.Int("users.updated", updated), attribute.Int("users.conflicts", len(conflicts)))
+
+	return updated, conflicts, err
+}
+
+// backfillNormalized sets column to key(user) for users that have source but no column yet.
+// Users whose key is already held, reported by the unique index as taken, are skipped and returned.
+func (repository *userRepository) backfillNormalized(
+	ctx context.Context,
+	source string,
+	column string,
+	taken error,
+	key func(user models.User) *string,
+) (int, []string, error) {
+	start := time.Now()
+	database := repository.DBService.GetDB()
+
+	const batchSize = 500
+	updated := 0
+	var conflicts []string
+	var err error
+
+	// Oldest accounts are normalized first so they keep their value when two collide
+	lastCreatedAt, lastID := time.Time{}, ""
+	for {
+		var batch []models.User
+		err = database.WithContext(ctx).
+			Where(column+" IS NULL AND "+source+" <> ''").
+			Where("(created_at > ? OR (created_at = ? AND id > ?))", lastCreatedAt, lastCreatedAt, lastID).
+			Order("created_at, id").
+			Limit(batchSize).
+			Find(&batch).Error
+		if err != nil || len(batch) == 0 {
+			break
+		}
+
+		for _, user := range batch {
+			updateErr := translateError(database.WithContext(ctx).Model(&models.User{}).
+				Where("id = ?", user.ID).
+				UpdateColumn(column, key(user)).Error)
+			if errors.Is(updateErr, taken) {
+				conflicts = append(conflicts, user.ID)
+				continue
+			}
+			if updateErr != nil {
+				err = updateErr
+				break
+			}
+			updated++
+		}
+		if err != nil {
+			break
+		}
+
+		last := batch[len(batch)-1]
+		lastCreatedAt, lastID = last.CreatedAt, last.ID
+	}
+
+	// Record database metrics
+	duration := float64(time.Since(start).Nanoseconds()) / float64(time.Millisecond)
+	appMetrics := metrics.GetAppMetrics()
+	result := metrics.Success
+	if err != nil {
+		result = metrics.Error
+	}
+	appMetrics.DatabaseMetric(duration, "users", "update", result)
+
+	return updated, conflicts, err
+}
+
+// profileChanges lists the fields an UpdateUser call changes on user, for the audit log.
+func profileChanges(
+	user *models.User,
+	username *string,
+	firstName *string,
+	lastName *string,
+	language *string,
+	email *string,
+) auditModels.Changes {
+	var changes auditModels.Changes
+	if username != nil {
+		changes = auditModels.Diff(changes, auditModels.FieldUsername, &user.Username, username)
+	}
+	if firstName != nil {
+		changes = auditModels.Diff(changes, auditModels.FieldFirstName, &user.FirstName, firstName)
+	}
+	if lastName != nil {
+		changes = auditModels.Diff(changes, auditModels.FieldLastName, &user.LastName, lastName)
+	}
+	if language != nil {
+		changes = auditModels.Diff(changes, auditModels.FieldLanguage, &user.Language, language)
+	}
+	if email != nil {
+		changes = auditModels.Diff(changes, auditModels.FieldEmail, user.Email, email)
+	}
+
+	return changes
+}
+
+// usernameKey returns the value stored in username_normalized for username.
+func usernameKey(value string) *string {
+	key := username.Normalize(value)
+	if key == "" {
+		return nil
+	}
+
+	return &key
+}
+
+// emailKey returns the value stored in email_normalized for value, which may be nil.
+func emailKey(value *string) *string {
+	if value == nil {
+		return nil
+	}
+
+	key := email.Normalize(*value)
+	if key == "" {
+		return nil
+	}
+
+	return &key
+}
+
+// translateError maps MySQL duplicate key errors to the repository's sentinel errors.
+func translateError(err error) error {
+	var mysqlErr *mysql.MySQLError
+	if !errors.As(err, &mysqlErr) || mysqlErr.Number != mysqlDuplicateEntry {
+		return err
+	}
+
+	if strings.Contains(mysqlErr.Message, usernameIndex) {
+		return ErrUsernameTaken
+	}
+	if strings.Contains(mysqlErr.Message, emailIndex) {
+		return ErrEmailTaken
+	}
+	if strings.Contains(mysqlErr.Message, "PRIMARY") {
+		return ErrUserExists
+	}
+
+	return err
+}
+
 func GetUsersRepository() UsersRepository {
 	if userRepositorySingleton == nil {
 		userRepositorySingleton = NewUsersRepository()
@@ -2,26 +2,80 @@ package users
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"strings"
 	"time"
 
+	"github.com/weeb-vip/user-service/config"
+	"github.com/weeb-vip/user-service/internal/events"
+	"github.com/weeb-vip/user-service/internal/jwt"
+	"github.com/weeb-vip/user-service/internal/locale"
+	"github.com/weeb-vip/user-service/internal/services/users/email"
 	"github.com/weeb-vip/user-service/internal/services/users/models"
 	"github.com/weeb-vip/user-service/internal/services/users/repositories"
+	"github.com/weeb-vip/user-service/internal/services/users/username"
 	"github.com/weeb-vip/user-service/metrics"
 	"github.com/weeb-vip/user-service/tracing"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
 )
 
+const (
+	defaultReleaseCooldown = 30 * 24 * time.Hour
+	defaultRenameInterval  = 30 * 24 * time.Hour
+	// defaultEmailVerificationTTL is how long a verification link stays valid.
+	defaultEmailVerificationTTL = 24 * time.Hour
+)
+
 type usersService struct {
 	usersRepository repositories.UsersRepository
+	usernamePolicy  *username.Policy
+	// releaseCooldown is how long a username given up by a rename stays reserved for its old owner.
+	releaseCooldown time.Duration
+	// renameInterval is the minimum time between two renames by the same user.
+	renameInterval time.Duration
+	// tokenizer and publisher issue and deliver email verification tokens; RequestEmailVerification
+	// fails without them.
+	tokenizer            jwt.Tokenizer
+	publisher            events.Publisher
+	emailVerificationTTL time.Duration
+	now                  func() time.Time
+}
+
+type Option func(*usersService)
+
+func WithUsernameConfig(cfg config.UsernameConfig) Option {
+	return func(service *usersService) {
+		service.releaseCooldown = time.Duration(cfg.ReleaseCooldownHours) * time.Hour
+		service.renameInterval = time.Duration(cfg.RenameIntervalHours) * time.Hour
+	}
 }
 
-func NewUserService() User {
+func WithEmailVerification(tokenizer jwt.Tokenizer, publisher events.Publisher, cfg config.EmailConfig) Option {
+	return func(service *usersService) {
+		service.tokenizer = tokenizer
+		service.publisher = publisher
+		service.emailVerificationTTL = time.Duration(cfg.VerificationTTLHours) * time.Hour
+	}
+}
+
+func NewUserService(opts ...Option) User {
 	usersRepository := repositories.GetUsersRepository()
 
-	return &usersService{
-		usersRepository: usersRepository,
+	service := &usersService{
+		usersRepository:      usersRepository,
+		usernamePolicy:       username.DefaultPolicy(),
+		releaseCooldown:      defaultReleaseCooldown,
+		renameInterval:       defaultRenameInterval,
+		emailVerificationTTL: defaultEmailVerificationTTL,
+		now:                  time.Now,
+	}
+	for _, opt := range opts {
+		opt(service)
 	}
+
+	return service
 }
 
 func (service *usersService) AddUser(
@@ -31,6 +85,7 @@ func (service *usersService) AddUser(
 	firstName string,
 	lastName string,
 	language string,
+	email *string,
 ) (*models.User, error) {
 	tracer := tracing.GetTracer(ctx)
 	ctx, span := tracer.Start(ctx, "service.AddUser",
@@ -46,9 +101,18 @@ func (service *usersService) AddUser(
 
 	startTime := time.Now()
 
-	// check if user already exists
-	user, err := service.usersRepository.GetUserByUsername(ctx, username)
+	language, err := locale.Normalize(language)
+	if err != nil {
+		metrics.GetAppMetrics().ServiceMetric(
+			float64(time.Since(startTime).Milliseconds()),
+			"users",
+			"AddUser",
+			metrics.Error,
+		)
+		return nil, unsupportedLanguage()
+	}
 
+	username, err = service.usernamePolicy.Validate(username)
 	if err != nil {
 		metrics.GetAppMetrics().ServiceMetric(
 			float64(time.Since(startTime).Milliseconds()),
@@ -57,22 +121,34 @@ func (service *usersService) AddUser(
 			metrics.Error,
 		)
 		return nil, &Error{
-			Code:    UserErrorInternalError,
-			Message: "database error",
+			Code:    UserErrorUsernameInvalid,
+			Message: err.Error(),
 		}
 	}
 
-	if user != nil {
+	email, err = validateEmail(email)
+	if err == nil {
+		err = service.checkEmailClaim(ctx, id, email)
+	}
+	if err != nil {
 		metrics.GetAppMetrics().ServiceMetric(
 			float64(time.Since(startTime).Milliseconds()),
 			"users",
 			"AddUser",
 			metrics.Error,
 		)
-		return nil, &Error{
-			Code:    UserErrorUserExists,
-			Message: "user already exists",
-		}
+		return nil, err
+	}
+
+	// the unique index catches anyone racing us between this check and the insert
+	if err := service.checkUsernameClaim(ctx, id, username); err != nil {
+		metrics.GetAppMetrics().ServiceMetric(
+			float64(time.Since(startTime).Milliseconds()),
+			"users",
+			"AddUser",
+			metrics.Error,
+		)
+		return nil, err
 	}
 
 	result, err := service.usersRepository.AddUser(
@@ -82,6 +158,7 @@ func (service *usersService) AddUser(
 		firstName,
 		lastName,
 		language,
+		email,
 	)
 
 	metricResult := metrics.Success
@@ -95,7 +172,11 @@ func (service *usersService) AddUser(
 		metricResult,
 	)
 
-	return result, err
+	if err != nil {
+		return nil, translateRepositoryError(err)
+	}
+
+	return result, nil
 }
 
 func (service *usersService) GetUserDetails( //nolint
@@ -174,6 +255,61 @@ func (service *usersService) UpdateUser(
 
 	startTime := time.Now()
 
+	if language != nil {
+		normalized, err := locale.Normalize(*language)
+		if err != nil {
+			metrics.GetAppMetrics().ServiceMetric(
+				float64(time.Since(startTime).Milliseconds()),
+				"users",
+				"UpdateUser",
+				metrics.Error,
+			)
+			return nil, unsupportedLanguage()
+		}
+		language = &normalized
+	}
+
+	email, err := validateEmail(email)
+	if err == nil {
+		err = service.checkEmailClaim(ctx, id, email)
+	}
+	if err != nil {
+		metrics.GetAppMetrics().ServiceMetric(
+			float64(time.Since(startTime).Milliseconds()),
+			"users",
+			"UpdateUser",
+			metrics.Error,
+		)
+		return nil, err
+	}
+
+	if username != nil {
+		validated, err := service.usernamePolicy.Validate(*username)
+		if err != nil {
+			metrics.GetAppMetrics().ServiceMetric(
+				float64(time.Since(startTime).Milliseconds()),
+				"users",
+				"UpdateUser",
+				metrics.Error,
+			)
+			return nil, &Error{
+				Code:    UserErrorUsernameInvalid,
+				Message: err.Error(),
+			}
+		}
+		username = &validated
+
+		if err := service.checkRename(ctx, id, validated); err != nil {
+			metrics.GetAppMetrics().ServiceMetric(
+				float64(time.Since(startTime).Milliseconds()),
+				"users",
+				"UpdateUser",
+				metrics.Error,
+			)
+			return nil, err
+		}
+	}
+
 	result, err := service.usersRepository.UpdateUser(ctx, id, username, firstName, lastName, language, email)
 
 	metricResult := metrics.Success
@@ -187,7 +323,11 @@ func (service *usersService) UpdateUser(
 		metricResult,
 	)
 
-	return result, err
+	if err != nil {
+		return nil, translateRepositoryError(err)
+	}
+
+	return result, nil
 }
 
 func (service *usersService) UpdateProfileImageURL(
@@ -251,3 +391,285 @@ func (service *usersService) UpdateProfileImageURL(
 
 	return result, err
 }
+
+func (service *usersService) DeleteUser(ctx context.Context, id string) error {
+	tracer := tracing.GetTracer(ctx)
+	ctx, span := tracer.Start(ctx, "service.DeleteUser",
+		trace.WithAttributes(
+			attribute.String("user.id", id),
+			attribute.String("service", "users"),
+			attribute.String("method", "DeleteUser"),
+		),
+		tracing.GetEnvironmentAttribute(),
+	)
+	defer span.End()
+
+	startTime := time.Now()
+
+	err := service.usersRepository.DeleteUserByID(ctx, id)
+
+	metricResult := metrics.Success
+	if err != nil {
+		metricResult = metrics.Error
+	}
+	metrics.GetAppMetrics().ServiceMetric(
+		float64(time.Since(startTime).Milliseconds()),
+		"users",
+		"DeleteUser",
+		metricResult,
+	)
+	if err != nil {
+		return &Error{
+			Code:    UserErrorInternalError,
+			Message: "database error",
+		}
+	}
+
+	return nil
+}
+
+func unsupportedLanguage() error {
+	return &Error{
+		Code:    UserErrorUnsupportedLanguage,
+		Message: "unsupported language, expected one of " + strings.Join(locale.Supported, ", "),
+	}
+}
+
+func (service *usersService) GetUserByUsername(ctx context.Context, value string) (*models.User, bool, error) {
+	tracer := tracing.GetTracer(ctx)
+	ctx, span := tracer.Start(ctx, "service.GetUserByUsername",
+		trace.WithAttributes(
+			attribute.String("user.username", value),
+			attribute.String("service", "users"),
+			attribute.String("method", "GetUserByUsername"),
+		),
+		tracing.GetEnvironmentAttribute(),
+	)
+	defer span.End()
+
+	startTime := time.Now()
+
+	user, redirected, err := service.findUserByUsername(ctx, value)
+
+	metricResult := metrics.Success
+	if err != nil {
+		metricResult = metrics.Error
+	}
+	metrics.GetAppMetrics().ServiceMetric(
+		float64(time.Since(startTime).Milliseconds()),
+		"users",
+		"GetUserByUsername",
+		metricResult,
+	)
+
+	if err != nil {
+		return nil, false, &Error{
+			Code:    UserErrorInternalError,
+			Message: "database error",
+		}
+	}
+
+	span.SetAttributes(attribute.Bool("user.found", user != nil), attribute.Bool("username.redirected", redirected))
+
+	return user, redirected, nil
+}
+
+func (service *usersService) GetUserByEmail(ctx context.Context, value string) (*models.User, error) {
+	tracer := tracing.GetTracer(ctx)
+	ctx, span := tracer.Start(ctx, "service.GetUserByEmail",
+		trace.WithAttributes(
+			attribute.String("service", "users"),
+			attribute.String("method", "GetUserByEmail"),
+		),
+		tracing.GetEnvironmentAttribute(),
+	)
+	defer span.End()
+
+	startTime := time.Now()
+
+	user, err := service.usersRepository.GetUserByEmail(ctx, value)
+
+	metricResult := metrics.Success
+	if err != nil {
+		metricResult = metrics.Error
+	}
+	metrics.GetAppMetrics().ServiceMetric(
+		float64(time.Since(startTime).Milliseconds()),
+		"users",
+		"GetUserByEmail",
+		metricResult,
+	)
+
+	if err != nil {
+		return nil, &Error{
+			Code:    UserErrorInternalError,
+			Message: "database error",
+		}
+	}
+
+	span.SetAttributes(attribute.Bool("user.found", user != nil))
+
+	return user, nil
+}
+
+// findUserByUsername prefers the current holder of a username and otherwise follows the most
+// recent rename away from it.
+func (service *usersService) findUserByUsername(ctx context.Context, value string) (*models.User, bool, error) {
+	user, err := service.usersRepository.GetUserByUsername(ctx, value)
+	if err != nil || user != nil {
+		return user, false, err
+	}
+
+	released, err := service.usersRepository.GetLatestUsernameRelease(ctx, value)
+	if err != nil || released == nil {
+		return nil, false, err
+	}
+
+	user, err = service.usersRepository.GetUserById(ctx, released.UserID)
+	if err != nil || user == nil || user.ID == "" {
+		return nil, false, err
+	}
+
+	return user, true, nil
+}
+
+// checkUsernameClaim returns an error unless userID may take display: nobody else holds it and
+// nobody else gave it up within the release cooldown.
+func (service *usersService) checkUsernameClaim(ctx context.Context, userID string, display string) error {
+	holder, err := service.usersRepository.GetUserByUsername(ctx, display)
+	if err != nil {
+		return &Error{
+			Code:    UserErrorInternalError,
+			Message: "database error",
+		}
+	}
+	if holder != nil {
+		if holder.ID == userID {
+			return nil
+		}
+		return usernameTaken()
+	}
+
+	released, err := service.usersRepository.GetLatestUsernameRelease(ctx, display)
+	if err != nil {
+		return &Error{
+			Code:    UserErrorInternalError,
+			Message: "database error",
+		}
+	}
+	if released != nil && released.UserID != userID && service.now().Sub(released.CreatedAt) < service.releaseCooldown {
+		return &Error{
+			Code:    UserErrorUsernameTaken,
+			Message: "username was released recently and cannot be claimed yet",
+		}
+	}
+
+	return nil
+}
+
+// checkRename returns an error unless userID may change their username to display now.
+func (service *usersService) checkRename(ctx context.Context, userID string, display string) error {
+	current, err := service.usersRepository.GetUserById(ctx, userID)
+	if err != nil {
+		return &Error{
+			Code:    UserErrorInternalError,
+			Message: "database error",
+		}
+	}
+	if current == nil || current.ID == "" {
+		return &Error{
+			Code:    UserErrorInvalidUsers,
+			Message: "user not found",
+		}
+	}
+
+	// Changing only the case or separators keeps the same name, so it is not a rename
+	if current.UsernameNormalized != nil && *current.UsernameNormalized == username.Normalize(display) {
+		return nil
+	}
+
+	latest, err := service.usersRepository.GetLatestUsernameChange(ctx, userID)
+	if err != nil {
+		return &Error{
+			Code:    UserErrorInternalError,
+			Message: "database error",
+		}
+	}
+	if latest != nil {
+		next := latest.CreatedAt.Add(service.renameInterval)
+		if service.now().Before(next) {
+			return &Error{
+				Code:    UserErrorUsernameChangeTooSoon,
+				Message: fmt.Sprintf("username can be changed again after %s", next.UTC().Format(time.RFC3339)),
+			}
+		}
+	}
+
+	return service.checkUsernameClaim(ctx, userID, display)
+}
+
+// validateEmail returns the cleaned form of value, which may be nil.
+func validateEmail(value *string) (*string, error) {
+	if value == nil {
+		return nil, nil
+	}
+
+	validated, err := email.Validate(*value)
+	if err != nil {
+		return nil, &Error{
+			Code:    UserErrorEmailInvalid,
+			Message: err.Error(),
+		}
+	}
+
+	return &validated, nil
+}
+
+// checkEmailClaim returns an error if another user holds value, which may be nil. The unique
+// index still catches anyone racing us after the check.
+func (service *usersService) checkEmailClaim(ctx context.Context, userID string, value *string) error {
+	if value == nil {
+		return nil
+	}
+
+	holder, err := service.usersRepository.GetUserByEmail(ctx, *value)
+	if err != nil {
+		return &Error{
+			Code:    UserErrorInternalError,
+			Message: "database error",
+		}
+	}
+	if holder != nil && holder.ID != userID {
+		return emailTaken()
+	}
+
+	return nil
+}
+
+func emailTaken() error {
+	return &Error{
+		Code:    UserErrorEmailTaken,
+		Message: "email is already in use",
+	}
+}
+
+func usernameTaken() error {
+	return &Error{
+		Code:    UserErrorUsernameTaken,
+		Message: "username is already taken",
+	}
+}
+
+// translateRepositoryError turns the repository's constraint errors into typed service errors.
+func translateRepositoryError(err error) error {
+	switch {
+	case errors.Is(err, repositories.ErrUsernameTaken):
+		return usernameTaken()
+	case errors.Is(err, repositories.ErrEmailTaken):
+		return emailTaken()
+	case errors.Is(err, repositories.ErrUserExists):
+		return &Error{Code: UserErrorUserExists, Message: "user already exists"}
+	default:
+		return &Error{Code: UserErrorInternalError, Message: "database error"}
+	}
+}
@@ -16,7 +16,7 @@ func TestUserService_AddUser(t *testing.T) {
 
 		credentialService := users.NewUserService()
 
-		_, err := credentialService.AddUser(context.TODO(), "1", "username", "first", "last", "en")
+		_, err := credentialService.AddUser(context.TODO(), "1", "username", "first", "last", "en", nil)
 		a.NoError(err)
 	})
 	t.Run("Test AddUser 2 times - idempotence", func(t *testing.T) {
@@ -25,9 +25,9 @@ func TestUserService_AddUser(t *testing.T) {
 
 		credentialService := users.NewUserService()
 
-		_, err := credentialService.AddUser(context.TODO(), "1", "username", "first", "last", "en")
+		_, err := credentialService.AddUser(context.TODO(), "1", "username", "first", "last", "en", nil)
 		a.NoError(err)
-		_, err = credentialService.AddUser(context.TODO(), "2", "username2", "first", "last", "en")
+		_, err = credentialService.AddUser(context.TODO(), "2", "username2", "first", "last", "en", nil)
 		a.NoError(err)
 	})
 	t.Run("Test AddUser 2 times with same username", func(t *testing.T) {
@@ -36,9 +36,9 @@ func TestUserService_AddUser(t *testing.T) {
 
 		credentialService := users.NewUserService()
 
-		_, err := credentialService.AddUser(context.TODO(), "1", "username", "first", "last", "en")
+		_, err := credentialService.AddUser(context.TODO(), "1", "username", "first", "last", "en", nil)
 		a.NoError(err)
-		_, err = credentialService.AddUser(context.TODO(), "1", "username", "first", "last", "en")
+		_, err = credentialService.AddUser(context.TODO(), "1", "username", "first", "last", "en", nil)
 		a.Error(err)
 	})
 }
@@ -51,7 +51,7 @@ func TestUserService_GetUserDetails(t *testing.T) {
 
 		credentialService := users.NewUserService()
 
-		_, err := credentialService.AddUser(context.TODO(), "1", "username", "first", "last", "en")
+		_, err := credentialService.AddUser(context.TODO(), "1", "username", "first", "last", "en", nil)
 		a.NoError(err)
 
 		a.NotNil(credentialService.GetUserDetails(context.TODO(), "username"))
@@ -0,0 +1,190 @@
+package username
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/unicode/norm"
+)
+
+const (
+	DefaultMinLength = 3
+	DefaultMaxLength = 30
+)
+
+// defaultReserved are names nobody may register, compared after normalization so that
+// "Admin", "ADM1N" and "аdmin" (Cyrillic а) are all caught.
+var defaultReserved = []string{ // nolint
+	"admin", "administrator", "root", "system", "support", "help", "staff", "official",
+	"moderator", "mod", "security", "abuse", "postmaster", "webmaster", "noreply",
+	"weeb", "weebvip", "weeb_vip", "api", "www", "mail", "status", "settings",
+	"login", "logout", "signup", "register", "account", "profile", "me", "user", "users",
+	"guest", "anonymous", "null", "undefined", "deleted",
+}
+
+// InvalidError explains why a username was rejected by a Policy.
+type InvalidError struct {
+	Reason string
+}
+
+func (e *InvalidError) Error() string {
+	return "invalid username: " + e.Reason
+}
+
+// Policy decides which usernames may be registered.
+type Policy struct {
+	MinLength int
+	MaxLength int
+	reserved  map[string]bool
+}
+
+type Option func(*Policy)
+
+// WithLength overrides the allowed length range, counted in characters.
+func WithLength(minLength int, maxLength int) Option {
+	return func(p *Policy) {
+		p.MinLength = minLength
+		p.MaxLength = maxLength
+	}
+}
+
+// WithReserved adds words to the reserved list.
+func WithReserved(words ...string) Option {
+	return func(p *Policy) {
+		for _, word := range words {
+			if word = strings.TrimSpace(word); word != "" {
+				p.reserved[reservedKey(word)] = true
+			}
+		}
+	}
+}
+
+func NewPolicy(opts ...Option) *Policy {
+	policy := &Policy{
+		MinLength: DefaultMinLength,
+		MaxLength: DefaultMaxLength,
+		reserved:  map[string]bool{},
+	}
+	WithReserved(defaultReserved...)(policy)
+
+	for _, opt := range opts {
+		opt(policy)
+	}
+
+	return policy
+}
+
+var defaultPolicy = NewPolicy() // nolint
+
+// DefaultPolicy returns the policy used for registrations.
+func DefaultPolicy() *Policy {
+	return defaultPolicy
+}
+
+// Validate checks value against the policy and returns its canonical display form, trimmed and
+// NFC-normalized. The returned error is an *InvalidError.
+func (p *Policy) Validate(value string) (string, error) {
+	display := norm.NFC.String(strings.TrimSpace(value))
+
+	length := utf8.RuneCountInString(display)
+	if length < p.MinLength || length > p.MaxLength {
+		return "", &InvalidError{Reason: fmt.Sprintf("must be between %d and %d characters", p.MinLength, p.MaxLength)}
+	}
+
+	previousSeparator := false
+	for i, r := range display {
+		switch {
+		case unicode.IsLetter(r) || unicode.Is(unicode.Nd, r):
+			previousSeparator = false
+		case isSeparator(r):
+			if i == 0 || previousSeparator {
+				return "", &InvalidError{Reason: "separators must sit between letters or digits"}
+			}
+			previousSeparator = true
+		default:
+			return "", &InvalidError{Reason: fmt.Sprintf("may not contain %q", r)}
+		}
+	}
+	if previousSeparator {
+		return "", &InvalidError{Reason: "separators must sit between letters or digits"}
+	}
+
+	if p.reserved[reservedKey(display)] {
+		return "", &InvalidError{Reason: "is reserved"}
+	}
+
+	return display, nil
+}
+
+// Normalize returns the key two usernames share when they would look alike: compatibility
+// forms are unified, case is folded, common look-alike letters from other scripts are mapped
+// to Latin, and "-" and "." are treated like "_". Uniqueness is enforced on this key.
+func Normalize(value string) string {
+	folded := cases.Fold().String(norm.NFKC.String(strings.TrimSpace(value)))
+
+	var builder strings.Builder
+	builder.Grow(len(folded))
+	for _, r := range folded {
+		if replacement, ok := confusables[r]; ok {
+			r = replacement
+		}
+		if isSeparator(r) {
+			r = '_'
+		}
+		builder.WriteRune(r)
+	}
+
+	return builder.String()
+}
+
+// reservedKey is stricter than Normalize: separators are dropped and digits commonly used as
+// letters are read as letters, so "ad_m1n" is treated as "admin".
+func reservedKey(value string) string {
+	var builder strings.Builder
+	for _, r := range Normalize(value) {
+		if r == '_' {
+			continue
+		}
+		if replacement, ok := leet[r]; ok {
+			r = replacement
+		}
+		builder.WriteRune(r)
+	}
+
+	return builder.String()
+}
+
+func isSeparator(r rune) bool {
+	return r == '_' || r == '-' || r == '.'
+}
+
+// confusables maps lower-case letters from other scripts to the Latin letter they are
+// indistinguishable from in most fonts.
+var confusables = map[rune]rune{ // nolint
+	// Cyrillic
+	'а': 'a', 'в': 'b', 'е': 'e', 'ё': 'e', 'һ': 'h', 'і': 'i', 'ї': 'i', 'ј': 'j', 'к': 'k',
+	'м': 'm', 'н': 'h', 'о': 'o', 'р': 'p', 'с': 'c', 'ѕ': 's', 'т': 't', 'у': 'y', 'х': 'x',
+	'ԁ': 'd', 'ԛ': 'q', 'ԝ': 'w', 'ӏ': 'l',
+	// Greek
+	'α': 'a', 'β': 'b', 'ε': 'e', 'η': 'n', 'ι': 'i', 'κ': 'k', 'ν': 'v', 'ο': 'o', 'ρ': 'p',
+	'τ': 't', 'υ': 'u', 'χ': 'x', 'ϲ': 'c',
+	// Latin look-alikes
+	'ı': 'i', 'ȷ': 'j', 'ɑ': 'a', 'ɡ': 'g', 'ɩ': 'i', 'ʋ': 'u',
+}
+
+var leet = map[rune]rune{ // nolint
+	'0': 'o', '1': 'i', '3': 'e', '4': 'a', '5': 's', '7': 't', 'l': 'i',
+}
+
+// Placeholder returns a username for accounts created without one. It is derived from seed so
+// the same account always gets the same placeholder.
+func Placeholder(seed string) string {
+	sum := sha256.Sum256([]byte(seed))
+
+	return "user_" + hex.EncodeToString(sum[:])[:10]
+}
@@ -0,0 +1,132 @@
+package username_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/weeb-vip/user-service/internal/services/users/username"
+)
+
+func TestPolicy_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		expected string
+		reason   string
+	}{
+		{name: "plain ascii", value: "naruto_fan", expected: "naruto_fan"},
+		{name: "trims whitespace", value: "  naruto  ", expected: "naruto"},
+		{name: "unicode letters", value: "すずみや", expected: "すずみや"},
+		{name: "composes accents", value: "amélie", expected: "amélie"},
+		{name: "too short", value: "ab", reason: "must be between 3 and 30 characters"},
+		{name: "too long", value: "abcdefghijklmnopqrstuvwxyz12345", reason: "must be between 3 and 30 characters"},
+		{name: "empty", value: "", reason: "must be between 3 and 30 characters"},
+		{name: "spaces inside", value: "naruto fan", reason: `may not contain ' '`},
+		{name: "symbols", value: "naruto!", reason: `may not contain '!'`},
+		{name: "leading separator", value: "_naruto", reason: "separators must sit between letters or digits"},
+		{name: "trailing separator", value: "naruto.", reason: "separators must sit between letters or digits"},
+		{name: "doubled separator", value: "naru__to", reason: "separators must sit between letters or digits"},
+		{name: "reserved", value: "admin", reason: "is reserved"},
+		{name: "reserved in another case", value: "ADMIN", reason: "is reserved"},
+		{name: "reserved with digits for letters", value: "adm1n", reason: "is reserved"},
+		{name: "reserved with separators", value: "ad-min", reason: "is reserved"},
+		{name: "reserved with cyrillic letters", value: "аdmіn", reason: "is reserved"},
+		{name: "reserved with fullwidth letters", value: "ａｄｍｉｎ", reason: "is reserved"},
+	}
+
+	policy := username.DefaultPolicy()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			display, err := policy.Validate(tt.value)
+			if tt.reason != "" {
+				var invalid *username.InvalidError
+				require.ErrorAs(t, err, &invalid)
+				assert.Equal(t, tt.reason, invalid.Reason)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, display)
+		})
+	}
+}
+
+func TestPolicy_Options(t *testing.T) {
+	policy := username.NewPolicy(username.WithLength(5, 8), username.WithReserved("sensei"))
+
+	_, err := policy.Validate("abcd")
+	assert.Error(t, err)
+	_, err = policy.Validate("abcdefghi")
+	assert.Error(t, err)
+	_, err = policy.Validate("Sen5ei")
+	assert.Error(t, err)
+	_, err = policy.Validate("senpai")
+	assert.NoError(t, err)
+}
+
+func TestNormalize(t *testing.T) {
+	tests := map[string]string{
+		"Naruto":     "naruto",
+		"NARUTO":     "naruto",
+		"naruto.fan": "naruto_fan",
+		"naruto-fan": "naruto_fan",
+		"nаrutо":     "naruto", // Cyrillic а and о
+		"ｎａｒｕｔｏ":     "naruto", // fullwidth
+		"Straße":     "strasse",
+		"":           "",
+	}
+
+	for value, expected := range tests {
+		t.Run(value, func(t *testing.T) {
+			assert.Equal(t, expected, username.Normalize(value))
+		})
+	}
+}
+
+func TestPlaceholder(t *testing.T) {
+	placeholder := username.Placeholder("user_123")
+
+	assert.Equal(t, placeholder, username.Placeholder("user_123"), "placeholders are deterministic")
+	assert.NotEqual(t, placeholder, username.Placeholder("user_456"))
+
+	_, err := username.DefaultPolicy().Validate(placeholder)
+	assert.NoError(t, err)
+}
+
+func TestPolicy_Candidates(t *testing.T) {
+	policy := username.DefaultPolicy()
+
+	t.Run("name variants come before numbered ones", func(t *testing.T) {
+		candidates := policy.Candidates("naruto", "Naruto", "Uzumaki", []int{1, 2})
+		assert.Equal(t, []string{
+			"naruto_uzumaki",
+			"narutouzumaki",
+			"nuzumaki",
+			"narutou",
+			"uzumaki_naruto",
+		}, candidates.Names)
+		assert.Equal(t, []string{"naruto1", "naruto2"}, candidates.Numbered)
+	})
+
+	t.Run("variants that normalize alike are only suggested once", func(t *testing.T) {
+		candidates := policy.Candidates("Naruto", "naruto", "fan", nil)
+		assert.Equal(t, []string{"naruto_fan", "narutofan", "nfan", "narutof", "fan_naruto"}, candidates.All())
+	})
+
+	t.Run("invalid input is cleaned up before suffixing", func(t *testing.T) {
+		candidates := policy.Candidates("naruto!!", "", "", []int{7})
+		assert.Equal(t, []string{"naruto"}, candidates.Names)
+		assert.Equal(t, []string{"naruto7"}, candidates.Numbered)
+	})
+
+	t.Run("long names leave room for suffixes", func(t *testing.T) {
+		candidates := policy.Candidates("abcdefghijklmnopqrstuvwxyz1234", "", "", []int{99})
+		assert.Equal(t, []string{"abcdefghijklmnopqrstuvwxyz99"}, candidates.Numbered)
+	})
+
+	t.Run("reserved stems are not suggested", func(t *testing.T) {
+		candidates := policy.Candidates("admin", "", "", []int{1})
+		assert.NotContains(t, candidates.All(), "admin")
+	})
+}
@@ -0,0 +1,120 @@
+package username
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// suffixRoom is how many characters are kept free at the end of a base for numeric suffixes.
+const suffixRoom = 4
+
+// Candidates are usernames to suggest instead of one that cannot be used, most preferred first.
+// Every candidate passes the policy and no two share a normalized key.
+type Candidates struct {
+	// Names are the requested name cleaned up and variants of the user's first and last name.
+	Names []string
+	// Numbered are the requested name with a numeric suffix.
+	Numbered []string
+}
+
+// All returns every candidate, names first.
+func (c Candidates) All() []string {
+	return append(append([]string{}, c.Names...), c.Numbered...)
+}
+
+// Candidates builds alternatives to base from base itself, the user's first and last name and
+// the given numeric suffixes. Nothing equivalent to base is returned.
+func (p *Policy) Candidates(base string, firstName string, lastName string, suffixes []int) Candidates {
+	first := p.sanitize(firstName)
+	last := p.sanitize(lastName)
+	stem := p.sanitize(base)
+
+	var names []string
+	if first != "" && last != "" {
+		names = append(names,
+			first+"_"+last,
+			first+last,
+			first+"."+last,
+			firstRune(first)+last,
+			first+firstRune(last),
+			last+"_"+first,
+		)
+	}
+	if stem != "" {
+		names = append([]string{stem}, names...)
+	} else {
+		stem = first + last
+		if stem == "" {
+			stem = "weeb"
+		}
+	}
+
+	var numbered []string
+	for _, suffix := range suffixes {
+		numbered = append(numbered, stem+strconv.Itoa(suffix))
+	}
+
+	seen := map[string]bool{Normalize(base): true}
+	valid := func(values []string) []string {
+		var result []string
+		for _, value := range values {
+			display, err := p.Validate(p.truncate(value, p.MaxLength))
+			if err != nil {
+				continue
+			}
+			key := Normalize(display)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			result = append(result, display)
+		}
+
+		return result
+	}
+
+	return Candidates{
+		Names:    valid(names),
+		Numbered: valid(numbered),
+	}
+}
+
+// sanitize turns free text into something usable as part of a username: disallowed characters
+// become separators, separators are collapsed and trimmed, and room is left for a suffix.
+func (p *Policy) sanitize(value string) string {
+	var builder strings.Builder
+	previousSeparator := true
+	for _, r := range norm.NFC.String(strings.TrimSpace(value)) {
+		if unicode.IsLetter(r) || unicode.Is(unicode.Nd, r) {
+			builder.WriteRune(unicode.ToLower(r))
+			previousSeparator = false
+			continue
+		}
+		if !previousSeparator {
+			builder.WriteRune('_')
+			previousSeparator = true
+		}
+	}
+
+	sanitized := strings.Trim(builder.String(), "_")
+
+	return strings.Trim(p.truncate(sanitized, p.MaxLength-suffixRoom), "_")
+}
+
+func (p *Policy) truncate(value string, maxLength int) string {
+	if maxLength <= 0 || utf8.RuneCountInString(value) <= maxLength {
+		return value
+	}
+
+	return string([]rune(value)[:maxLength])
+}
+
+func firstRune(value string) string {
+	r, _ := utf8.DecodeRuneInString(value)
+
+	return string(r)
+}
@@ -0,0 +1,129 @@
+package users
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"time"
+
+	"github.com/weeb-vip/user-service/internal/services/users/username"
+	"github.com/weeb-vip/user-service/metrics"
+	"github.com/weeb-vip/user-service/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const maxSuggestions = 5
+
+// maxNameSuggestions leaves room for numbered suggestions when many name variants are free.
+const maxNameSuggestions = 3
+
+func (service *usersService) CheckUsername(
+	ctx context.Context,
+	value string,
+	firstName string,
+	lastName string,
+) (*UsernameAvailability, error) {
+	tracer := tracing.GetTracer(ctx)
+	ctx, span := tracer.Start(ctx, "service.CheckUsername",
+		trace.WithAttributes(
+			attribute.String("user.username", value),
+			attribute.String("service", "users"),
+			attribute.String("method", "CheckUsername"),
+		),
+		tracing.GetEnvironmentAttribute(),
+	)
+	defer span.End()
+
+	startTime := time.Now()
+
+	availability := &UsernameAvailability{Username: value}
+
+	display, err := service.usernamePolicy.Validate(value)
+	if err != nil {
+		reason := UserErrorUsernameInvalid
+		message := err.Error()
+		availability.Reason = &reason
+		availability.Message = &message
+	} else {
+		availability.Username = display
+
+		err := service.checkUsernameClaim(ctx, "", display)
+		if err == nil {
+			availability.Available = true
+			service.recordCheckUsernameMetric(startTime, metrics.Success)
+			return availability, nil
+		}
+
+		var userErr *Error
+		if !errors.As(err, &userErr) || userErr.Code != UserErrorUsernameTaken {
+			service.recordCheckUsernameMetric(startTime, metrics.Error)
+			return nil, err
+		}
+		availability.Reason = &userErr.Code
+		availability.Message = &userErr.Message
+	}
+
+	suggestions, err := service.suggestUsernames(ctx, value, firstName, lastName)
+	if err != nil {
+		service.recordCheckUsernameMetric(startTime, metrics.Error)
+		return nil, &Error{
+			Code:    UserErrorInternalError,
+			Message: "database error",
+		}
+	}
+	availability.Suggestions = suggestions
+
+	span.SetAttributes(attribute.Int("username.suggestions", len(suggestions)))
+	service.recordCheckUsernameMetric(startTime, metrics.Success)
+
+	return availability, nil
+}
+
+// suggestUsernames returns up to maxSuggestions free usernames, checked with a single query.
+func (service *usersService) suggestUsernames(ctx context.Context, value string, firstName string, lastName string) ([]string, error) {
+	// A few small numbers read nicely; random ones help when those are gone for popular names
+	suffixes := []int{1, 2, 3}
+	for i := 0; i < 3; i++ {
+		suffixes = append(suffixes, 100+rand.Intn(900)) // nolint:gosec
+	}
+
+	candidates := service.usernamePolicy.Candidates(value, firstName, lastName, suffixes)
+	all := candidates.All()
+	if len(all) == 0 {
+		return []string{}, nil
+	}
+
+	taken, err := service.usersRepository.FindTakenUsernames(ctx, all, service.now().Add(-service.releaseCooldown))
+	if err != nil {
+		return nil, err
+	}
+
+	free := func(values []string, limit int) []string {
+		result := []string{}
+		for _, value := range values {
+			if len(result) == limit {
+				break
+			}
+			if !taken[username.Normalize(value)] {
+				result = append(result, value)
+			}
+		}
+
+		return result
+	}
+
+	suggestions := free(candidates.Names, maxNameSuggestions)
+	suggestions = append(suggestions, free(candidates.Numbered, maxSuggestions-len(suggestions))...)
+
+	return suggestions, nil
+}
+
+func (service *usersService) recordCheckUsernameMetric(startTime time.Time, result string) {
+	metrics.GetAppMetrics().ServiceMetric(
+		float64(time.Since(startTime).Milliseconds()),
+		"users",
+		"CheckUsername",
+		result,
+	)
+}
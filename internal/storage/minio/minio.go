@@ -3,11 +3,13 @@ package minio
 import (
 	"bytes"
 	"context"
+	"fmt"
 	"github.com/minio/minio-go/v7"
 	"github.com/minio/minio-go/v7/pkg/credentials"
 	"github.com/weeb-vip/user-service/config"
 	"github.com/weeb-vip/user-service/internal/logger"
 	"github.com/weeb-vip/user-service/internal/storage"
+	"net/http"
 )
 
 type MinioStorageImpl struct {
@@ -16,6 +18,30 @@ type MinioStorageImpl struct {
 }
 
 func NewMinioStorage(cfg config.MinioConfig) storage.Storage {
+	return &MinioStorageImpl{
+		Client: newClient(cfg),
+		Bucket: cfg.Bucket,
+	}
+}
+
+// NewBucketCheck returns a health check that fails unless the configured bucket is reachable.
+func NewBucketCheck(cfg config.MinioConfig) func(ctx context.Context) error {
+	client := newClient(cfg)
+
+	return func(ctx context.Context) error {
+		exists, err := client.BucketExists(ctx, cfg.Bucket)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return fmt.Errorf("bucket %s does not exist", cfg.Bucket)
+		}
+
+		return nil
+	}
+}
+
+func newClient(cfg config.MinioConfig) *minio.Client {
 	minioClient, err := minio.New(cfg.Endpoint, &minio.Options{
 		Creds:  credentials.NewStaticV4(cfg.AccessKeyID, cfg.SecretAccessKey, ""),
 		Secure: cfg.UseSSL,
@@ -23,17 +49,22 @@ func NewMinioStorage(cfg config.MinioConfig) storage.Storage {
 	if err != nil {
 		panic(err)
 	}
-	return &MinioStorageImpl{
-		Client: minioClient,
-		Bucket: cfg.Bucket,
-	}
+
+	return minioClient
 }
 
-func (m *MinioStorageImpl) Put(ctx context.Context, data []byte, path string) error {
+func (m *MinioStorageImpl) Put(ctx context.Context, data []byte, path string, opts ...storage.PutOption) error {
 	log := logger.FromCtx(ctx)
 	log.Info().Str("path", path).Msg("uploading to minio")
+
+	putOptions := storage.PutOptions{ContentType: http.DetectContentType(data)}
+	for _, opt := range opts {
+		opt(&putOptions)
+	}
+
 	_, err := m.Client.PutObject(ctx, m.Bucket, path, bytes.NewReader(data), int64(len(data)), minio.PutObjectOptions{
-		ContentType: "application/octet-stream",
+		ContentType:  putOptions.ContentType,
+		CacheControl: putOptions.CacheControl,
 	})
 
 	if err != nil {
@@ -60,3 +91,122 @@ func (m *MinioStorageImpl) Get(ctx context.Context, path string) ([]byte, error)
 func (m *MinioStorageImpl) Delete(ctx context.Context, path string) error {
 	return m.Client.RemoveObject(ctx, m.Bucket, path, minio.RemoveObjectOptions{})
 }
+
+func (m *MinioStorageImpl) List(ctx context.Context, prefix string) storage.ObjectIterator {
+	return &objectIterator{
+		client:   m.Client,
+		bucket:   m.Bucket,
+		prefix:   prefix,
+		pageSize: storage.DefaultPageSize,
+	}
+}
+
+func (m *MinioStorageImpl) Stat(ctx context.Context, path string) (*storage.ObjectInfo, error) {
+	info, err := m.Client.StatObject(ctx, m.Bucket, path, minio.StatObjectOptions{})
+	if err != nil {
+		if minio.ToErrorResponse(err).Code == minio.NoSuchKey {
+			return nil, storage.ErrObjectNotFound
+		}
+		return nil, err
+	}
+
+	return toObjectInfo(info), nil
+}
+
+func (m *MinioStorageImpl) DeletePrefix(ctx context.Context, prefix string) error {
+	log := logger.FromCtx(ctx)
+	log.Info().Str("prefix", prefix).Msg("deleting prefix from minio")
+
+	objectsCh := make(chan minio.ObjectInfo)
+	listErrCh := make(chan error, 1)
+
+	// Cancelled once removal stops, so the lister can't block forever on a send nobody receives
+	listCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	go func() {
+		defer close(objectsCh)
+		for object := range m.Client.ListObjectsIter(listCtx, m.Bucket, minio.ListObjectsOptions{
+			Prefix:    prefix,
+			Recursive: true,
+		}) {
+			if object.Err != nil {
+				listErrCh <- object.Err
+				return
+			}
+			select {
+			case objectsCh <- object:
+			case <-listCtx.Done():
+				return
+			}
+		}
+	}()
+
+	var removeErr error
+	for result := range m.Client.RemoveObjects(ctx, m.Bucket, objectsCh, minio.RemoveObjectsOptions{}) {
+		log.Error().Str("path", result.ObjectName).Err(result.Err).Msg("error deleting from minio")
+		if removeErr == nil {
+			removeErr = fmt.Errorf("failed to delete %s: %w", result.ObjectName, result.Err)
+		}
+	}
+	cancel()
+
+	select {
+	case err := <-listErrCh:
+		return err
+	default:
+		return removeErr
+	}
+}
+
+// objectIterator fetches one page per Next call, resuming after the last key it returned.
+type objectIterator struct {
+	client     *minio.Client
+	bucket     string
+	prefix     string
+	pageSize   int
+	startAfter string
+	done       bool
+}
+
+func (it *objectIterator) Next(ctx context.Context) ([]storage.ObjectInfo, error) {
+	if it.done {
+		return nil, storage.ErrIteratorDone
+	}
+
+	page := make([]storage.ObjectInfo, 0, it.pageSize)
+	for object := range it.client.ListObjectsIter(ctx, it.bucket, minio.ListObjectsOptions{
+		Prefix:     it.prefix,
+		Recursive:  true,
+		MaxKeys:    it.pageSize,
+		StartAfter: it.startAfter,
+	}) {
+		if object.Err != nil {
+			return nil, object.Err
+		}
+		page = append(page, *toObjectInfo(object))
+		if len(page) == it.pageSize {
+			break
+		}
+	}
+
+	if len(page) < it.pageSize {
+		it.done = true
+	}
+	if len(page) == 0 {
+		return nil, storage.ErrIteratorDone
+	}
+
+	it.startAfter = page[len(page)-1].Path
+
+	return page, nil
+}
+
+func toObjectInfo(info minio.ObjectInfo) *storage.ObjectInfo {
+	return &storage.ObjectInfo{
+		Path:         info.Key,
+		Size:         info.Size,
+		ContentType:  info.ContentType,
+		LastModified: info.LastModified,
+	}
+}
@@ -1,9 +1,92 @@
 package storage
 
-import "context"
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// DefaultPageSize is the number of objects returned by a single ObjectIterator.Next call.
+const DefaultPageSize = 100
+
+var (
+	// ErrObjectNotFound is returned by Stat when no object exists at the given path.
+	ErrObjectNotFound = errors.New("object not found")
+	// ErrIteratorDone is returned by ObjectIterator.Next once every page has been read.
+	ErrIteratorDone = errors.New("no more objects")
+)
 
 type Storage interface {
-	Put(ctx context.Context, data []byte, path string) error
+	Put(ctx context.Context, data []byte, path string, opts ...PutOption) error
 	Get(ctx context.Context, path string) ([]byte, error)
 	Delete(ctx context.Context, path string) error
+	List(ctx context.Context, prefix string) ObjectIterator
+	Stat(ctx context.Context, path string) (*ObjectInfo, error)
+	DeletePrefix(ctx context.Context, prefix string) error
+}
+
+// PutOptions holds per-object metadata applied on upload.
+type PutOptions struct {
+	ContentType  string
+	CacheControl string
+}
+
+// PutOption configures a Put call.
+type PutOption func(*PutOptions)
+
+// WithContentType overrides the content type detected from the uploaded bytes.
+func WithContentType(contentType string) PutOption {
+	return func(o *PutOptions) {
+		o.ContentType = contentType
+	}
+}
+
+// WithCacheControl sets the Cache-Control header served with the object.
+func WithCacheControl(cacheControl string) PutOption {
+	return func(o *PutOptions) {
+		o.CacheControl = cacheControl
+	}
+}
+
+// ObjectIterator pages through the objects stored under a prefix.
+type ObjectIterator interface {
+	// Next returns the next page of objects, or ErrIteratorDone when the listing is exhausted.
+	Next(ctx context.Context) ([]ObjectInfo, error)
+}
+
+type ObjectInfo struct {
+	Path         string
+	Size         int64
+	ContentType  string
+	LastModified time.Time
+}
+
+// Exists reports whether an object is stored at path.
+func Exists(ctx context.Context, s Storage, path string) (bool, error) {
+	_, err := s.Stat(ctx, path)
+	if errors.Is(err, ErrObjectNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// ListAll drains an iterator and returns every object under prefix.
+func ListAll(ctx context.Context, s Storage, prefix string) ([]ObjectInfo, error) {
+	iterator := s.List(ctx, prefix)
+
+	var objects []ObjectInfo
+	for {
+		page, err := iterator.Next(ctx)
+		if errors.Is(err, ErrIteratorDone) {
+			return objects, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		objects = append(objects, page...)
+	}
 }
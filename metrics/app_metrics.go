@@ -1,8 +1,10 @@
 package metrics
 
 import (
-	"github.com/weeb-vip/user-service/config"
+	"time"
+
 	metricsLib "github.com/weeb-vip/go-metrics-lib"
+	"github.com/weeb-vip/user-service/config"
 )
 
 // AppMetrics provides a centralized metrics interface with default tags
@@ -70,6 +72,69 @@ func (m *AppMetrics) ServiceMetric(duration float64, service string, method stri
 	m.DatabaseMetric(duration, service, method, result)
 }
 
+// EventMetric records how long handling an event consumed from topic took
+func (m *AppMetrics) EventMetric(duration float64, topic string, result string) {
+	labels := m.eventLabels(topic)
+	labels["result"] = result
+	_ = m.metricsImpl.HistogramMetric(EventDurationMetric, duration, labels)
+}
+
+// EventRetryMetric counts a retry of a failed event consumed from topic
+func (m *AppMetrics) EventRetryMetric(topic string) {
+	_ = m.metricsImpl.CountMetric(EventRetriesMetric, m.eventLabels(topic))
+}
+
+// EventDeadLetterMetric counts an event from topic moved to its dead-letter topic
+func (m *AppMetrics) EventDeadLetterMetric(topic string) {
+	_ = m.metricsImpl.CountMetric(EventDeadLetteredMetric, m.eventLabels(topic))
+}
+
+// KeyPublishMetric records one attempt at publishing a public key to the key-management service
+func (m *AppMetrics) KeyPublishMetric(duration float64, result string) {
+	labels := m.keyLabels()
+	labels["result"] = result
+	_ = m.metricsImpl.HistogramMetric(KeyPublishDurationMetric, duration, labels)
+}
+
+// KeyPublishCircuitMetric records whether key publishing is failing fast
+func (m *AppMetrics) KeyPublishCircuitMetric(open bool) {
+	value := 0.0
+	if open {
+		value = 1
+	}
+	_ = m.metricsImpl.GaugeMetric(KeyPublishCircuitOpenMetric, value, m.keyLabels())
+}
+
+// KeyRotationMetric counts a signing key rotation attempt, and timestamps the successful ones
+func (m *AppMetrics) KeyRotationMetric(result string) {
+	labels := m.keyLabels()
+	labels["result"] = result
+	_ = m.metricsImpl.CountMetric(KeyRotationsMetric, labels)
+	if result == Success {
+		_ = m.metricsImpl.GaugeMetric(KeyLastRotationMetric, float64(time.Now().Unix()), m.keyLabels())
+	}
+}
+
+// KeyAgeMetric records how long ago the key currently signing tokens was generated
+func (m *AppMetrics) KeyAgeMetric(age time.Duration) {
+	_ = m.metricsImpl.GaugeMetric(KeyAgeMetric, age.Seconds(), m.keyLabels())
+}
+
+func (m *AppMetrics) keyLabels() map[string]string {
+	return map[string]string{
+		"service": m.defaultTags["service"],
+		"env":     m.defaultTags["env"],
+	}
+}
+
+func (m *AppMetrics) eventLabels(topic string) map[string]string {
+	return map[string]string{
+		"service": m.defaultTags["service"],
+		"topic":   topic,
+		"env":     m.defaultTags["env"],
+	}
+}
+
 // GetDefaultTags returns the default tags for this metrics instance
 func (m *AppMetrics) GetDefaultTags() map[string]string {
 	// Return a copy to prevent modification
@@ -98,4 +163,4 @@ func (m *AppMetrics) WithTags(additionalTags map[string]string) *AppMetrics {
 		metricsImpl: m.metricsImpl,
 		defaultTags: newTags,
 	}
-}
\ No newline at end of file
+}
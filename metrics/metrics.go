@@ -42,6 +42,35 @@ func initMetrics(prometheusInstance *prometheus.PrometheusClient) {
 		1000,
 	})
 
+	prometheusInstance.CreateHistogramVec(EventDurationMetric, "event handling millisecond", []string{"service", "topic", "result", "env"}, []float64{
+		10,
+		50,
+		100,
+		250,
+		500,
+		1000,
+		2500,
+		5000,
+		10000,
+	})
+	prometheusInstance.CreateCounterVec(EventRetriesMetric, "events retried after a processing failure", []string{"service", "topic", "env"})
+	prometheusInstance.CreateCounterVec(EventDeadLetteredMetric, "events moved to a dead-letter topic", []string{"service", "topic", "env"})
+
+	prometheusInstance.CreateHistogramVec(KeyPublishDurationMetric, "key-management service publish attempt millisecond", []string{"service", "result", "env"}, []float64{
+		50,
+		100,
+		250,
+		500,
+		1000,
+		2500,
+		5000,
+		10000,
+	})
+	prometheusInstance.CreateGaugeVec(KeyPublishCircuitOpenMetric, "1 while publishing keys fails fast because the key-management service keeps failing", []string{"service", "env"})
+	prometheusInstance.CreateCounterVec(KeyRotationsMetric, "signing key rotations", []string{"service", "result", "env"})
+	prometheusInstance.CreateGaugeVec(KeyLastRotationMetric, "unix time of the last successful signing key rotation", []string{"service", "env"})
+	prometheusInstance.CreateGaugeVec(KeyAgeMetric, "age of the key currently signing tokens", []string{"service", "env"})
+
 	prometheusInstance.CreateHistogramVec("database_query_duration_histogram_milliseconds", "database calls millisecond", []string{"service", "table", "method", "result", "env"}, []float64{
 		// create buckets 10000 split into 10 buckets
 		100,
@@ -57,6 +86,22 @@ func initMetrics(prometheusInstance *prometheus.PrometheusClient) {
 	})
 }
 
+// Eventing metrics, labelled by service, topic and env
+const (
+	EventDurationMetric     = "eventing_handling_duration_histogram_milliseconds"
+	EventRetriesMetric      = "eventing_retries_total"
+	EventDeadLetteredMetric = "eventing_dead_lettered_total"
+)
+
+// Signing key metrics, labelled by service and env
+const (
+	KeyPublishDurationMetric    = "key_publish_duration_histogram_milliseconds"
+	KeyPublishCircuitOpenMetric = "key_publish_circuit_open"
+	KeyRotationsMetric          = "signing_key_rotations_total"
+	KeyLastRotationMetric       = "signing_key_last_rotation_success_timestamp_seconds"
+	KeyAgeMetric                = "signing_key_age_seconds"
+)
+
 func GetCurrentEnv() string {
 	cfg := config.LoadConfigOrPanic()
 	return cfg.APPConfig.Env
@@ -0,0 +1,101 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/services/guests/repositories/guest_profiles.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/services/guests/repositories/guest_profiles.go -destination=mocks/mock_guest_profiles_repository.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	models "github.com/weeb-vip/user-service/internal/services/guests/models"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockGuestProfilesRepository is a mock of GuestProfilesRepository interface.
+type MockGuestProfilesRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockGuestProfilesRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockGuestProfilesRepositoryMockRecorder is the mock recorder for MockGuestProfilesRepository.
+type MockGuestProfilesRepositoryMockRecorder struct {
+	mock *MockGuestProfilesRepository
+}
+
+// NewMockGuestProfilesRepository creates a new mock instance.
+func NewMockGuestProfilesRepository(ctrl *gomock.Controller) *MockGuestProfilesRepository {
+	mock := &MockGuestProfilesRepository{ctrl: ctrl}
+	mock.recorder = &MockGuestProfilesRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockGuestProfilesRepository) EXPECT() *MockGuestProfilesRepositoryMockRecorder {
+	return m.recorder
+}
+
+// DeleteExpiredGuestProfiles mocks base method.
+func (m *MockGuestProfilesRepository) DeleteExpiredGuestProfiles(ctx context.Context, before time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpiredGuestProfiles", ctx, before)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteExpiredGuestProfiles indicates an expected call of DeleteExpiredGuestProfiles.
+func (mr *MockGuestProfilesRepositoryMockRecorder) DeleteExpiredGuestProfiles(ctx, before any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpiredGuestProfiles", reflect.TypeOf((*MockGuestProfilesRepository)(nil).DeleteExpiredGuestProfiles), ctx, before)
+}
+
+// DeleteGuestProfile mocks base method.
+func (m *MockGuestProfilesRepository) DeleteGuestProfile(ctx context.Context, guestID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteGuestProfile", ctx, guestID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteGuestProfile indicates an expected call of DeleteGuestProfile.
+func (mr *MockGuestProfilesRepositoryMockRecorder) DeleteGuestProfile(ctx, guestID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteGuestProfile", reflect.TypeOf((*MockGuestProfilesRepository)(nil).DeleteGuestProfile), ctx, guestID)
+}
+
+// GetGuestProfile mocks base method.
+func (m *MockGuestProfilesRepository) GetGuestProfile(ctx context.Context, guestID string) (*models.GuestProfile, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGuestProfile", ctx, guestID)
+	ret0, _ := ret[0].(*models.GuestProfile)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGuestProfile indicates an expected call of GetGuestProfile.
+func (mr *MockGuestProfilesRepositoryMockRecorder) GetGuestProfile(ctx, guestID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGuestProfile", reflect.TypeOf((*MockGuestProfilesRepository)(nil).GetGuestProfile), ctx, guestID)
+}
+
+// SaveGuestProfile mocks base method.
+func (m *MockGuestProfilesRepository) SaveGuestProfile(ctx context.Context, profile *models.GuestProfile) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveGuestProfile", ctx, profile)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveGuestProfile indicates an expected call of SaveGuestProfile.
+func (mr *MockGuestProfilesRepositoryMockRecorder) SaveGuestProfile(ctx, profile any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveGuestProfile", reflect.TypeOf((*MockGuestProfilesRepository)(nil).SaveGuestProfile), ctx, profile)
+}
@@ -0,0 +1,87 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/services/image/repositories/image.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/services/image/repositories/image.go -destination=mocks/mock_images_repository.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	models "github.com/weeb-vip/user-service/internal/services/image/models"
+	repositories "github.com/weeb-vip/user-service/internal/services/image/repositories"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockImagesRepository is a mock of ImagesRepository interface.
+type MockImagesRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockImagesRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockImagesRepositoryMockRecorder is the mock recorder for MockImagesRepository.
+type MockImagesRepositoryMockRecorder struct {
+	mock *MockImagesRepository
+}
+
+// NewMockImagesRepository creates a new mock instance.
+func NewMockImagesRepository(ctrl *gomock.Controller) *MockImagesRepository {
+	mock := &MockImagesRepository{ctrl: ctrl}
+	mock.recorder = &MockImagesRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockImagesRepository) EXPECT() *MockImagesRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Acquire mocks base method.
+func (m *MockImagesRepository) Acquire(ctx context.Context, hash, path string, size int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Acquire", ctx, hash, path, size)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Acquire indicates an expected call of Acquire.
+func (mr *MockImagesRepositoryMockRecorder) Acquire(ctx, hash, path, size any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Acquire", reflect.TypeOf((*MockImagesRepository)(nil).Acquire), ctx, hash, path, size)
+}
+
+// GetImageByHash mocks base method.
+func (m *MockImagesRepository) GetImageByHash(ctx context.Context, hash string) (*models.Image, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetImageByHash", ctx, hash)
+	ret0, _ := ret[0].(*models.Image)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetImageByHash indicates an expected call of GetImageByHash.
+func (mr *MockImagesRepositoryMockRecorder) GetImageByHash(ctx, hash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetImageByHash", reflect.TypeOf((*MockImagesRepository)(nil).GetImageByHash), ctx, hash)
+}
+
+// Release mocks base method.
+func (m *MockImagesRepository) Release(ctx context.Context, hash string, onLast repositories.OnLastReference) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Release", ctx, hash, onLast)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Release indicates an expected call of Release.
+func (mr *MockImagesRepositoryMockRecorder) Release(ctx, hash, onLast any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Release", reflect.TypeOf((*MockImagesRepository)(nil).Release), ctx, hash, onLast)
+}
@@ -0,0 +1,117 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/services/moderation/repositories/profile_image.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/services/moderation/repositories/profile_image.go -destination=mocks/mock_profile_images_repository.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	models "github.com/weeb-vip/user-service/internal/services/moderation/models"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockProfileImagesRepository is a mock of ProfileImagesRepository interface.
+type MockProfileImagesRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockProfileImagesRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockProfileImagesRepositoryMockRecorder is the mock recorder for MockProfileImagesRepository.
+type MockProfileImagesRepositoryMockRecorder struct {
+	mock *MockProfileImagesRepository
+}
+
+// NewMockProfileImagesRepository creates a new mock instance.
+func NewMockProfileImagesRepository(ctrl *gomock.Controller) *MockProfileImagesRepository {
+	mock := &MockProfileImagesRepository{ctrl: ctrl}
+	mock.recorder = &MockProfileImagesRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockProfileImagesRepository) EXPECT() *MockProfileImagesRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AddProfileImage mocks base method.
+func (m *MockProfileImagesRepository) AddProfileImage(ctx context.Context, userID, path string) (*models.ProfileImage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddProfileImage", ctx, userID, path)
+	ret0, _ := ret[0].(*models.ProfileImage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddProfileImage indicates an expected call of AddProfileImage.
+func (mr *MockProfileImagesRepositoryMockRecorder) AddProfileImage(ctx, userID, path any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddProfileImage", reflect.TypeOf((*MockProfileImagesRepository)(nil).AddProfileImage), ctx, userID, path)
+}
+
+// GetProfileImageByID mocks base method.
+func (m *MockProfileImagesRepository) GetProfileImageByID(ctx context.Context, id string) (*models.ProfileImage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProfileImageByID", ctx, id)
+	ret0, _ := ret[0].(*models.ProfileImage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProfileImageByID indicates an expected call of GetProfileImageByID.
+func (mr *MockProfileImagesRepositoryMockRecorder) GetProfileImageByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProfileImageByID", reflect.TypeOf((*MockProfileImagesRepository)(nil).GetProfileImageByID), ctx, id)
+}
+
+// ListProfileImagesByStatus mocks base method.
+func (m *MockProfileImagesRepository) ListProfileImagesByStatus(ctx context.Context, status models.Status, limit, offset int) ([]*models.ProfileImage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListProfileImagesByStatus", ctx, status, limit, offset)
+	ret0, _ := ret[0].([]*models.ProfileImage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListProfileImagesByStatus indicates an expected call of ListProfileImagesByStatus.
+func (mr *MockProfileImagesRepositoryMockRecorder) ListProfileImagesByStatus(ctx, status, limit, offset any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListProfileImagesByStatus", reflect.TypeOf((*MockProfileImagesRepository)(nil).ListProfileImagesByStatus), ctx, status, limit, offset)
+}
+
+// ListUserProfileImagesByStatus mocks base method.
+func (m *MockProfileImagesRepository) ListUserProfileImagesByStatus(ctx context.Context, userID string, status models.Status) ([]*models.ProfileImage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUserProfileImagesByStatus", ctx, userID, status)
+	ret0, _ := ret[0].([]*models.ProfileImage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUserProfileImagesByStatus indicates an expected call of ListUserProfileImagesByStatus.
+func (mr *MockProfileImagesRepositoryMockRecorder) ListUserProfileImagesByStatus(ctx, userID, status any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUserProfileImagesByStatus", reflect.TypeOf((*MockProfileImagesRepository)(nil).ListUserProfileImagesByStatus), ctx, userID, status)
+}
+
+// UpdateStatus mocks base method.
+func (m *MockProfileImagesRepository) UpdateStatus(ctx context.Context, id string, from, to models.Status, reviewerID, reason *string) (*models.ProfileImage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateStatus", ctx, id, from, to, reviewerID, reason)
+	ret0, _ := ret[0].(*models.ProfileImage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateStatus indicates an expected call of UpdateStatus.
+func (mr *MockProfileImagesRepositoryMockRecorder) UpdateStatus(ctx, id, from, to, reviewerID, reason any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStatus", reflect.TypeOf((*MockProfileImagesRepository)(nil).UpdateStatus), ctx, id, from, to, reviewerID, reason)
+}
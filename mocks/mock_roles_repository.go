@@ -0,0 +1,87 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/services/roles/repositories/roles.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/services/roles/repositories/roles.go -destination=mocks/mock_roles_repository.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	models "github.com/weeb-vip/user-service/internal/services/roles/models"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockRolesRepository is a mock of RolesRepository interface.
+type MockRolesRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRolesRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockRolesRepositoryMockRecorder is the mock recorder for MockRolesRepository.
+type MockRolesRepositoryMockRecorder struct {
+	mock *MockRolesRepository
+}
+
+// NewMockRolesRepository creates a new mock instance.
+func NewMockRolesRepository(ctrl *gomock.Controller) *MockRolesRepository {
+	mock := &MockRolesRepository{ctrl: ctrl}
+	mock.recorder = &MockRolesRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRolesRepository) EXPECT() *MockRolesRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AssignRole mocks base method.
+func (m *MockRolesRepository) AssignRole(ctx context.Context, change *models.RoleChange) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AssignRole", ctx, change)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AssignRole indicates an expected call of AssignRole.
+func (mr *MockRolesRepositoryMockRecorder) AssignRole(ctx, change any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssignRole", reflect.TypeOf((*MockRolesRepository)(nil).AssignRole), ctx, change)
+}
+
+// GetRoles mocks base method.
+func (m *MockRolesRepository) GetRoles(ctx context.Context, userID string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRoles", ctx, userID)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRoles indicates an expected call of GetRoles.
+func (mr *MockRolesRepositoryMockRecorder) GetRoles(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRoles", reflect.TypeOf((*MockRolesRepository)(nil).GetRoles), ctx, userID)
+}
+
+// RevokeRole mocks base method.
+func (m *MockRolesRepository) RevokeRole(ctx context.Context, change *models.RoleChange) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeRole", ctx, change)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RevokeRole indicates an expected call of RevokeRole.
+func (mr *MockRolesRepositoryMockRecorder) RevokeRole(ctx, change any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeRole", reflect.TypeOf((*MockRolesRepository)(nil).RevokeRole), ctx, change)
+}
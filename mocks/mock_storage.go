@@ -13,6 +13,7 @@ import (
 	context "context"
 	reflect "reflect"
 
+	storage "github.com/weeb-vip/user-service/internal/storage"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -54,6 +55,20 @@ func (mr *MockStorageMockRecorder) Delete(ctx, path any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockStorage)(nil).Delete), ctx, path)
 }
 
+// DeletePrefix mocks base method.
+func (m *MockStorage) DeletePrefix(ctx context.Context, prefix string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeletePrefix", ctx, prefix)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeletePrefix indicates an expected call of DeletePrefix.
+func (mr *MockStorageMockRecorder) DeletePrefix(ctx, prefix any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePrefix", reflect.TypeOf((*MockStorage)(nil).DeletePrefix), ctx, prefix)
+}
+
 // Get mocks base method.
 func (m *MockStorage) Get(ctx context.Context, path string) ([]byte, error) {
 	m.ctrl.T.Helper()
@@ -69,16 +84,89 @@ func (mr *MockStorageMockRecorder) Get(ctx, path any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockStorage)(nil).Get), ctx, path)
 }
 
+// List mocks base method.
+func (m *MockStorage) List(ctx context.Context, prefix string) storage.ObjectIterator {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, prefix)
+	ret0, _ := ret[0].(storage.ObjectIterator)
+	return ret0
+}
+
+// List indicates an expected call of List.
+func (mr *MockStorageMockRecorder) List(ctx, prefix any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockStorage)(nil).List), ctx, prefix)
+}
+
 // Put mocks base method.
-func (m *MockStorage) Put(ctx context.Context, data []byte, path string) error {
+func (m *MockStorage) Put(ctx context.Context, data []byte, path string, opts ...storage.PutOption) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Put", ctx, data, path)
+	varargs := []any{ctx, data, path}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Put", varargs...)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Put indicates an expected call of Put.
-func (mr *MockStorageMockRecorder) Put(ctx, data, path any) *gomock.Call {
+func (mr *MockStorageMockRecorder) Put(ctx, data, path any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, data, path}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Put", reflect.TypeOf((*MockStorage)(nil).Put), varargs...)
+}
+
+// Stat mocks base method.
+func (m *MockStorage) Stat(ctx context.Context, path string) (*storage.ObjectInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Stat", ctx, path)
+	ret0, _ := ret[0].(*storage.ObjectInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Stat indicates an expected call of Stat.
+func (mr *MockStorageMockRecorder) Stat(ctx, path any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stat", reflect.TypeOf((*MockStorage)(nil).Stat), ctx, path)
+}
+
+// MockObjectIterator is a mock of ObjectIterator interface.
+type MockObjectIterator struct {
+	ctrl     *gomock.Controller
+	recorder *MockObjectIteratorMockRecorder
+	isgomock struct{}
+}
+
+// MockObjectIteratorMockRecorder is the mock recorder for MockObjectIterator.
+type MockObjectIteratorMockRecorder struct {
+	mock *MockObjectIterator
+}
+
+// NewMockObjectIterator creates a new mock instance.
+func NewMockObjectIterator(ctrl *gomock.Controller) *MockObjectIterator {
+	mock := &MockObjectIterator{ctrl: ctrl}
+	mock.recorder = &MockObjectIteratorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockObjectIterator) EXPECT() *MockObjectIteratorMockRecorder {
+	return m.recorder
+}
+
+// Next mocks base method.
+func (m *MockObjectIterator) Next(ctx context.Context) ([]storage.ObjectInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Next", ctx)
+	ret0, _ := ret[0].([]storage.ObjectInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Next indicates an expected call of Next.
+func (mr *MockObjectIteratorMockRecorder) Next(ctx any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Put", reflect.TypeOf((*MockStorage)(nil).Put), ctx, data, path)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Next", reflect.TypeOf((*MockObjectIterator)(nil).Next), ctx)
 }
@@ -0,0 +1,72 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/services/settings/repositories/user_settings.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/services/settings/repositories/user_settings.go -destination=mocks/mock_user_settings_repository.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	models "github.com/weeb-vip/user-service/internal/services/settings/models"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockUserSettingsRepository is a mock of UserSettingsRepository interface.
+type MockUserSettingsRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockUserSettingsRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockUserSettingsRepositoryMockRecorder is the mock recorder for MockUserSettingsRepository.
+type MockUserSettingsRepositoryMockRecorder struct {
+	mock *MockUserSettingsRepository
+}
+
+// NewMockUserSettingsRepository creates a new mock instance.
+func NewMockUserSettingsRepository(ctrl *gomock.Controller) *MockUserSettingsRepository {
+	mock := &MockUserSettingsRepository{ctrl: ctrl}
+	mock.recorder = &MockUserSettingsRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUserSettingsRepository) EXPECT() *MockUserSettingsRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetUserSettings mocks base method.
+func (m *MockUserSettingsRepository) GetUserSettings(ctx context.Context, userID string) (*models.UserSettings, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserSettings", ctx, userID)
+	ret0, _ := ret[0].(*models.UserSettings)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserSettings indicates an expected call of GetUserSettings.
+func (mr *MockUserSettingsRepositoryMockRecorder) GetUserSettings(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserSettings", reflect.TypeOf((*MockUserSettingsRepository)(nil).GetUserSettings), ctx, userID)
+}
+
+// SaveUserSettings mocks base method.
+func (m *MockUserSettingsRepository) SaveUserSettings(ctx context.Context, userID string, version int, values models.Values) (*models.UserSettings, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveUserSettings", ctx, userID, version, values)
+	ret0, _ := ret[0].(*models.UserSettings)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SaveUserSettings indicates an expected call of SaveUserSettings.
+func (mr *MockUserSettingsRepositoryMockRecorder) SaveUserSettings(ctx, userID, version, values any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveUserSettings", reflect.TypeOf((*MockUserSettingsRepository)(nil).SaveUserSettings), ctx, userID, version, values)
+}
@@ -8,8 +8,9 @@ import (
 	context "context"
 	reflect "reflect"
 
-	models "github.com/weeb-vip/user-service/internal/services/users/models"
 	gomock "github.com/golang/mock/gomock"
+	users "github.com/weeb-vip/user-service/internal/services/users"
+	models "github.com/weeb-vip/user-service/internal/services/users/models"
 )
 
 // MockUser is a mock of User interface.
@@ -36,18 +37,94 @@ func (m *MockUser) EXPECT() *MockUserMockRecorder {
 }
 
 // AddUser mocks base method.
-func (m *MockUser) AddUser(arg0 context.Context, arg1, arg2, arg3, arg4 string) (*models.User, error) {
+func (m *MockUser) AddUser(arg0 context.Context, arg1, arg2, arg3, arg4, arg5 string, arg6 *string) (*models.User, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "AddUser", arg0, arg1, arg2, arg3, arg4)
+	ret := m.ctrl.Call(m, "AddUser", arg0, arg1, arg2, arg3, arg4, arg5, arg6)
 	ret0, _ := ret[0].(*models.User)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // AddUser indicates an expected call of AddUser.
-func (mr *MockUserMockRecorder) AddUser(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+func (mr *MockUserMockRecorder) AddUser(arg0, arg1, arg2, arg3, arg4, arg5, arg6 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddUser", reflect.TypeOf((*MockUser)(nil).AddUser), arg0, arg1, arg2, arg3, arg4, arg5, arg6)
+}
+
+// CheckUsername mocks base method.
+func (m *MockUser) CheckUsername(arg0 context.Context, arg1, arg2, arg3 string) (*users.UsernameAvailability, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckUsername", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*users.UsernameAvailability)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CheckUsername indicates an expected call of CheckUsername.
+func (mr *MockUserMockRecorder) CheckUsername(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckUsername", reflect.TypeOf((*MockUser)(nil).CheckUsername), arg0, arg1, arg2, arg3)
+}
+
+// DeleteUser mocks base method.
+func (m *MockUser) DeleteUser(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUser", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteUser indicates an expected call of DeleteUser.
+func (mr *MockUserMockRecorder) DeleteUser(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUser", reflect.TypeOf((*MockUser)(nil).DeleteUser), arg0, arg1)
+}
+
+// EnsureUser mocks base method.
+func (m *MockUser) EnsureUser(arg0 context.Context, arg1 string, arg2 *string, arg3 string) (*models.User, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnsureUser", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*models.User)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// EnsureUser indicates an expected call of EnsureUser.
+func (mr *MockUserMockRecorder) EnsureUser(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnsureUser", reflect.TypeOf((*MockUser)(nil).EnsureUser), arg0, arg1, arg2, arg3)
+}
+
+// GetUserByEmail mocks base method.
+func (m *MockUser) GetUserByEmail(arg0 context.Context, arg1 string) (*models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserByEmail", arg0, arg1)
+	ret0, _ := ret[0].(*models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserByEmail indicates an expected call of GetUserByEmail.
+func (mr *MockUserMockRecorder) GetUserByEmail(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByEmail", reflect.TypeOf((*MockUser)(nil).GetUserByEmail), arg0, arg1)
+}
+
+// GetUserByUsername mocks base method.
+func (m *MockUser) GetUserByUsername(arg0 context.Context, arg1 string) (*models.User, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserByUsername", arg0, arg1)
+	ret0, _ := ret[0].(*models.User)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetUserByUsername indicates an expected call of GetUserByUsername.
+func (mr *MockUserMockRecorder) GetUserByUsername(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddUser", reflect.TypeOf((*MockUser)(nil).AddUser), arg0, arg1, arg2, arg3, arg4)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByUsername", reflect.TypeOf((*MockUser)(nil).GetUserByUsername), arg0, arg1)
 }
 
 // GetUserDetails mocks base method.
@@ -64,3 +141,62 @@ func (mr *MockUserMockRecorder) GetUserDetails(arg0, arg1 interface{}) *gomock.C
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserDetails", reflect.TypeOf((*MockUser)(nil).GetUserDetails), arg0, arg1)
 }
+
+// RequestEmailVerification mocks base method.
+func (m *MockUser) RequestEmailVerification(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequestEmailVerification", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RequestEmailVerification indicates an expected call of RequestEmailVerification.
+func (mr *MockUserMockRecorder) RequestEmailVerification(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequestEmailVerification", reflect.TypeOf((*MockUser)(nil).RequestEmailVerification), arg0, arg1)
+}
+
+// UpdateProfileImageURL mocks base method.
+func (m *MockUser) UpdateProfileImageURL(arg0 context.Context, arg1, arg2 string) (*models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateProfileImageURL", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateProfileImageURL indicates an expected call of UpdateProfileImageURL.
+func (mr *MockUserMockRecorder) UpdateProfileImageURL(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateProfileImageURL", reflect.TypeOf((*MockUser)(nil).UpdateProfileImageURL), arg0, arg1, arg2)
+}
+
+// UpdateUser mocks base method.
+func (m *MockUser) UpdateUser(arg0 context.Context, arg1 string, arg2, arg3, arg4, arg5, arg6 *string) (*models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUser", arg0, arg1, arg2, arg3, arg4, arg5, arg6)
+	ret0, _ := ret[0].(*models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateUser indicates an expected call of UpdateUser.
+func (mr *MockUserMockRecorder) UpdateUser(arg0, arg1, arg2, arg3, arg4, arg5, arg6 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUser", reflect.TypeOf((*MockUser)(nil).UpdateUser), arg0, arg1, arg2, arg3, arg4, arg5, arg6)
+}
+
+// VerifyEmail mocks base method.
+func (m *MockUser) VerifyEmail(arg0 context.Context, arg1 string) (*models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyEmail", arg0, arg1)
+	ret0, _ := ret[0].(*models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VerifyEmail indicates an expected call of VerifyEmail.
+func (mr *MockUserMockRecorder) VerifyEmail(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyEmail", reflect.TypeOf((*MockUser)(nil).VerifyEmail), arg0, arg1)
+}
@@ -0,0 +1,268 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/services/users/repositories/user.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/services/users/repositories/user.go -destination=mocks/mock_users_repository.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	models "github.com/weeb-vip/user-service/internal/services/users/models"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockUsersRepository is a mock of UsersRepository interface.
+type MockUsersRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockUsersRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockUsersRepositoryMockRecorder is the mock recorder for MockUsersRepository.
+type MockUsersRepositoryMockRecorder struct {
+	mock *MockUsersRepository
+}
+
+// NewMockUsersRepository creates a new mock instance.
+func NewMockUsersRepository(ctrl *gomock.Controller) *MockUsersRepository {
+	mock := &MockUsersRepository{ctrl: ctrl}
+	mock.recorder = &MockUsersRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUsersRepository) EXPECT() *MockUsersRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AddUser mocks base method.
+func (m *MockUsersRepository) AddUser(ctx context.Context, username, userID, firstName, lastName, language string, email *string) (*models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddUser", ctx, username, userID, firstName, lastName, language, email)
+	ret0, _ := ret[0].(*models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddUser indicates an expected call of AddUser.
+func (mr *MockUsersRepositoryMockRecorder) AddUser(ctx, username, userID, firstName, lastName, language, email any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddUser", reflect.TypeOf((*MockUsersRepository)(nil).AddUser), ctx, username, userID, firstName, lastName, language, email)
+}
+
+// BackfillNormalizedEmails mocks base method.
+func (m *MockUsersRepository) BackfillNormalizedEmails(ctx context.Context) (int, []string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BackfillNormalizedEmails", ctx)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].([]string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// BackfillNormalizedEmails indicates an expected call of BackfillNormalizedEmails.
+func (mr *MockUsersRepositoryMockRecorder) BackfillNormalizedEmails(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BackfillNormalizedEmails", reflect.TypeOf((*MockUsersRepository)(nil).BackfillNormalizedEmails), ctx)
+}
+
+// BackfillNormalizedUsernames mocks base method.
+func (m *MockUsersRepository) BackfillNormalizedUsernames(ctx context.Context) (int, []string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BackfillNormalizedUsernames", ctx)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].([]string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// BackfillNormalizedUsernames indicates an expected call of BackfillNormalizedUsernames.
+func (mr *MockUsersRepositoryMockRecorder) BackfillNormalizedUsernames(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BackfillNormalizedUsernames", reflect.TypeOf((*MockUsersRepository)(nil).BackfillNormalizedUsernames), ctx)
+}
+
+// DeleteUser mocks base method.
+func (m *MockUsersRepository) DeleteUser(ctx context.Context, username string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUser", ctx, username)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteUser indicates an expected call of DeleteUser.
+func (mr *MockUsersRepositoryMockRecorder) DeleteUser(ctx, username any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUser", reflect.TypeOf((*MockUsersRepository)(nil).DeleteUser), ctx, username)
+}
+
+// DeleteUserByID mocks base method.
+func (m *MockUsersRepository) DeleteUserByID(ctx context.Context, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUserByID", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteUserByID indicates an expected call of DeleteUserByID.
+func (mr *MockUsersRepositoryMockRecorder) DeleteUserByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUserByID", reflect.TypeOf((*MockUsersRepository)(nil).DeleteUserByID), ctx, id)
+}
+
+// FindTakenUsernames mocks base method.
+func (m *MockUsersRepository) FindTakenUsernames(ctx context.Context, usernames []string, releasedSince time.Time) (map[string]bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindTakenUsernames", ctx, usernames, releasedSince)
+	ret0, _ := ret[0].(map[string]bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindTakenUsernames indicates an expected call of FindTakenUsernames.
+func (mr *MockUsersRepositoryMockRecorder) FindTakenUsernames(ctx, usernames, releasedSince any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindTakenUsernames", reflect.TypeOf((*MockUsersRepository)(nil).FindTakenUsernames), ctx, usernames, releasedSince)
+}
+
+// GetLatestUsernameChange mocks base method.
+func (m *MockUsersRepository) GetLatestUsernameChange(ctx context.Context, userID string) (*models.UsernameHistory, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLatestUsernameChange", ctx, userID)
+	ret0, _ := ret[0].(*models.UsernameHistory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLatestUsernameChange indicates an expected call of GetLatestUsernameChange.
+func (mr *MockUsersRepositoryMockRecorder) GetLatestUsernameChange(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLatestUsernameChange", reflect.TypeOf((*MockUsersRepository)(nil).GetLatestUsernameChange), ctx, userID)
+}
+
+// GetLatestUsernameRelease mocks base method.
+func (m *MockUsersRepository) GetLatestUsernameRelease(ctx context.Context, username string) (*models.UsernameHistory, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLatestUsernameRelease", ctx, username)
+	ret0, _ := ret[0].(*models.UsernameHistory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLatestUsernameRelease indicates an expected call of GetLatestUsernameRelease.
+func (mr *MockUsersRepositoryMockRecorder) GetLatestUsernameRelease(ctx, username any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLatestUsernameRelease", reflect.TypeOf((*MockUsersRepository)(nil).GetLatestUsernameRelease), ctx, username)
+}
+
+// GetUserByEmail mocks base method.
+func (m *MockUsersRepository) GetUserByEmail(ctx context.Context, email string) (*models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserByEmail", ctx, email)
+	ret0, _ := ret[0].(*models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserByEmail indicates an expected call of GetUserByEmail.
+func (mr *MockUsersRepositoryMockRecorder) GetUserByEmail(ctx, email any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByEmail", reflect.TypeOf((*MockUsersRepository)(nil).GetUserByEmail), ctx, email)
+}
+
+// GetUserById mocks base method.
+func (m *MockUsersRepository) GetUserById(ctx context.Context, id string) (*models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserById", ctx, id)
+	ret0, _ := ret[0].(*models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserById indicates an expected call of GetUserById.
+func (mr *MockUsersRepositoryMockRecorder) GetUserById(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserById", reflect.TypeOf((*MockUsersRepository)(nil).GetUserById), ctx, id)
+}
+
+// GetUserByUsername mocks base method.
+func (m *MockUsersRepository) GetUserByUsername(ctx context.Context, username string) (*models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserByUsername", ctx, username)
+	ret0, _ := ret[0].(*models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserByUsername indicates an expected call of GetUserByUsername.
+func (mr *MockUsersRepositoryMockRecorder) GetUserByUsername(ctx, username any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByUsername", reflect.TypeOf((*MockUsersRepository)(nil).GetUserByUsername), ctx, username)
+}
+
+// MarkEmailVerified mocks base method.
+func (m *MockUsersRepository) MarkEmailVerified(ctx context.Context, id, tokenHash string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkEmailVerified", ctx, id, tokenHash)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkEmailVerified indicates an expected call of MarkEmailVerified.
+func (mr *MockUsersRepositoryMockRecorder) MarkEmailVerified(ctx, id, tokenHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkEmailVerified", reflect.TypeOf((*MockUsersRepository)(nil).MarkEmailVerified), ctx, id, tokenHash)
+}
+
+// SetEmailVerificationToken mocks base method.
+func (m *MockUsersRepository) SetEmailVerificationToken(ctx context.Context, id, email, tokenHash string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetEmailVerificationToken", ctx, id, email, tokenHash)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetEmailVerificationToken indicates an expected call of SetEmailVerificationToken.
+func (mr *MockUsersRepositoryMockRecorder) SetEmailVerificationToken(ctx, id, email, tokenHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetEmailVerificationToken", reflect.TypeOf((*MockUsersRepository)(nil).SetEmailVerificationToken), ctx, id, email, tokenHash)
+}
+
+// UpdateProfileImageURL mocks base method.
+func (m *MockUsersRepository) UpdateProfileImageURL(ctx context.Context, id, profileImageURL string) (*models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateProfileImageURL", ctx, id, profileImageURL)
+	ret0, _ := ret[0].(*models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateProfileImageURL indicates an expected call of UpdateProfileImageURL.
+func (mr *MockUsersRepositoryMockRecorder) UpdateProfileImageURL(ctx, id, profileImageURL any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateProfileImageURL", reflect.TypeOf((*MockUsersRepository)(nil).UpdateProfileImageURL), ctx, id, profileImageURL)
+}
+
+// UpdateUser mocks base method.
+func (m *MockUsersRepository) UpdateUser(ctx context.Context, id string, username, firstName, lastName, language, email *string) (*models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUser", ctx, id, username, firstName, lastName, language, email)
+	ret0, _ := ret[0].(*models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateUser indicates an expected call of UpdateUser.
+func (mr *MockUsersRepositoryMockRecorder) UpdateUser(ctx, id, username, firstName, lastName, language, email any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUser", reflect.TypeOf((*MockUsersRepository)(nil).UpdateUser), ctx, id, username, firstName, lastName, language, email)
+}
@@ -2,7 +2,12 @@ package user
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"github.com/weeb-vip/user-service/internal/db"
+	"github.com/weeb-vip/user-service/internal/eventing"
+	"github.com/weeb-vip/user-service/internal/health"
+	"github.com/weeb-vip/user-service/internal/lifecycle"
 	"github.com/weeb-vip/user-service/internal/logger"
 	"github.com/weeb-vip/user-service/metrics"
 	"net/http"
@@ -16,18 +21,38 @@ import (
 	"github.com/weeb-vip/user-service/http/middleware"
 	"github.com/weeb-vip/user-service/internal/jwt"
 	"github.com/weeb-vip/user-service/internal/keypair"
+	"github.com/weeb-vip/user-service/internal/keypair/keystore"
 	"github.com/weeb-vip/user-service/internal/publishkey"
+	"github.com/weeb-vip/user-service/internal/storage/minio"
 
 	"github.com/99designs/gqlgen/graphql/playground"
 )
 
 const minKeyValidityDurationMinutes = 5
 
+// keyFreshnessRotations is how many rotations can fail in a row before the service reports
+// itself unhealthy.
+const keyFreshnessRotations = 3
+
+// keyStoreChecksPerRotation is how often per rotation interval replicas sharing a key store check
+// it for a new key.
+const keyStoreChecksPerRotation = 5
+
 func StartServer() error { // nolint
-	return StartServerWithContext(context.Background())
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := lifecycle.SignalContext(context.Background())
+	defer stop()
+
+	return StartServerWithContext(ctx, lifecycle.New(cfg.ShutdownConfig))
 }
 
-func StartServerWithContext(ctx context.Context) error { // nolint
+// StartServerWithContext serves until ctx is cancelled, then shuts down through shutdown: /readyz
+// starts failing, in-flight requests drain, key rotation stops and the database pool closes.
+func StartServerWithContext(ctx context.Context, shutdown *lifecycle.Lifecycle) error { // nolint
 	cfg, err := config.LoadConfig()
 	if err != nil {
 		return err
@@ -41,8 +66,17 @@ func StartServerWithContext(ctx context.Context) error { // nolint
 	_ = metrics.GetAppMetrics() // Initialize the metrics singleton
 	log.Info().Msg("Metrics initialized successfully")
 
+	shutdown.OnShutdown("database", func(context.Context) error {
+		return db.Close()
+	})
+
 	log.Info().Msg("Loading keys...")
-	rotatingKey, err := getRotatingSigningKey(cfg, ctx)
+	rotationCtx, stopRotation := context.WithCancel(context.WithoutCancel(ctx))
+	shutdown.OnShutdown("key rotation", func(context.Context) error {
+		stopRotation()
+		return nil
+	})
+	rotatingKey, err := getRotatingSigningKey(cfg, rotationCtx)
 	if err != nil {
 		log.Error().Err(err).Msg("Failed to load keys")
 		return err
@@ -67,37 +101,129 @@ func StartServerWithContext(ctx context.Context) error { // nolint
 	router.Handle("/", playground.Handler("GraphQL playground", "/graphql"))
 	router.Handle("/graphql", handlers.BuildRootHandler(jwt.New(rotatingKey)))
 	router.Handle("/metrics", promhttp.Handler())
-	router.Handle("/readyz", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(200) // nolint
-	}))
-	router.Handle("/livez", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(200) // nolint
-	}))
+	router.Handle("/.well-known/jwks.json", jwt.JWKSHandler(rotatingKey))
+	checks := buildHealth(cfg, rotatingKey, shutdown)
+	router.Handle("/readyz", checks.ReadyHandler())
+	router.Handle("/livez", checks.LiveHandler())
+	router.Handle("/healthz", checks.HealthHandler())
+
+	server := &http.Server{Addr: fmt.Sprintf(":%d", cfg.APPConfig.Port), Handler: router} // nolint
+	shutdown.OnShutdown("http server", server.Shutdown)
+	// Registered last so it runs first: load balancers get time to see /readyz fail and stop
+	// routing here before the listener closes
+	drainDelay := time.Duration(cfg.ShutdownConfig.DrainDelaySeconds) * time.Second
+	shutdown.OnShutdown("readiness drain", func(ctx context.Context) error {
+		select {
+		case <-time.After(drainDelay):
+		case <-ctx.Done():
+		}
+		return nil
+	})
 
 	log.Info().Int("port", cfg.APPConfig.Port).Msg("Connect to GraphQL playground")
 
-	return http.ListenAndServe(fmt.Sprintf(":%d", cfg.APPConfig.Port), router) // nolint
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- server.ListenAndServe()
+	}()
+
+	select {
+	case err := <-serveErr:
+		if errors.Is(err, http.ErrServerClosed) {
+			return nil
+		}
+		log.Error().Err(err).Msg("Server stopped")
+		return errors.Join(err, shutdown.Shutdown(context.WithoutCancel(ctx)))
+	case <-ctx.Done():
+	}
+
+	log.Info().Msg("Shutting down server...")
+	err = shutdown.Shutdown(context.WithoutCancel(ctx))
+	log.Info().Msg("Server stopped")
+
+	return err
 }
 
 func getRotatingSigningKey(cfg *config.Config, ctx context.Context) (keypair.RotatingSigningKey, error) {
 	log := logger.FromCtx(ctx)
 
+	algorithm, err := keypair.ParseAlgorithm(cfg.APPConfig.SigningAlgorithm)
+	if err != nil {
+		log.Error().Err(err).Msg("Invalid signing algorithm")
+		return nil, err
+	}
+
+	actualDuration := keyRotationInterval(cfg)
+	options := []keypair.RotatorOption{
+		keypair.WithPublicKeyWindow(cfg.APPConfig.PublicKeyWindow),
+		keypair.WithAlgorithm(algorithm),
+	}
+
+	store, lock, err := keystore.New(cfg.KeyStoreConfig)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to create key store")
+		return nil, err
+	}
+	checkEvery := actualDuration
+	if store != nil {
+		options = append(options, keypair.WithKeyStore(store, lock, actualDuration))
+		// Rotation is due when the stored key gets old, and other replicas have to notice when
+		// the leader replaced it, so check more often than the key rotates
+		checkEvery = getMinimumDuration(actualDuration/keyStoreChecksPerRotation, time.Minute)
+	}
+
 	rotatingKey, err := keypair.NewSigningKeyRotator(
+		ctx,
 		publishkey.NewKeyPublisher(
-			cfg.APPConfig.InternalGraphQLURL).
-			PublishToKeyManagementService)
+			cfg.APPConfig.InternalGraphQLURL,
+			cfg.KeyPublishConfig).
+			PublishToKeyManagementService,
+		options...)
 	if err != nil {
 		log.Error().Err(err).Msg("Failed to create signing key rotator")
 		return nil, err
 	}
 
+	log.Info().Dur("duration", actualDuration).Str("key_store", cfg.KeyStoreConfig.Type).Msg("Starting key rotation in background")
+	rotatingKey.RotateInBackground(ctx, checkEvery)
+
+	return rotatingKey, nil
+}
+
+func keyRotationInterval(cfg *config.Config) time.Duration {
 	requestedDuration := time.Hour * time.Duration(cfg.APPConfig.KeyRollingDurationInHours)
-	actualDuration := getMinimumDuration(requestedDuration, time.Minute*minKeyValidityDurationMinutes)
 
-	log.Info().Dur("duration", actualDuration).Msg("Starting key rotation in background")
-	rotatingKey.RotateInBackground(actualDuration)
+	return getMinimumDuration(requestedDuration, time.Minute*minKeyValidityDurationMinutes)
+}
 
-	return rotatingKey, nil
+// maxKeyAge is how old the signing key may get before liveness fails, so a service that can't
+// rotate gets restarted rather than signing with the same key indefinitely.
+func maxKeyAge(cfg *config.Config) time.Duration {
+	if cfg.HealthConfig.MaxKeyAgeMinutes > 0 {
+		return time.Duration(cfg.HealthConfig.MaxKeyAgeMinutes) * time.Minute
+	}
+
+	return keyFreshnessRotations * keyRotationInterval(cfg)
+}
+
+func buildHealth(cfg *config.Config, rotatingKey keypair.RotatingSigningKey, shutdown *lifecycle.Lifecycle) *health.Health {
+	checks := health.New(
+		time.Duration(cfg.HealthConfig.CheckTimeoutMs)*time.Millisecond,
+		time.Duration(cfg.HealthConfig.CacheMs)*time.Millisecond,
+		shutdown.Draining,
+	)
+	checks.Register(health.Check{Name: "database", Checker: db.Ping})
+	checks.Register(health.Check{Name: "storage", Checker: minio.NewBucketCheck(cfg.MinioConfig)})
+	if cfg.KafkaConfig.ProducerTopic != "" && cfg.KafkaConfig.ProducerTopic != "nil" {
+		checks.Register(health.Check{Name: "kafka", Checker: eventing.NewBrokerCheck(cfg.KafkaConfig)})
+	}
+	checks.Register(health.Check{
+		Name:     "signing_key",
+		Checker:  keypair.NewFreshnessCheck(rotatingKey, maxKeyAge(cfg)),
+		Liveness: true,
+	})
+
+	return checks
 }
 
 func getMinimumDuration(askedDuration time.Duration, minimumDuration time.Duration) time.Duration {